@@ -2,19 +2,21 @@ package main
 
 import (
 	"context"
-	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"runtime"
 
 	"github.com/codeready-toolchain/toolchain-operator/pkg"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/apis"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/controller"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/health"
+	crmetrics "github.com/codeready-toolchain/toolchain-operator/pkg/metrics"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
-	kubemetrics "github.com/operator-framework/operator-sdk/pkg/kube-metrics"
 	"github.com/operator-framework/operator-sdk/pkg/leader"
 	"github.com/operator-framework/operator-sdk/pkg/log/zap"
 	"github.com/operator-framework/operator-sdk/pkg/metrics"
@@ -23,19 +25,19 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
-	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
 )
 
-// Change below variables to serve metrics on different host or port.
-var (
-	metricsHost               = "0.0.0.0"
-	metricsPort         int32 = 8383
-	operatorMetricsPort int32 = 8686
+const (
+	// leaseLockName the name of the lock used by the lease-based leader election
+	leaseLockName = "toolchain-operator-lease"
+	// leaderForLifeLockName the name of the lock used by the leader-for-life election
+	leaderForLifeLockName = "toolchain-operator-lock"
 )
+
 var log = logf.Log.WithName("cmd")
 
 func printVersion() {
@@ -53,8 +55,26 @@ func main() {
 	// controller-runtime)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 
+	// Add the flags of the operator configuration
+	configuration.AddFlags(pflag.CommandLine)
+
 	pflag.Parse()
 
+	// Load the configuration from the flags, the env vars and the optional config file
+	crtConfig, err := configuration.LoadConfig(pflag.CommandLine)
+	if err != nil {
+		// the logger is not set yet
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	// The zap level flag takes precedence over the configured log level
+	if zapLevel := pflag.Lookup("zap-level"); zapLevel != nil && !zapLevel.Changed {
+		if err := zapLevel.Value.Set(crtConfig.GetLogLevel()); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
 	// Use a zap logr.Logger implementation. If none of the zap
 	// flags are configured (or if the zap flag set is not being
 	// used), this defaults to a production zap logger.
@@ -81,17 +101,45 @@ func main() {
 	}
 
 	ctx := context.TODO()
-	// Become the leader before proceeding
-	err = leader.Become(ctx, "toolchain-operator-lock")
-	if err != nil {
-		log.Error(err, "")
-		os.Exit(1)
-	}
 	// Create a new Cmd to provide shared dependencies and start components
-	mgr, err := manager.New(cfg, manager.Options{
+	options := manager.Options{
 		//	Namespace:          namespace, we'll need to build cache to inform from any namespace as Che operator is installing in any ns
-		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
-	})
+		MetricsBindAddress:     fmt.Sprintf("%s:%d", crtConfig.GetMetricsHost(), crtConfig.GetMetricsPort()),
+		HealthProbeBindAddress: fmt.Sprintf("%s:%d", crtConfig.GetMetricsHost(), crtConfig.GetHealthProbePort()),
+	}
+	switch crtConfig.GetLeaderElectionMode() {
+	case configuration.LeaderElectionModeLease:
+		leaseDuration := crtConfig.GetLeaseDuration()
+		renewDeadline := crtConfig.GetRenewDeadline()
+		retryPeriod := crtConfig.GetRetryPeriod()
+		log.Info("Using the lease-based leader election", "LeaseDuration", leaseDuration, "RenewDeadline", renewDeadline, "RetryPeriod", retryPeriod)
+		options.LeaderElection = true
+		options.LeaderElectionID = leaseLockName
+		options.LeaderElectionNamespace = namespace // if empty, then the namespace in which the operator is running is used
+		options.LeaseDuration = &leaseDuration
+		options.RenewDeadline = &renewDeadline
+		options.RetryPeriod = &retryPeriod
+	case configuration.LeaderElectionModeLeaderForLife:
+		log.Info("Using the leader-for-life election")
+		// Serve the probes while waiting for the leadership, since the manager which serves them is only created afterwards
+		// (otherwise the liveness probe would keep restarting the standby replica)
+		listener, err := net.Listen("tcp", options.HealthProbeBindAddress)
+		if err != nil {
+			log.Error(err, "unable to serve the probes")
+			os.Exit(1)
+		}
+		stopProbes := health.DefaultStatus.ServeStandby(listener)
+		// Become the leader before proceeding
+		if err := leader.Become(ctx, leaderForLifeLockName); err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+		if err := stopProbes(); err != nil {
+			log.Error(err, "unable to stop serving the probes")
+			os.Exit(1)
+		}
+	}
+	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		log.Error(err, "")
 		os.Exit(1)
@@ -105,20 +153,30 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup the readiness and liveness probes
+	if err := addHealthChecks(mgr, crtConfig); err != nil {
+		log.Error(err, "unable to set up the health checks")
+		os.Exit(1)
+	}
+
 	log.Info("Setting up all Controllers")
 
 	// Setup all Controllers
-	if err := controller.AddToManager(mgr); err != nil {
+	log.Info("Enabled controllers", "names", crtConfig.GetEnabledControllers())
+	if err := controller.AddToManager(mgr, crtConfig); err != nil {
 		log.Error(err, "error while setting up controllers")
 		os.Exit(1)
 	}
 
-	// After moving from namespace scoped to cluster scoped - Having this issue https://github.com/operator-framework/operator-sdk/issues/1858.
-	//if err = serveCRMetrics(cfg); err != nil {
-	//	log.Info("Could not generate and serve custom resource metrics", "error", err.Error())
-	//}
+	// Serve the custom resource metrics with our own collector, since the operator-sdk's kube-metrics
+	// do not support cluster-scoped resources (see https://github.com/operator-framework/operator-sdk/issues/1858)
+	if err = serveCRMetrics(mgr, crtConfig); err != nil {
+		log.Info("Could not generate and serve custom resource metrics", "error", err.Error())
+	}
 
 	// Add to the below struct any other metrics ports you want to expose.
+	metricsPort := crtConfig.GetMetricsPort()
+	operatorMetricsPort := crtConfig.GetOperatorMetricsPort()
 	servicePorts := []v1.ServicePort{
 		{Port: metricsPort, Name: metrics.OperatorPortName, Protocol: v1.ProtocolTCP, TargetPort: intstr.IntOrString{Type: intstr.Int, IntVal: metricsPort}},
 		{Port: operatorMetricsPort, Name: metrics.CRPortName, Protocol: v1.ProtocolTCP, TargetPort: intstr.IntOrString{Type: intstr.Int, IntVal: operatorMetricsPort}},
@@ -144,18 +202,19 @@ func main() {
 
 	stopChannel := signals.SetupSignalHandler()
 
-	go func() {
-		log.Info("Waiting for cache to be sync'd before creating the Tekton and Che installation resources...")
-		if !mgr.GetCache().WaitForCacheSync(stopChannel) {
-			log.Error(errors.New("timed out waiting for caches to sync"), "")
-			os.Exit(1)
-		}
-
-		if err := pkg.CreateInstallationResources(mgr.GetClient(), mgr.GetScheme(), log); err != nil {
+	// Create the Tekton and Che installation resources once the cache is synced and this replica is the leader
+	err = mgr.Add(manager.RunnableFunc(func(_ <-chan struct{}) error {
+		if err := pkg.CreateInstallationResources(mgr.GetClient(), crtConfig, log); err != nil {
 			log.Error(err, "unable to create toolchain installation resources during startup")
 			os.Exit(1)
 		}
-	}()
+		health.DefaultStatus.MarkInstallationResourcesCreated()
+		return nil
+	}))
+	if err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
 
 	log.Info("Starting the Cmd.")
 
@@ -166,26 +225,26 @@ func main() {
 	}
 }
 
-// serveCRMetrics gets the Operator/CustomResource GVKs and generates metrics based on those types.
-// It serves those metrics on "http://metricsHost:operatorMetricsPort".
-func serveCRMetrics(cfg *rest.Config) error {
-	// Below function returns filtered operator/CustomResource specific GVKs.
-	// For more control override the below GVK list with your own custom logic.
-	filteredGVK, err := k8sutil.GetGVKsFromAddToScheme(apis.AddToScheme)
+// serveCRMetrics adds a runnable to the manager that generates metrics about the CheInstallation and TektonInstallation
+// resources. It serves those metrics on "http://<metrics host>:<operator metrics port>".
+func serveCRMetrics(mgr manager.Manager, crtConfig *configuration.Config) error {
+	server, err := crmetrics.NewCRMetricsServer(mgr.GetClient(), crtConfig.GetMetricsHost(), crtConfig.GetOperatorMetricsPort())
 	if err != nil {
 		return err
 	}
-	// Get the namespace the operator is currently deployed in.
-	operatorNs, err := k8sutil.GetOperatorNamespace()
-	if err != nil {
+	return mgr.Add(server)
+}
+
+// addHealthChecks registers the readiness check (on "/readyz") and the liveness check (on "/healthz")
+// that are served on "http://<metrics host>:<health probe port>".
+func addHealthChecks(mgr manager.Manager, crtConfig *configuration.Config) error {
+	if err := health.DefaultStatus.AddToManager(mgr, func() (bool, error) {
+		return pkg.InstallationResourcesExist(mgr.GetClient(), crtConfig)
+	}); err != nil {
 		return err
 	}
-	// To generate metrics in other namespaces, add the values below.
-	ns := []string{operatorNs}
-	// Generate and serve custom resource specific metrics.
-	err = kubemetrics.GenerateAndServeCRMetrics(cfg, ns, filteredGVK, metricsHost, operatorMetricsPort)
-	if err != nil {
+	if err := mgr.AddReadyzCheck("operator", health.DefaultStatus.Ready); err != nil {
 		return err
 	}
-	return nil
+	return mgr.AddHealthzCheck("reconcile", health.DefaultStatus.Alive)
 }
@@ -6,6 +6,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	orgv1 "github.com/eclipse/che-operator/pkg/apis/org/v1"
+	configv1 "github.com/openshift/api/config/v1"
 	olmv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1"
 	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	config "github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
@@ -21,6 +22,7 @@ func init() {
 	AddToSchemes = append(AddToSchemes, orgv1.SchemeBuilder.AddToScheme)
 	AddToSchemes = append(AddToSchemes, apiextnv1beta1.AddToScheme)
 	AddToSchemes = append(AddToSchemes, config.SchemeBuilder.AddToScheme)
+	AddToSchemes = append(AddToSchemes, configv1.Install)
 }
 
 // AddToScheme adds all Resources to the Scheme
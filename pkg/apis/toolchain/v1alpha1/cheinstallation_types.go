@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -10,10 +11,229 @@ import (
 type CheInstallationSpec struct {
 	// The configuration required for Che operator
 	CheOperatorSpec CheOperator `json:"cheOperatorSpec"`
+
+	// The maximum duration during which the installation may stay in the same phase before it is considered as stalled.
+	// Defaults to the `che.install-timeout` setting of the operator
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Install Timeout"
+	InstallTimeout *metav1.Duration `json:"installTimeout,omitempty"`
+
+	// The action to run when the installation is stalled: `None` (the default) or `DeleteStuckPods`,
+	// which deletes the pods of the CheCluster which are not ready so that they are recreated
+	// +optional
+	StalledRecovery StalledRecoveryAction `json:"stalledRecovery,omitempty"`
+
+	// If true, the finalizers of the resources created by the operator in the namespace of the CodeReady Workspaces operator are cleared
+	// when they block the deletion of the namespace for longer than the `che.namespace-termination-timeout` setting of the operator,
+	// so that the namespace can be recreated
+	// +optional
+	ClearStuckFinalizers bool `json:"clearStuckFinalizers,omitempty"`
+
+	// The CA bundle which is trusted by the Che server. It is provisioned in a ConfigMap in the namespace of the CodeReady Workspaces
+	// operator, which is set as the trust store of the CheCluster
+	// +optional
+	TrustedCABundle *TrustedCABundle `json:"trustedCABundle,omitempty"`
+
+	// The settings of a disconnected (air-gapped) installation
+	// +optional
+	AirGap *CheAirGap `json:"airgap,omitempty"`
+
+	// The CatalogSource which is created by the operator and from which the CodeReady Workspaces operator is installed
+	// (eg: to test a pre-release build). It takes precedence over the CatalogSource of the `airgap` section
+	// +optional
+	CatalogSource *ManagedCatalogSource `json:"catalogSource,omitempty"`
+
+	// The configuration of the pod of the CodeReady Workspaces operator, which is set in the config of its Subscription
+	// +optional
+	OperatorConfig *OperatorConfig `json:"operatorConfig,omitempty"`
+
+	// The ResourceQuota, LimitRange and NetworkPolicies which are applied to the namespace of the CodeReady Workspaces operator
+	// +optional
+	NamespacePolicy *NamespacePolicy `json:"namespacePolicy,omitempty"`
+
+	// The extra labels and annotations which are set on all the resources created for the installation of CodeReady Workspaces
+	// +optional
+	CommonMetadata *CommonMetadata `json:"commonMetadata,omitempty"`
+
+	// The external PostgreSQL database of CodeReady Workspaces. A database is deployed along with CodeReady Workspaces if unset
+	// +optional
+	Database *CheDatabase `json:"database,omitempty"`
+
+	// The external identity provider (Keycloak or RH-SSO) of CodeReady Workspaces. A Keycloak instance is deployed along with
+	// CodeReady Workspaces and integrated with the OpenShift OAuth server if unset
+	// +optional
+	IdentityProvider *CheIdentityProvider `json:"identityProvider,omitempty"`
+
+	// The TLS settings of CodeReady Workspaces. CodeReady Workspaces is served over plain HTTP if unset
+	// +optional
+	TLS *CheTLS `json:"tls,omitempty"`
+}
+
+// CheTLS the TLS settings of CodeReady Workspaces
+// +k8s:openapi-gen=true
+type CheTLS struct {
+	// The secret (of type `kubernetes.io/tls`) which contains the certificate and the private key of CodeReady Workspaces,
+	// in its `tls.crt` and `tls.key` keys. The default certificate of the OpenShift router is used if unset
+	// +optional
+	CertificateSecret *SecretReference `json:"certificateSecret,omitempty"`
+
+	// Whether the certificate is signed by an authority which is not trusted by default (eg: the default certificate of
+	// the router of a demo cluster), in which case it is added to the trust store of the Che server
+	// +optional
+	SelfSignedCert bool `json:"selfSignedCert,omitempty"`
+
+	// The duration before the expiry of the certificate from which the CertificateExpiringSoon condition is set (720h if unset)
+	// +optional
+	ExpiryWarningPeriod *metav1.Duration `json:"expiryWarningPeriod,omitempty"`
+}
+
+// CheIdentityProvider the settings of an external identity provider (Keycloak or RH-SSO)
+// +k8s:openapi-gen=true
+type CheIdentityProvider struct {
+	// The URL of the identity provider, without the `/auth` path (eg: `https://sso.example.com`)
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// The realm of CodeReady Workspaces
+	// +kubebuilder:validation:MinLength=1
+	Realm string `json:"realm"`
+
+	// The ID of the client of CodeReady Workspaces in the realm
+	// +kubebuilder:validation:MinLength=1
+	ClientID string `json:"clientId"`
+
+	// The secret which contains the credentials of the admin of the identity provider, in its `user` and `password` keys.
+	// The changes of the credentials are applied to CodeReady Workspaces
+	AdminCredentialsSecret SecretReference `json:"adminCredentialsSecret"`
+}
+
+// CheDatabase the settings of an external PostgreSQL database
+// +k8s:openapi-gen=true
+type CheDatabase struct {
+	// The hostname of the database server
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// The port of the database server (5432 if unset)
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// The name of the database
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The secret which contains the credentials of the database, in its `user` and `password` keys.
+	// The changes of the credentials (eg: after a rotation) are applied to CodeReady Workspaces
+	CredentialsSecret SecretReference `json:"credentialsSecret"`
+}
+
+// NamespacePolicy the policies which are applied to a namespace
+// +k8s:openapi-gen=true
+type NamespacePolicy struct {
+	// The hard limits of the ResourceQuota of the namespace (eg: `requests.cpu`, `limits.memory`, `persistentvolumeclaims`).
+	// No ResourceQuota is created if unset
+	// +optional
+	Quota corev1.ResourceList `json:"quota,omitempty"`
+
+	// The default resources of the containers, which are set in the LimitRange of the namespace. No LimitRange is created if unset
+	// +optional
+	DefaultLimits *DefaultLimits `json:"defaultLimits,omitempty"`
+
+	// The network isolation of the namespace: `None` (the default) or `Isolated`, which only accepts the traffic from the pods
+	// of the namespace and from the OpenShift router, and the traffic from any namespace to Keycloak
+	// +optional
+	NetworkIsolation NetworkIsolationMode `json:"networkIsolation,omitempty"`
+}
+
+// DefaultLimits the default resources of the containers
+// +k8s:openapi-gen=true
+type DefaultLimits struct {
+	// The default limits of the containers
+	// +optional
+	Limits corev1.ResourceList `json:"limits,omitempty"`
+
+	// The default requests of the containers
+	// +optional
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+}
+
+// NetworkIsolationMode the network isolation of a namespace
+// +kubebuilder:validation:Enum=None;Isolated
+type NetworkIsolationMode string
+
+const (
+	// NoNetworkIsolation the namespace accepts the traffic from any namespace
+	NoNetworkIsolation NetworkIsolationMode = "None"
+	// IsolatedNetwork the namespace only accepts the traffic from its pods, from the OpenShift router, and the traffic to Keycloak
+	IsolatedNetwork NetworkIsolationMode = "Isolated"
+)
+
+// CheAirGap the settings of a disconnected (air-gapped) installation of CodeReady Workspaces
+// +k8s:openapi-gen=true
+type CheAirGap struct {
+	// The mirror CatalogSource from which the CodeReady Workspaces operator is installed, in place of `redhat-operators`.
+	// The Subscription is not created until the CatalogSource is ready
+	// +optional
+	CatalogSource *CatalogSourceReference `json:"catalogSource,omitempty"`
+
+	// The mirror registry from which the default images of the CheCluster are pulled, with an optional organization
+	// (eg: `mirror.example.com:5000/codeready`)
+	// +optional
+	MirrorRegistry string `json:"mirrorRegistry,omitempty"`
+
+	// The images of the Che components, which override the default images and the mirror registry
+	// +optional
+	Images *CheImages `json:"images,omitempty"`
+}
+
+// CheImages the images of the Che components
+// +k8s:openapi-gen=true
+type CheImages struct {
+	// The image of the Che server, without tag
+	// +optional
+	Server string `json:"server,omitempty"`
+
+	// The tag of the image of the Che server
+	// +optional
+	ServerTag string `json:"serverTag,omitempty"`
+
+	// The image of the devfile registry
+	// +optional
+	DevfileRegistry string `json:"devfileRegistry,omitempty"`
+
+	// The image of the plugin registry
+	// +optional
+	PluginRegistry string `json:"pluginRegistry,omitempty"`
+
+	// The image of the identity provider (Keycloak)
+	// +optional
+	IdentityProvider string `json:"identityProvider,omitempty"`
+
+	// The image of the PostgreSQL database
+	// +optional
+	Postgres string `json:"postgres,omitempty"`
+
+	// The image of the jobs which create the sub-paths of the persistent volumes
+	// +optional
+	PvcJobs string `json:"pvcJobs,omitempty"`
 }
 
+// StalledRecoveryAction the action to run when an installation is stalled
+// +kubebuilder:validation:Enum=None;DeleteStuckPods
+type StalledRecoveryAction string
+
+const (
+	// NoRecovery no action is run when the installation is stalled
+	NoRecovery StalledRecoveryAction = "None"
+	// DeleteStuckPodsRecovery the pods of the CheCluster which are not ready are deleted when the installation is stalled
+	DeleteStuckPodsRecovery StalledRecoveryAction = "DeleteStuckPods"
+)
+
 type CheOperator struct {
-	// The namespace where the CodeReady Workspaces operator will be installed
+	// The namespace where the CodeReady Workspaces operator will be installed. It cannot be used by another CheInstallation
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Namespace"
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:label"
@@ -35,9 +255,30 @@ type CheInstallationStatus struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:org.w3:link"
 	CheServerURL string `json:"cheServerURL,omitempty"`
 
+	// The current phase of the installation, while it is in progress
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Phase"
+	Phase string `json:"phase,omitempty"`
+
+	// The last time the installation progressed to a new phase, or the last time it was detected as stalled
+	// +optional
+	LastProgressTime *metav1.Time `json:"lastProgressTime,omitempty"`
+
+	// The expiry time of the TLS certificate of CodeReady Workspaces, if TLS is enabled
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Certificate Expiry"
+	CertificateExpiry *metav1.Time `json:"certificateExpiry,omitempty"`
+
+	// The hash of the settings of the external identity provider and of the version of the secret of its admin credentials, when the
+	// identity provider was last found reachable, so that it is only checked again when they change
+	// +optional
+	IdentityProviderHash string `json:"identityProviderHash,omitempty"`
+
 	// Last known condition of the CodeReady Workspaces  operator installation.
 	// Supported condition types:
-	// CheReady
+	// CheReady, Stalled, CertificateExpiringSoon
 	// +optional
 	// +patchMergeKey=type
 	// +patchStrategy=merge
@@ -0,0 +1,83 @@
+package v1alpha1
+
+import (
+	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CheUserNamespaceSpec defines the desired state of CheUserNamespace
+// +k8s:openapi-gen=true
+type CheUserNamespaceSpec struct {
+	// The name of the user for whom the CodeReady Workspaces namespace is provisioned
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Username"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:label"
+	Username string `json:"username"`
+
+	// The name of the CheInstallation whose CodeReady Workspaces server manages the workspaces of the user.
+	// Defaults to `toolchain-workspaces-installation`
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="CodeReady Workspaces Installation"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	CheInstallation string `json:"cheInstallation,omitempty"`
+}
+
+// CheUserNamespaceStatus defines the observed state of CheUserNamespace
+// +k8s:openapi-gen=true
+type CheUserNamespaceStatus struct {
+	// The namespace in which the workspaces of the user are running
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Namespace"
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes:Namespace"
+	Namespace string `json:"namespace,omitempty"`
+
+	// Last known condition of the user namespace provisioning.
+	// Supported condition types:
+	// CheUserNamespaceReady
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Conditions"
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.x-descriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []toolchainv1alpha1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CheUserNamespace defines the namespace in which the CodeReady Workspaces of a user are running
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=cheusernamespaces,scope=Cluster
+// +kubebuilder:printcolumn:name="Username",type="string",JSONPath=".spec.username"
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".status.namespace"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"CheUserNamespaceReady\")].status"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"CheUserNamespaceReady\")].reason"
+// +kubebuilder:validation:XPreserveUnknownFields
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="CodeReady Workspaces User Namespace"
+// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+type CheUserNamespace struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CheUserNamespaceSpec   `json:"spec,omitempty"`
+	Status CheUserNamespaceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CheUserNamespaceList contains a list of CheUserNamespace
+type CheUserNamespaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CheUserNamespace `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CheUserNamespace{}, &CheUserNamespaceList{})
+}
@@ -7,14 +7,36 @@ import (
 const (
 	// status condition type
 
-	CheReady    toolchainv1alpha1.ConditionType = "CheReady"
-	TektonReady toolchainv1alpha1.ConditionType = "TektonReady"
+	CheReady                toolchainv1alpha1.ConditionType = "CheReady"
+	TektonReady             toolchainv1alpha1.ConditionType = "TektonReady"
+	CheUserNamespaceReady   toolchainv1alpha1.ConditionType = "CheUserNamespaceReady"
+	PipelineNamespacesReady toolchainv1alpha1.ConditionType = "PipelineNamespacesReady"
+	TasksReady              toolchainv1alpha1.ConditionType = "TasksReady"
+	Stalled                 toolchainv1alpha1.ConditionType = "Stalled"
+	CertificateExpiringSoon toolchainv1alpha1.ConditionType = "CertificateExpiringSoon"
 
 	// Status condition reasons
 
-	InstallingReason      = "Installing"
-	TerminatingReason     = "Terminating"
-	FailedToInstallReason = "FailedToInstall"
-	InstalledReason       = "Installed"
-	UnknownReason         = "Unknown"
+	InstallingReason                    = "Installing"
+	TerminatingReason                   = "Terminating"
+	FailedToInstallReason               = "FailedToInstall"
+	InstalledReason                     = "Installed"
+	UnknownReason                       = "Unknown"
+	ProvisioningReason                  = "Provisioning"
+	FailedToProvisionReason             = "FailedToProvision"
+	ProvisionedReason                   = "Provisioned"
+	SyncedReason                        = "Synced"
+	FailedToSyncReason                  = "FailedToSync"
+	InstallTimeoutReason                = "InstallTimeout"
+	ProgressingReason                   = "Progressing"
+	NamespaceTerminatingReason          = "NamespaceTerminating"
+	ConflictReason                      = "Conflict"
+	CatalogSourceNotReadyReason         = "CatalogSourceNotReady"
+	DatabaseConfigInvalidReason         = "DatabaseConfigInvalid"
+	IdentityProviderConfigInvalidReason = "IdentityProviderConfigInvalid"
+	IdentityProviderUnreachableReason   = "IdentityProviderUnreachable"
+	CertificateInvalidReason            = "CertificateInvalid"
+	CertificateExpiringReason           = "CertificateExpiring"
+	CertificateExpiredReason            = "CertificateExpired"
+	CertificateValidReason              = "CertificateValid"
 )
@@ -2,12 +2,251 @@ package v1alpha1
 
 import (
 	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // TektonInstallationSpec defines the desired state of TektonInstallation
 // +k8s:openapi-gen=true
 type TektonInstallationSpec struct {
+	// The namespaces in which the pipeline ServiceAccount, its RBAC and its secrets are provisioned
+	// once OpenShift Pipelines is installed
+	// +optional
+	PipelineNamespaces *PipelineNamespaces `json:"pipelineNamespaces,omitempty"`
+
+	// The bundles of Tasks and ClusterTasks which are applied once OpenShift Pipelines is installed
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	TaskBundles []TaskBundle `json:"taskBundles,omitempty"`
+
+	// The parameters which are applied to the TektonConfig. The operator owns the fields that it sets,
+	// and removes them from the TektonConfig when they are removed from this section
+	// +optional
+	Config *TektonConfigParameters `json:"config,omitempty"`
+
+	// The CA bundle which is trusted by the pipelines. It is provisioned in a ConfigMap in the target namespace of the Tekton components
+	// once OpenShift Pipelines is installed
+	// +optional
+	TrustedCABundle *TrustedCABundle `json:"trustedCABundle,omitempty"`
+
+	// The settings of a disconnected (air-gapped) installation
+	// +optional
+	AirGap *TektonAirGap `json:"airgap,omitempty"`
+
+	// The CatalogSource which is created by the operator and from which the OpenShift Pipelines operator is installed
+	// (eg: to test a pre-release build). It takes precedence over the CatalogSource of the `airgap` section
+	// +optional
+	CatalogSource *ManagedCatalogSource `json:"catalogSource,omitempty"`
+
+	// The configuration of the pod of the OpenShift Pipelines operator, which is set in the config of its Subscription
+	// +optional
+	OperatorConfig *OperatorConfig `json:"operatorConfig,omitempty"`
+
+	// The extra labels and annotations which are set on all the resources created for the installation of OpenShift Pipelines
+	// +optional
+	CommonMetadata *CommonMetadata `json:"commonMetadata,omitempty"`
+}
+
+// TektonAirGap the settings of a disconnected (air-gapped) installation of OpenShift Pipelines
+// +k8s:openapi-gen=true
+type TektonAirGap struct {
+	// The mirror CatalogSource from which the OpenShift Pipelines operator is installed, in place of `redhat-operators`.
+	// The Subscription is not created until the CatalogSource is ready
+	// +optional
+	CatalogSource *CatalogSourceReference `json:"catalogSource,omitempty"`
+}
+
+// ManagedCatalogSource an OLM CatalogSource which is created and owned by the operator, in the `openshift-marketplace` namespace
+// +k8s:openapi-gen=true
+type ManagedCatalogSource struct {
+	// The index image of the operator registry (eg: `quay.io/myorg/crw-index:latest`)
+	Image string `json:"image"`
+
+	// The name of the CatalogSource which is displayed in the console. Defaults to the name of the CatalogSource
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// The interval between the checks of the latest version of the index image (eg: `30m`). The index image is not polled if unset
+	// +optional
+	PollingInterval *metav1.Duration `json:"pollingInterval,omitempty"`
+}
+
+// CommonMetadata the extra labels and annotations which are set on the resources created by the operator for an installation.
+// The labels and annotations of the operator (`provider` and the ones prefixed with `toolchain.openshift.dev/`) can't be overridden.
+// +k8s:openapi-gen=true
+type CommonMetadata struct {
+	// The extra labels (eg: a cost center or the `app.kubernetes.io/*` labels)
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// The extra annotations (eg: `openshift.io/node-selector`)
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// OperatorConfig the configuration of the pod of an operator installed by OLM. The operator owns the config of the Subscription:
+// the settings which are not part of this configuration are removed from the Subscription, except the proxy env vars
+// +k8s:openapi-gen=true
+type OperatorConfig struct {
+	// The compute resources of the operator container
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// The selector of the nodes on which the operator pod is scheduled
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// The tolerations of the operator pod
+	// +optional
+	// +listType=atomic
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// The env vars which are set in the operator container
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// The volumes which are added to the operator pod
+	// +optional
+	// +listType=atomic
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// The volumes which are mounted in the operator container
+	// +optional
+	// +listType=atomic
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+}
+
+// CatalogSourceReference a reference to an OLM CatalogSource
+// +k8s:openapi-gen=true
+type CatalogSourceReference struct {
+	// The namespace of the CatalogSource
+	Namespace string `json:"namespace"`
+
+	// The name of the CatalogSource
+	Name string `json:"name"`
+}
+
+// TektonConfigParameters the parameters which are applied to the TektonConfig
+// +k8s:openapi-gen=true
+type TektonConfigParameters struct {
+	// The namespace in which the Tekton components are installed
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// The pruning of the resources created by the pipelines
+	// +optional
+	Pruner *TektonPruner `json:"pruner,omitempty"`
+
+	// The parameters of the addons (eg: clusterTasks, pipelineTemplates)
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	AddonParams []TektonParam `json:"addonParams,omitempty"`
+}
+
+// TektonPruner defines how the resources created by the pipelines are pruned
+// +k8s:openapi-gen=true
+type TektonPruner struct {
+	// The kinds of resources to prune (eg: taskrun, pipelinerun)
+	// +optional
+	// +listType=set
+	Resources []string `json:"resources,omitempty"`
+
+	// The number of resources to keep
+	// +optional
+	Keep *int32 `json:"keep,omitempty"`
+
+	// The cron schedule of the pruning
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// TektonParam a parameter of a Tekton addon
+// +k8s:openapi-gen=true
+type TektonParam struct {
+	// The name of the parameter
+	Name string `json:"name"`
+
+	// The value of the parameter
+	Value string `json:"value"`
+}
+
+// PipelineNamespaces selects the namespaces in which the pipelines are running
+// +k8s:openapi-gen=true
+type PipelineNamespaces struct {
+	// The label selector of the namespaces (an empty selector is rejected, as it would match all the namespaces)
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Namespace Selector"
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:selector:core:v1:Namespace"
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// The secrets (eg: the image push secrets) which are copied into the selected namespaces
+	// and referenced by the pipeline ServiceAccount
+	// +optional
+	// +listType=atomic
+	Secrets []SecretReference `json:"secrets,omitempty"`
+}
+
+// SecretReference references a secret in a given namespace
+// +k8s:openapi-gen=true
+type SecretReference struct {
+	// The namespace of the secret
+	Namespace string `json:"namespace"`
+
+	// The name of the secret
+	Name string `json:"name"`
+}
+
+// TaskBundle is a set of Tasks and ClusterTasks, which is either embedded in the operator or stored in a ConfigMap.
+// The Tasks which have no namespace are applied in each of the pipeline namespaces.
+// +k8s:openapi-gen=true
+type TaskBundle struct {
+	// The name of the bundle
+	Name string `json:"name"`
+
+	// The name of the bundle embedded in the operator (supported bundles: default)
+	// +optional
+	Embedded string `json:"embedded,omitempty"`
+
+	// The ConfigMap which contains the manifests of the tasks of the bundle, with one or more manifests per key
+	// +optional
+	ConfigMap *ConfigMapReference `json:"configMap,omitempty"`
+}
+
+// ConfigMapReference references a ConfigMap in a given namespace
+// +k8s:openapi-gen=true
+type ConfigMapReference struct {
+	// The namespace of the ConfigMap
+	Namespace string `json:"namespace"`
+
+	// The name of the ConfigMap
+	Name string `json:"name"`
+}
+
+// TrustedCABundle the CA bundle to trust, which is either copied from a ConfigMap or injected by OpenShift
+// +k8s:openapi-gen=true
+type TrustedCABundle struct {
+	// The ConfigMap which holds the CA bundle in its `ca-bundle.crt` key. The bundle is copied whenever the ConfigMap changes
+	// +optional
+	ConfigMap *ConfigMapReference `json:"configMap,omitempty"`
+
+	// If true, the cluster-wide trusted CA bundle is injected by OpenShift in the provisioned ConfigMap, thanks to the
+	// `config.openshift.io/inject-trusted-cabundle` label. Ignored when a ConfigMap is set
+	// +optional
+	InjectClusterBundle bool `json:"injectClusterBundle,omitempty"`
+}
+
+// TaskBundleStatus the version of a bundle of tasks which is applied
+// +k8s:openapi-gen=true
+type TaskBundleStatus struct {
+	// The name of the bundle
+	Name string `json:"name"`
+
+	// The version of the bundle which is applied
+	Version string `json:"version"`
 }
 
 // TektonInstallationStatus defines the observed state of TektonInstallation
@@ -17,9 +256,47 @@ type TektonInstallationStatus struct {
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
 
+	// The namespaces in which the pipeline ServiceAccount, its RBAC and its secrets are provisioned
+	// +optional
+	// +listType=set
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Pipeline Namespaces"
+	PipelineNamespaces []string `json:"pipelineNamespaces,omitempty"`
+
+	// The bundles of tasks which are applied
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Task Bundles"
+	TaskBundles []TaskBundleStatus `json:"taskBundles,omitempty"`
+
+	// The version of Tekton Pipelines which is installed
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Pipelines Version"
+	PipelinesVersion string `json:"pipelinesVersion,omitempty"`
+
+	// The version of the CSV of the OpenShift Pipelines operator which is installed
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Operator Version"
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+
+	// The namespace in which the Tekton components are installed
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Target Namespace"
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// The time at which the current installation of OpenShift Pipelines started. It is used to fail the installation
+	// which does not complete within the configured timeout, and it is reset once the installation completes or fails.
+	// +optional
+	InstallStartTime *metav1.Time `json:"installStartTime,omitempty"`
+
 	// Last known condition of the OpenShift Pipelines operator installation.
 	// Supported condition types:
-	// TektonReady
+	// TektonReady, PipelineNamespacesReady, TasksReady
 	// +optional
 	// +patchMergeKey=type
 	// +patchStrategy=merge
@@ -39,6 +316,7 @@ type TektonInstallationStatus struct {
 // +kubebuilder:resource:path=tektoninstallations,scope=Cluster
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"TektonReady\")].status"
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"TektonReady\")].reason"
+// +kubebuilder:printcolumn:name="Pipelines",type="string",JSONPath=".status.pipelinesVersion"
 // +kubebuilder:validation:XPreserveUnknownFields
 // +operator-sdk:gen-csv:customresourcedefinitions.displayName="OpenShift Pipelines Installation"
 // +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
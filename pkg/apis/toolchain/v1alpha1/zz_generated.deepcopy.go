@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -20,15 +21,109 @@ package v1alpha1
 
 import (
 	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogSourceReference) DeepCopyInto(out *CatalogSourceReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogSourceReference.
+func (in *CatalogSourceReference) DeepCopy() *CatalogSourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogSourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheAirGap) DeepCopyInto(out *CheAirGap) {
+	*out = *in
+	if in.CatalogSource != nil {
+		in, out := &in.CatalogSource, &out.CatalogSource
+		*out = new(CatalogSourceReference)
+		**out = **in
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = new(CheImages)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheAirGap.
+func (in *CheAirGap) DeepCopy() *CheAirGap {
+	if in == nil {
+		return nil
+	}
+	out := new(CheAirGap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheDatabase) DeepCopyInto(out *CheDatabase) {
+	*out = *in
+	out.CredentialsSecret = in.CredentialsSecret
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheDatabase.
+func (in *CheDatabase) DeepCopy() *CheDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(CheDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheIdentityProvider) DeepCopyInto(out *CheIdentityProvider) {
+	*out = *in
+	out.AdminCredentialsSecret = in.AdminCredentialsSecret
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheIdentityProvider.
+func (in *CheIdentityProvider) DeepCopy() *CheIdentityProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(CheIdentityProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheImages) DeepCopyInto(out *CheImages) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheImages.
+func (in *CheImages) DeepCopy() *CheImages {
+	if in == nil {
+		return nil
+	}
+	out := new(CheImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CheInstallation) DeepCopyInto(out *CheInstallation) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -88,6 +183,56 @@ func (in *CheInstallationList) DeepCopyObject() runtime.Object {
 func (in *CheInstallationSpec) DeepCopyInto(out *CheInstallationSpec) {
 	*out = *in
 	out.CheOperatorSpec = in.CheOperatorSpec
+	if in.InstallTimeout != nil {
+		in, out := &in.InstallTimeout, &out.InstallTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TrustedCABundle != nil {
+		in, out := &in.TrustedCABundle, &out.TrustedCABundle
+		*out = new(TrustedCABundle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AirGap != nil {
+		in, out := &in.AirGap, &out.AirGap
+		*out = new(CheAirGap)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CatalogSource != nil {
+		in, out := &in.CatalogSource, &out.CatalogSource
+		*out = new(ManagedCatalogSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OperatorConfig != nil {
+		in, out := &in.OperatorConfig, &out.OperatorConfig
+		*out = new(OperatorConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespacePolicy != nil {
+		in, out := &in.NamespacePolicy, &out.NamespacePolicy
+		*out = new(NamespacePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommonMetadata != nil {
+		in, out := &in.CommonMetadata, &out.CommonMetadata
+		*out = new(CommonMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(CheDatabase)
+		**out = **in
+	}
+	if in.IdentityProvider != nil {
+		in, out := &in.IdentityProvider, &out.IdentityProvider
+		*out = new(CheIdentityProvider)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(CheTLS)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -104,6 +249,14 @@ func (in *CheInstallationSpec) DeepCopy() *CheInstallationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CheInstallationStatus) DeepCopyInto(out *CheInstallationStatus) {
 	*out = *in
+	if in.LastProgressTime != nil {
+		in, out := &in.LastProgressTime, &out.LastProgressTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]toolchainv1alpha1.Condition, len(*in))
@@ -141,7 +294,33 @@ func (in *CheOperator) DeepCopy() *CheOperator {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TektonInstallation) DeepCopyInto(out *TektonInstallation) {
+func (in *CheTLS) DeepCopyInto(out *CheTLS) {
+	*out = *in
+	if in.CertificateSecret != nil {
+		in, out := &in.CertificateSecret, &out.CertificateSecret
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.ExpiryWarningPeriod != nil {
+		in, out := &in.ExpiryWarningPeriod, &out.ExpiryWarningPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheTLS.
+func (in *CheTLS) DeepCopy() *CheTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(CheTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheUserNamespace) DeepCopyInto(out *CheUserNamespace) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -150,6 +329,409 @@ func (in *TektonInstallation) DeepCopyInto(out *TektonInstallation) {
 	return
 }
 
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheUserNamespace.
+func (in *CheUserNamespace) DeepCopy() *CheUserNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(CheUserNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CheUserNamespace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheUserNamespaceList) DeepCopyInto(out *CheUserNamespaceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CheUserNamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheUserNamespaceList.
+func (in *CheUserNamespaceList) DeepCopy() *CheUserNamespaceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CheUserNamespaceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CheUserNamespaceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheUserNamespaceSpec) DeepCopyInto(out *CheUserNamespaceSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheUserNamespaceSpec.
+func (in *CheUserNamespaceSpec) DeepCopy() *CheUserNamespaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CheUserNamespaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheUserNamespaceStatus) DeepCopyInto(out *CheUserNamespaceStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]toolchainv1alpha1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheUserNamespaceStatus.
+func (in *CheUserNamespaceStatus) DeepCopy() *CheUserNamespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CheUserNamespaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommonMetadata) DeepCopyInto(out *CommonMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonMetadata.
+func (in *CommonMetadata) DeepCopy() *CommonMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapReference.
+func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultLimits) DeepCopyInto(out *DefaultLimits) {
+	*out = *in
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultLimits.
+func (in *DefaultLimits) DeepCopy() *DefaultLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedCatalogSource) DeepCopyInto(out *ManagedCatalogSource) {
+	*out = *in
+	if in.PollingInterval != nil {
+		in, out := &in.PollingInterval, &out.PollingInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedCatalogSource.
+func (in *ManagedCatalogSource) DeepCopy() *ManagedCatalogSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedCatalogSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacePolicy) DeepCopyInto(out *NamespacePolicy) {
+	*out = *in
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.DefaultLimits != nil {
+		in, out := &in.DefaultLimits, &out.DefaultLimits
+		*out = new(DefaultLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePolicy.
+func (in *NamespacePolicy) DeepCopy() *NamespacePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfig.
+func (in *OperatorConfig) DeepCopy() *OperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineNamespaces) DeepCopyInto(out *PipelineNamespaces) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]SecretReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineNamespaces.
+func (in *PipelineNamespaces) DeepCopy() *PipelineNamespaces {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineNamespaces)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskBundle) DeepCopyInto(out *TaskBundle) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskBundle.
+func (in *TaskBundle) DeepCopy() *TaskBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskBundleStatus) DeepCopyInto(out *TaskBundleStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskBundleStatus.
+func (in *TaskBundleStatus) DeepCopy() *TaskBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonAirGap) DeepCopyInto(out *TektonAirGap) {
+	*out = *in
+	if in.CatalogSource != nil {
+		in, out := &in.CatalogSource, &out.CatalogSource
+		*out = new(CatalogSourceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonAirGap.
+func (in *TektonAirGap) DeepCopy() *TektonAirGap {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonAirGap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonConfigParameters) DeepCopyInto(out *TektonConfigParameters) {
+	*out = *in
+	if in.Pruner != nil {
+		in, out := &in.Pruner, &out.Pruner
+		*out = new(TektonPruner)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AddonParams != nil {
+		in, out := &in.AddonParams, &out.AddonParams
+		*out = make([]TektonParam, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonConfigParameters.
+func (in *TektonConfigParameters) DeepCopy() *TektonConfigParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonConfigParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonInstallation) DeepCopyInto(out *TektonInstallation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonInstallation.
 func (in *TektonInstallation) DeepCopy() *TektonInstallation {
 	if in == nil {
@@ -204,6 +786,48 @@ func (in *TektonInstallationList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TektonInstallationSpec) DeepCopyInto(out *TektonInstallationSpec) {
 	*out = *in
+	if in.PipelineNamespaces != nil {
+		in, out := &in.PipelineNamespaces, &out.PipelineNamespaces
+		*out = new(PipelineNamespaces)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TaskBundles != nil {
+		in, out := &in.TaskBundles, &out.TaskBundles
+		*out = make([]TaskBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(TektonConfigParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrustedCABundle != nil {
+		in, out := &in.TrustedCABundle, &out.TrustedCABundle
+		*out = new(TrustedCABundle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AirGap != nil {
+		in, out := &in.AirGap, &out.AirGap
+		*out = new(TektonAirGap)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CatalogSource != nil {
+		in, out := &in.CatalogSource, &out.CatalogSource
+		*out = new(ManagedCatalogSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OperatorConfig != nil {
+		in, out := &in.OperatorConfig, &out.OperatorConfig
+		*out = new(OperatorConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommonMetadata != nil {
+		in, out := &in.CommonMetadata, &out.CommonMetadata
+		*out = new(CommonMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -220,6 +844,20 @@ func (in *TektonInstallationSpec) DeepCopy() *TektonInstallationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TektonInstallationStatus) DeepCopyInto(out *TektonInstallationStatus) {
 	*out = *in
+	if in.PipelineNamespaces != nil {
+		in, out := &in.PipelineNamespaces, &out.PipelineNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TaskBundles != nil {
+		in, out := &in.TaskBundles, &out.TaskBundles
+		*out = make([]TaskBundleStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstallStartTime != nil {
+		in, out := &in.InstallStartTime, &out.InstallStartTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]toolchainv1alpha1.Condition, len(*in))
@@ -239,3 +877,66 @@ func (in *TektonInstallationStatus) DeepCopy() *TektonInstallationStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonParam) DeepCopyInto(out *TektonParam) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonParam.
+func (in *TektonParam) DeepCopy() *TektonParam {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonParam)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TektonPruner) DeepCopyInto(out *TektonPruner) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keep != nil {
+		in, out := &in.Keep, &out.Keep
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TektonPruner.
+func (in *TektonPruner) DeepCopy() *TektonPruner {
+	if in == nil {
+		return nil
+	}
+	out := new(TektonPruner)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedCABundle) DeepCopyInto(out *TrustedCABundle) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedCABundle.
+func (in *TrustedCABundle) DeepCopy() *TrustedCABundle {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedCABundle)
+	in.DeepCopyInto(out)
+	return out
+}
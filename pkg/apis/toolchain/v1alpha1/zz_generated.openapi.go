@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -27,12 +28,246 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CatalogSourceReference":   schema_pkg_apis_toolchain_v1alpha1_CatalogSourceReference(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheAirGap":                schema_pkg_apis_toolchain_v1alpha1_CheAirGap(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheDatabase":              schema_pkg_apis_toolchain_v1alpha1_CheDatabase(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheIdentityProvider":      schema_pkg_apis_toolchain_v1alpha1_CheIdentityProvider(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheImages":                schema_pkg_apis_toolchain_v1alpha1_CheImages(ref),
 		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheInstallation":          schema_pkg_apis_toolchain_v1alpha1_CheInstallation(ref),
 		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheInstallationSpec":      schema_pkg_apis_toolchain_v1alpha1_CheInstallationSpec(ref),
 		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheInstallationStatus":    schema_pkg_apis_toolchain_v1alpha1_CheInstallationStatus(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheTLS":                   schema_pkg_apis_toolchain_v1alpha1_CheTLS(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheUserNamespace":         schema_pkg_apis_toolchain_v1alpha1_CheUserNamespace(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheUserNamespaceSpec":     schema_pkg_apis_toolchain_v1alpha1_CheUserNamespaceSpec(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheUserNamespaceStatus":   schema_pkg_apis_toolchain_v1alpha1_CheUserNamespaceStatus(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CommonMetadata":           schema_pkg_apis_toolchain_v1alpha1_CommonMetadata(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.ConfigMapReference":       schema_pkg_apis_toolchain_v1alpha1_ConfigMapReference(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.DefaultLimits":            schema_pkg_apis_toolchain_v1alpha1_DefaultLimits(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.ManagedCatalogSource":     schema_pkg_apis_toolchain_v1alpha1_ManagedCatalogSource(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.NamespacePolicy":          schema_pkg_apis_toolchain_v1alpha1_NamespacePolicy(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.OperatorConfig":           schema_pkg_apis_toolchain_v1alpha1_OperatorConfig(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.PipelineNamespaces":       schema_pkg_apis_toolchain_v1alpha1_PipelineNamespaces(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.SecretReference":          schema_pkg_apis_toolchain_v1alpha1_SecretReference(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TaskBundle":               schema_pkg_apis_toolchain_v1alpha1_TaskBundle(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TaskBundleStatus":         schema_pkg_apis_toolchain_v1alpha1_TaskBundleStatus(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonAirGap":             schema_pkg_apis_toolchain_v1alpha1_TektonAirGap(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonConfigParameters":   schema_pkg_apis_toolchain_v1alpha1_TektonConfigParameters(ref),
 		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonInstallation":       schema_pkg_apis_toolchain_v1alpha1_TektonInstallation(ref),
 		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonInstallationSpec":   schema_pkg_apis_toolchain_v1alpha1_TektonInstallationSpec(ref),
 		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonInstallationStatus": schema_pkg_apis_toolchain_v1alpha1_TektonInstallationStatus(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonParam":              schema_pkg_apis_toolchain_v1alpha1_TektonParam(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonPruner":             schema_pkg_apis_toolchain_v1alpha1_TektonPruner(ref),
+		"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TrustedCABundle":          schema_pkg_apis_toolchain_v1alpha1_TrustedCABundle(ref),
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_CatalogSourceReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CatalogSourceReference a reference to an OLM CatalogSource",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The namespace of the CatalogSource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the CatalogSource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_CheAirGap(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CheAirGap the settings of a disconnected (air-gapped) installation of CodeReady Workspaces",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"catalogSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The mirror CatalogSource from which the CodeReady Workspaces operator is installed, in place of `redhat-operators`. The Subscription is not created until the CatalogSource is ready",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CatalogSourceReference"),
+						},
+					},
+					"mirrorRegistry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The mirror registry from which the default images of the CheCluster are pulled, with an optional organization (eg: `mirror.example.com:5000/codeready`)",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"images": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The images of the Che components, which override the default images and the mirror registry",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheImages"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CatalogSourceReference", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheImages"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_CheDatabase(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CheDatabase the settings of an external PostgreSQL database",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The hostname of the database server",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"port": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The port of the database server (5432 if unset)",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the database",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialsSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The secret which contains the credentials of the database, in its `user` and `password` keys. The changes of the credentials (eg: after a rotation) are applied to CodeReady Workspaces",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.SecretReference"),
+						},
+					},
+				},
+				Required: []string{"host", "name", "credentialsSecret"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.SecretReference"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_CheIdentityProvider(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CheIdentityProvider the settings of an external identity provider (Keycloak or RH-SSO)",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The URL of the identity provider, without the `/auth` path (eg: `https://sso.example.com`)",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"realm": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The realm of CodeReady Workspaces",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clientId": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The ID of the client of CodeReady Workspaces in the realm",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"adminCredentialsSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The secret which contains the credentials of the admin of the identity provider, in its `user` and `password` keys. The changes of the credentials are applied to CodeReady Workspaces",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.SecretReference"),
+						},
+					},
+				},
+				Required: []string{"url", "realm", "clientId", "adminCredentialsSecret"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.SecretReference"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_CheImages(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CheImages the images of the Che components",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"server": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The image of the Che server, without tag",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"serverTag": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The tag of the image of the Che server",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"devfileRegistry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The image of the devfile registry",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pluginRegistry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The image of the plugin registry",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"identityProvider": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The image of the identity provider (Keycloak)",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"postgres": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The image of the PostgreSQL database",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pvcJobs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The image of the jobs which create the sub-paths of the persistent volumes",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -93,12 +328,86 @@ func schema_pkg_apis_toolchain_v1alpha1_CheInstallationSpec(ref common.Reference
 							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheOperator"),
 						},
 					},
+					"installTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The maximum duration during which the installation may stay in the same phase before it is considered as stalled. Defaults to the `che.install-timeout` setting of the operator",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"stalledRecovery": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The action to run when the installation is stalled: `None` (the default) or `DeleteStuckPods`, which deletes the pods of the CheCluster which are not ready so that they are recreated",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clearStuckFinalizers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If true, the finalizers of the resources created by the operator in the namespace of the CodeReady Workspaces operator are cleared when they block the deletion of the namespace for longer than the `che.namespace-termination-timeout` setting of the operator, so that the namespace can be recreated",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"trustedCABundle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The CA bundle which is trusted by the Che server. It is provisioned in a ConfigMap in the namespace of the CodeReady Workspaces operator, which is set as the trust store of the CheCluster",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TrustedCABundle"),
+						},
+					},
+					"airgap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The settings of a disconnected (air-gapped) installation",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheAirGap"),
+						},
+					},
+					"catalogSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The CatalogSource which is created by the operator and from which the CodeReady Workspaces operator is installed (eg: to test a pre-release build). It takes precedence over the CatalogSource of the `airgap` section",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.ManagedCatalogSource"),
+						},
+					},
+					"operatorConfig": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The configuration of the pod of the CodeReady Workspaces operator, which is set in the config of its Subscription",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.OperatorConfig"),
+						},
+					},
+					"namespacePolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The ResourceQuota, LimitRange and NetworkPolicies which are applied to the namespace of the CodeReady Workspaces operator",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.NamespacePolicy"),
+						},
+					},
+					"commonMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The extra labels and annotations which are set on all the resources created for the installation of CodeReady Workspaces",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CommonMetadata"),
+						},
+					},
+					"database": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The external PostgreSQL database of CodeReady Workspaces. A database is deployed along with CodeReady Workspaces if unset",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheDatabase"),
+						},
+					},
+					"identityProvider": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The external identity provider (Keycloak or RH-SSO) of CodeReady Workspaces. A Keycloak instance is deployed along with CodeReady Workspaces and integrated with the OpenShift OAuth server if unset",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheIdentityProvider"),
+						},
+					},
+					"tls": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The TLS settings of CodeReady Workspaces. CodeReady Workspaces is served over plain HTTP if unset",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheTLS"),
+						},
+					},
 				},
 				Required: []string{"cheOperatorSpec"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheOperator"},
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheAirGap", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheDatabase", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheIdentityProvider", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheOperator", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheTLS", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CommonMetadata", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.ManagedCatalogSource", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.NamespacePolicy", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.OperatorConfig", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TrustedCABundle", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
@@ -116,6 +425,32 @@ func schema_pkg_apis_toolchain_v1alpha1_CheInstallationStatus(ref common.Referen
 							Format:      "",
 						},
 					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The current phase of the installation, while it is in progress",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastProgressTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The last time the installation progressed to a new phase, or the last time it was detected as stalled",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"certificateExpiry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The expiry time of the TLS certificate of CodeReady Workspaces, if TLS is enabled",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"identityProviderHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The hash of the settings of the external identity provider and of the version of the secret of its admin credentials, when the identity provider was last found reachable, so that it is only checked again when they change",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"conditions": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -128,7 +463,7 @@ func schema_pkg_apis_toolchain_v1alpha1_CheInstallationStatus(ref common.Referen
 							},
 						},
 						SchemaProps: spec.SchemaProps{
-							Description: "Last known condition of the CodeReady Workspaces  operator installation. Supported condition types: CheReady",
+							Description: "Last known condition of the CodeReady Workspaces  operator installation. Supported condition types: CheReady, Stalled, CertificateExpiringSoon",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -143,15 +478,49 @@ func schema_pkg_apis_toolchain_v1alpha1_CheInstallationStatus(ref common.Referen
 			},
 		},
 		Dependencies: []string{
-			"github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1.Condition"},
+			"github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1.Condition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_toolchain_v1alpha1_TektonInstallation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_toolchain_v1alpha1_CheTLS(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TektonInstallation defines how OpenShift Pipelines (Tekton) operator should be installed",
+				Description: "CheTLS the TLS settings of CodeReady Workspaces",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"certificateSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The secret (of type `kubernetes.io/tls`) which contains the certificate and the private key of CodeReady Workspaces, in its `tls.crt` and `tls.key` keys. The default certificate of the OpenShift router is used if unset",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.SecretReference"),
+						},
+					},
+					"selfSignedCert": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether the certificate is signed by an authority which is not trusted by default (eg: the default certificate of the router of a demo cluster), in which case it is added to the trust store of the Che server",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"expiryWarningPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The duration before the expiry of the certificate from which the CertificateExpiringSoon condition is set (720h if unset)",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.SecretReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_CheUserNamespace(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CheUserNamespace defines the namespace in which the CodeReady Workspaces of a user are running",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -175,40 +544,64 @@ func schema_pkg_apis_toolchain_v1alpha1_TektonInstallation(ref common.ReferenceC
 					},
 					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonInstallationSpec"),
+							Ref: ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheUserNamespaceSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonInstallationStatus"),
+							Ref: ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheUserNamespaceStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonInstallationSpec", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonInstallationStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheUserNamespaceSpec", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CheUserNamespaceStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_toolchain_v1alpha1_TektonInstallationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_toolchain_v1alpha1_CheUserNamespaceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TektonInstallationSpec defines the desired state of TektonInstallation",
+				Description: "CheUserNamespaceSpec defines the desired state of CheUserNamespace",
 				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"username": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the user for whom the CodeReady Workspaces namespace is provisioned",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"cheInstallation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the CheInstallation whose CodeReady Workspaces server manages the workspaces of the user. Defaults to `toolchain-workspaces-installation`",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"username"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_toolchain_v1alpha1_TektonInstallationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_toolchain_v1alpha1_CheUserNamespaceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TektonInstallationStatus defines the observed state of TektonInstallation",
+				Description: "CheUserNamespaceStatus defines the observed state of CheUserNamespace",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The namespace in which the workspaces of the user are running",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"conditions": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -221,7 +614,7 @@ func schema_pkg_apis_toolchain_v1alpha1_TektonInstallationStatus(ref common.Refe
 							},
 						},
 						SchemaProps: spec.SchemaProps{
-							Description: "Last known condition of the OpenShift Pipelines operator installation. Supported condition types: TektonReady",
+							Description: "Last known condition of the user namespace provisioning. Supported condition types: CheUserNamespaceReady",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -239,3 +632,837 @@ func schema_pkg_apis_toolchain_v1alpha1_TektonInstallationStatus(ref common.Refe
 			"github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1.Condition"},
 	}
 }
+
+func schema_pkg_apis_toolchain_v1alpha1_CommonMetadata(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CommonMetadata the extra labels and annotations which are set on the resources created by the operator for an installation. The labels and annotations of the operator (`provider` and the ones prefixed with `toolchain.openshift.dev/`) can't be overridden.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"labels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The extra labels (eg: a cost center or the `app.kubernetes.io/*` labels)",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"annotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The extra annotations (eg: `openshift.io/node-selector`)",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_ConfigMapReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConfigMapReference references a ConfigMap in a given namespace",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The namespace of the ConfigMap",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the ConfigMap",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_DefaultLimits(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DefaultLimits the default resources of the containers",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"limits": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The default limits of the containers",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+					"requests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The default requests of the containers",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_ManagedCatalogSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ManagedCatalogSource an OLM CatalogSource which is created and owned by the operator, in the `openshift-marketplace` namespace",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The index image of the operator registry (eg: `quay.io/myorg/crw-index:latest`)",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"displayName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the CatalogSource which is displayed in the console. Defaults to the name of the CatalogSource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pollingInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The interval between the checks of the latest version of the index image (eg: `30m`). The index image is not polled if unset",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"image"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_NamespacePolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NamespacePolicy the policies which are applied to a namespace",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"quota": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The hard limits of the ResourceQuota of the namespace (eg: `requests.cpu`, `limits.memory`, `persistentvolumeclaims`). No ResourceQuota is created if unset",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+					"defaultLimits": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The default resources of the containers, which are set in the LimitRange of the namespace. No LimitRange is created if unset",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.DefaultLimits"),
+						},
+					},
+					"networkIsolation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The network isolation of the namespace: `None` (the default) or `Isolated`, which only accepts the traffic from the pods of the namespace and from the OpenShift router, and the traffic from any namespace to Keycloak",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.DefaultLimits", "k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_OperatorConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OperatorConfig the configuration of the pod of an operator installed by OLM. The operator owns the config of the Subscription: the settings which are not part of this configuration are removed from the Subscription, except the proxy env vars",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The compute resources of the operator container",
+							Ref:         ref("k8s.io/api/core/v1.ResourceRequirements"),
+						},
+					},
+					"nodeSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The selector of the nodes on which the operator pod is scheduled",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"tolerations": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The tolerations of the operator pod",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Toleration"),
+									},
+								},
+							},
+						},
+					},
+					"env": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The env vars which are set in the operator container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.EnvVar"),
+									},
+								},
+							},
+						},
+					},
+					"volumes": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The volumes which are added to the operator pod",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Volume"),
+									},
+								},
+							},
+						},
+					},
+					"volumeMounts": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The volumes which are mounted in the operator container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.VolumeMount"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.Toleration", "k8s.io/api/core/v1.Volume", "k8s.io/api/core/v1.VolumeMount"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_PipelineNamespaces(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PipelineNamespaces selects the namespaces in which the pipelines are running",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The label selector of the namespaces (an empty selector is rejected, as it would match all the namespaces)",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"secrets": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The secrets (eg: the image push secrets) which are copied into the selected namespaces and referenced by the pipeline ServiceAccount",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.SecretReference"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"selector"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.SecretReference", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_SecretReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SecretReference references a secret in a given namespace",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The namespace of the secret",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the secret",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_TaskBundle(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TaskBundle is a set of Tasks and ClusterTasks, which is either embedded in the operator or stored in a ConfigMap. The Tasks which have no namespace are applied in each of the pipeline namespaces.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the bundle",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"embedded": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the bundle embedded in the operator (supported bundles: default)",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"configMap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The ConfigMap which contains the manifests of the tasks of the bundle, with one or more manifests per key",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.ConfigMapReference"),
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.ConfigMapReference"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_TaskBundleStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TaskBundleStatus the version of a bundle of tasks which is applied",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the bundle",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The version of the bundle which is applied",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "version"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_TektonAirGap(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TektonAirGap the settings of a disconnected (air-gapped) installation of OpenShift Pipelines",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"catalogSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The mirror CatalogSource from which the OpenShift Pipelines operator is installed, in place of `redhat-operators`. The Subscription is not created until the CatalogSource is ready",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CatalogSourceReference"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CatalogSourceReference"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_TektonConfigParameters(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TektonConfigParameters the parameters which are applied to the TektonConfig",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"targetNamespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The namespace in which the Tekton components are installed",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pruner": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The pruning of the resources created by the pipelines",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonPruner"),
+						},
+					},
+					"addonParams": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The parameters of the addons (eg: clusterTasks, pipelineTemplates)",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonParam"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonParam", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonPruner"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_TektonInstallation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TektonInstallation defines how OpenShift Pipelines (Tekton) operator should be installed",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonInstallationSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonInstallationStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonInstallationSpec", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonInstallationStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_TektonInstallationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TektonInstallationSpec defines the desired state of TektonInstallation",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pipelineNamespaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The namespaces in which the pipeline ServiceAccount, its RBAC and its secrets are provisioned once OpenShift Pipelines is installed",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.PipelineNamespaces"),
+						},
+					},
+					"taskBundles": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The bundles of Tasks and ClusterTasks which are applied once OpenShift Pipelines is installed",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TaskBundle"),
+									},
+								},
+							},
+						},
+					},
+					"config": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The parameters which are applied to the TektonConfig. The operator owns the fields that it sets, and removes them from the TektonConfig when they are removed from this section",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonConfigParameters"),
+						},
+					},
+					"trustedCABundle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The CA bundle which is trusted by the pipelines. It is provisioned in a ConfigMap in the target namespace of the Tekton components once OpenShift Pipelines is installed",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TrustedCABundle"),
+						},
+					},
+					"airgap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The settings of a disconnected (air-gapped) installation",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonAirGap"),
+						},
+					},
+					"catalogSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The CatalogSource which is created by the operator and from which the OpenShift Pipelines operator is installed (eg: to test a pre-release build). It takes precedence over the CatalogSource of the `airgap` section",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.ManagedCatalogSource"),
+						},
+					},
+					"operatorConfig": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The configuration of the pod of the OpenShift Pipelines operator, which is set in the config of its Subscription",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.OperatorConfig"),
+						},
+					},
+					"commonMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The extra labels and annotations which are set on all the resources created for the installation of OpenShift Pipelines",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CommonMetadata"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.CommonMetadata", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.ManagedCatalogSource", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.OperatorConfig", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.PipelineNamespaces", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TaskBundle", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonAirGap", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TektonConfigParameters", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TrustedCABundle"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_TektonInstallationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TektonInstallationStatus defines the observed state of TektonInstallation",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pipelineNamespaces": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The namespaces in which the pipeline ServiceAccount, its RBAC and its secrets are provisioned",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"taskBundles": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The bundles of tasks which are applied",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TaskBundleStatus"),
+									},
+								},
+							},
+						},
+					},
+					"pipelinesVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The version of Tekton Pipelines which is installed",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"operatorVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The version of the CSV of the OpenShift Pipelines operator which is installed",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"targetNamespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The namespace in which the Tekton components are installed",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"installStartTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The time at which the current installation of OpenShift Pipelines started. It is used to fail the installation which does not complete within the configured timeout, and it is reset once the installation completes or fails.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"type",
+								},
+								"x-kubernetes-list-type":       "map",
+								"x-kubernetes-patch-merge-key": "type",
+								"x-kubernetes-patch-strategy":  "merge",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Last known condition of the OpenShift Pipelines operator installation. Supported condition types: TektonReady, PipelineNamespacesReady, TasksReady",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1.Condition", "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.TaskBundleStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_TektonParam(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TektonParam a parameter of a Tekton addon",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the parameter",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The value of the parameter",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "value"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_TektonPruner(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TektonPruner defines how the resources created by the pipelines are pruned",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"resources": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The kinds of resources to prune (eg: taskrun, pipelinerun)",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"keep": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The number of resources to keep",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"schedule": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The cron schedule of the pruning",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_toolchain_v1alpha1_TrustedCABundle(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TrustedCABundle the CA bundle to trust, which is either copied from a ConfigMap or injected by OpenShift",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"configMap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The ConfigMap which holds the CA bundle in its `ca-bundle.crt` key. The bundle is copied whenever the ConfigMap changes",
+							Ref:         ref("github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.ConfigMapReference"),
+						},
+					},
+					"injectClusterBundle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If true, the cluster-wide trusted CA bundle is injected by OpenShift in the provisioned ConfigMap, thanks to the `config.openshift.io/inject-trusted-cabundle` label. Ignored when a ConfigMap is set",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1.ConfigMapReference"},
+	}
+}
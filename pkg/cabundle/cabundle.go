@@ -0,0 +1,130 @@
+package cabundle
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// ConfigMapName the name of the ConfigMap with the trusted CA bundle, which is provisioned by the operator
+	ConfigMapName = "toolchain-trusted-ca-bundle"
+	// Key the key of the CA bundle in the ConfigMaps
+	Key = "ca-bundle.crt"
+	// InjectLabelKey the key of the label which requests OpenShift to inject the cluster-wide trusted CA bundle in a ConfigMap
+	InjectLabelKey = "config.openshift.io/inject-trusted-cabundle"
+)
+
+// Owner the resource which owns the provisioned ConfigMap
+type Owner interface {
+	metav1.Object
+	runtime.Object
+}
+
+// Ensure provisions the ConfigMap of the given trusted CA bundle in the given namespace, and keeps it in sync with its source:
+// the data is copied from the referenced ConfigMap, or the injection label is set so that OpenShift injects the cluster-wide bundle.
+// The ConfigMap is deleted when the bundle is nil, as well as the ConfigMaps of the same owner which are in other namespaces
+// (eg: when the target namespace changed). The labels and annotations of the given common metadata are set on the ConfigMap.
+func Ensure(logger logr.Logger, cl client.Client, scheme *runtime.Scheme, owner Owner, namespace string, bundle *v1alpha1.TrustedCABundle, metadata *v1alpha1.CommonMetadata) error {
+	if err := deleteOutdated(logger, cl, owner, namespace, bundle); err != nil {
+		return err
+	}
+	if bundle == nil {
+		return nil
+	}
+	expected, err := newConfigMap(cl, owner, namespace, bundle)
+	if err != nil {
+		return err
+	}
+	toolchain.SetCommonMetadata(expected, metadata)
+	if err := controllerutil.SetControllerReference(owner, expected, scheme); err != nil {
+		return err
+	}
+	existing := &corev1.ConfigMap{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: ConfigMapName}, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Creating the ConfigMap of the trusted CA bundle", "ConfigMap.Namespace", namespace)
+		return cl.Create(context.TODO(), expected)
+	}
+	changed := !reflect.DeepEqual(existing.Labels, expected.Labels)
+	existing.Labels = expected.Labels
+	if toolchain.SetCommonMetadata(existing, metadata) {
+		changed = true
+	}
+	if !bundle.InjectClusterBundle || bundle.ConfigMap != nil {
+		// the injected data is owned by OpenShift
+		changed = changed || !reflect.DeepEqual(existing.Data, expected.Data)
+		existing.Data = expected.Data
+	}
+	if !changed {
+		return nil
+	}
+	logger.Info("Updating the ConfigMap of the trusted CA bundle", "ConfigMap.Namespace", namespace)
+	return cl.Update(context.TODO(), existing)
+}
+
+// newConfigMap returns the ConfigMap of the given trusted CA bundle, with the data of its source ConfigMap (if any)
+func newConfigMap(cl client.Reader, owner Owner, namespace string, bundle *v1alpha1.TrustedCABundle) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      ConfigMapName,
+			Labels:    toolchain.LabelsWithOwner(owner.GetName()),
+		},
+	}
+	if bundle.ConfigMap == nil {
+		if bundle.InjectClusterBundle {
+			cm.Labels[InjectLabelKey] = "true"
+		}
+		return cm, nil
+	}
+	source := &corev1.ConfigMap{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: bundle.ConfigMap.Namespace, Name: bundle.ConfigMap.Name}, source); err != nil {
+		return nil, err
+	}
+	data, found := source.Data[Key]
+	if !found {
+		return nil, fmt.Errorf("ConfigMap '%s/%s' has no '%s' key", source.Namespace, source.Name, Key)
+	}
+	cm.Data = map[string]string{Key: data}
+	return cm, nil
+}
+
+// deleteOutdated deletes the ConfigMaps of the trusted CA bundle which are controlled by the given owner, except the one in the given
+// namespace if the bundle is set
+func deleteOutdated(logger logr.Logger, cl client.Client, owner Owner, namespace string, bundle *v1alpha1.TrustedCABundle) error {
+	cms := &corev1.ConfigMapList{}
+	if err := cl.List(context.TODO(), cms, client.MatchingLabels(toolchain.LabelsWithOwner(owner.GetName()))); err != nil {
+		return err
+	}
+	for i := range cms.Items {
+		cm := &cms.Items[i]
+		if cm.Name != ConfigMapName || !metav1.IsControlledBy(cm, owner) || (bundle != nil && cm.Namespace == namespace) {
+			continue
+		}
+		logger.Info("Deleting the ConfigMap of the trusted CA bundle", "ConfigMap.Namespace", cm.Namespace)
+		if err := cl.Delete(context.TODO(), cm); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsSource returns true if the given ConfigMap is the source of the given trusted CA bundle
+func IsSource(bundle *v1alpha1.TrustedCABundle, namespace, name string) bool {
+	return bundle != nil && bundle.ConfigMap != nil && bundle.ConfigMap.Namespace == namespace && bundle.ConfigMap.Name == name
+}
@@ -0,0 +1,172 @@
+package cabundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+	"github.com/codeready-toolchain/toolchain-operator/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes/scheme"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestEnsure(t *testing.T) {
+	require.NoError(t, apis.AddToScheme(scheme.Scheme))
+	logger := logf.Log.WithName("cabundle-test")
+	fromConfigMap := &v1alpha1.TrustedCABundle{ConfigMap: &v1alpha1.ConfigMapReference{Namespace: "config", Name: "custom-ca"}}
+
+	t.Run("should copy the bundle of the source ConfigMap", func(t *testing.T) {
+		// given
+		owner := newOwner()
+		cl := test.NewFakeClient(t, owner, newSourceConfigMap("cert1"))
+
+		// when
+		err := Ensure(logger, cl, scheme.Scheme, owner, "target", fromConfigMap, nil)
+
+		// then
+		require.NoError(t, err)
+		cm := getConfigMap(t, cl, "target")
+		assert.Equal(t, map[string]string{Key: "cert1"}, cm.Data)
+		assert.Equal(t, toolchain.LabelsWithOwner(owner.Name), cm.Labels)
+		assert.True(t, metav1.IsControlledBy(cm, owner))
+
+		t.Run("should update the bundle when the source ConfigMap changes", func(t *testing.T) {
+			// given
+			source := newSourceConfigMap("cert2")
+			require.NoError(t, cl.Update(context.TODO(), source))
+
+			// when
+			err := Ensure(logger, cl, scheme.Scheme, owner, "target", fromConfigMap, nil)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{Key: "cert2"}, getConfigMap(t, cl, "target").Data)
+		})
+
+		t.Run("should move the ConfigMap to the new namespace", func(t *testing.T) {
+			// when
+			err := Ensure(logger, cl, scheme.Scheme, owner, "new-target", fromConfigMap, nil)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{Key: "cert2"}, getConfigMap(t, cl, "new-target").Data)
+			assertNoConfigMap(t, cl, "target")
+		})
+
+		t.Run("should delete the ConfigMap when the bundle is removed", func(t *testing.T) {
+			// when
+			err := Ensure(logger, cl, scheme.Scheme, owner, "new-target", nil, nil)
+
+			// then
+			require.NoError(t, err)
+			assertNoConfigMap(t, cl, "new-target")
+		})
+	})
+
+	t.Run("should label the ConfigMap for the injection of the cluster bundle", func(t *testing.T) {
+		// given
+		owner := newOwner()
+		injected := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "target", Name: ConfigMapName, Labels: toolchain.LabelsWithOwner(owner.Name)},
+			Data:       map[string]string{Key: "injected"},
+		}
+		cl := test.NewFakeClient(t, owner, injected)
+
+		// when
+		err := Ensure(logger, cl, scheme.Scheme, owner, "target", &v1alpha1.TrustedCABundle{InjectClusterBundle: true}, nil)
+
+		// then
+		require.NoError(t, err)
+		cm := getConfigMap(t, cl, "target")
+		assert.Equal(t, "true", cm.Labels[InjectLabelKey])
+		assert.Equal(t, map[string]string{Key: "injected"}, cm.Data) // the injected data is kept
+	})
+
+	t.Run("should not delete the ConfigMap of another owner", func(t *testing.T) {
+		// given
+		owner := newOwner()
+		other := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: ConfigMapName, Labels: toolchain.LabelsWithOwner(owner.Name)},
+		}
+		cl := test.NewFakeClient(t, owner, other)
+
+		// when
+		err := Ensure(logger, cl, scheme.Scheme, owner, "target", nil, nil)
+
+		// then
+		require.NoError(t, err)
+		getConfigMap(t, cl, "other")
+	})
+
+	t.Run("should fail when the source ConfigMap has no bundle", func(t *testing.T) {
+		// given
+		owner := newOwner()
+		source := newSourceConfigMap("cert1")
+		source.Data = map[string]string{"other.crt": "cert1"}
+		cl := test.NewFakeClient(t, owner, source)
+
+		// when
+		err := Ensure(logger, cl, scheme.Scheme, owner, "target", fromConfigMap, nil)
+
+		// then
+		require.EqualError(t, err, "ConfigMap 'config/custom-ca' has no 'ca-bundle.crt' key")
+		assertNoConfigMap(t, cl, "target")
+	})
+
+	t.Run("should fail when the source ConfigMap does not exist", func(t *testing.T) {
+		// given
+		owner := newOwner()
+		cl := test.NewFakeClient(t, owner)
+
+		// when
+		err := Ensure(logger, cl, scheme.Scheme, owner, "target", fromConfigMap, nil)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.IsNotFound(err))
+	})
+}
+
+func TestIsSource(t *testing.T) {
+	bundle := &v1alpha1.TrustedCABundle{ConfigMap: &v1alpha1.ConfigMapReference{Namespace: "config", Name: "custom-ca"}}
+
+	assert.True(t, IsSource(bundle, "config", "custom-ca"))
+	assert.False(t, IsSource(bundle, "other", "custom-ca"))
+	assert.False(t, IsSource(&v1alpha1.TrustedCABundle{InjectClusterBundle: true}, "config", "custom-ca"))
+	assert.False(t, IsSource(nil, "config", "custom-ca"))
+}
+
+func newOwner() *v1alpha1.TektonInstallation {
+	return &v1alpha1.TektonInstallation{
+		ObjectMeta: metav1.ObjectMeta{Name: "installation", UID: uuid.NewUUID()},
+	}
+}
+
+func newSourceConfigMap(bundle string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "config", Name: "custom-ca"},
+		Data:       map[string]string{Key: bundle},
+	}
+}
+
+func getConfigMap(t *testing.T, cl *test.FakeClient, namespace string) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: ConfigMapName}, cm))
+	return cm
+}
+
+func assertNoConfigMap(t *testing.T, cl *test.FakeClient, namespace string) {
+	err := cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: ConfigMapName}, &corev1.ConfigMap{})
+	require.Error(t, err)
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,157 @@
+package catalogsource
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+
+	"github.com/go-logr/logr"
+	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// DefaultName the name of the CatalogSource from which the operators are installed by default
+	DefaultName = "redhat-operators"
+	// DefaultNamespace the namespace of the CatalogSource from which the operators are installed by default
+	DefaultNamespace = "openshift-marketplace"
+	// ReadyState the state of the connection to a CatalogSource which is ready
+	ReadyState = "READY"
+)
+
+// OrDefault returns the given CatalogSource, or the default one if it is nil
+func OrDefault(ref *v1alpha1.CatalogSourceReference) v1alpha1.CatalogSourceReference {
+	if ref == nil {
+		return v1alpha1.CatalogSourceReference{Namespace: DefaultNamespace, Name: DefaultName}
+	}
+	return *ref
+}
+
+// CheckReady checks that the given CatalogSource exists and that its registry is READY.
+// Returns a message which describes why the CatalogSource is not ready, or an empty string if it is ready.
+func CheckReady(cl client.Reader, ref v1alpha1.CatalogSourceReference) (string, error) {
+	catalogSource := &olmv1alpha1.CatalogSource{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, catalogSource); err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Sprintf("CatalogSource '%s/%s' not found", ref.Namespace, ref.Name), nil
+		}
+		return "", err
+	}
+	state := ""
+	if catalogSource.Status.GRPCConnectionState != nil {
+		state = catalogSource.Status.GRPCConnectionState.LastObservedState
+	}
+	if state != ReadyState {
+		return fmt.Sprintf("CatalogSource '%s/%s' is not ready (state: '%s')", ref.Namespace, ref.Name, state), nil
+	}
+	return "", nil
+}
+
+// SetSubscriptionSource sets the given CatalogSource in the given Subscription.
+// Returns true if the CatalogSource of the Subscription changed.
+func SetSubscriptionSource(sub *olmv1alpha1.Subscription, ref v1alpha1.CatalogSourceReference) bool {
+	if sub.Spec.CatalogSource == ref.Name && sub.Spec.CatalogSourceNamespace == ref.Namespace {
+		return false
+	}
+	sub.Spec.CatalogSource = ref.Name
+	sub.Spec.CatalogSourceNamespace = ref.Namespace
+	return true
+}
+
+// Owner the resource which owns the managed CatalogSource
+type Owner interface {
+	metav1.Object
+	runtime.Object
+}
+
+// Ensure creates or updates the CatalogSource with the given name and spec in the `openshift-marketplace` namespace, which is owned
+// by the given owner. The CatalogSources of the owner with another name, or all of them if the spec is nil, are deleted.
+// The labels and annotations of the given common metadata are set on the CatalogSource.
+func Ensure(logger logr.Logger, cl client.Client, scheme *runtime.Scheme, owner Owner, name string, spec *v1alpha1.ManagedCatalogSource, metadata *v1alpha1.CommonMetadata) error {
+	if err := deleteOutdated(logger, cl, owner, name, spec); err != nil {
+		return err
+	}
+	if spec == nil {
+		return nil
+	}
+	expected := newCatalogSource(owner, name, spec)
+	toolchain.SetCommonMetadata(expected, metadata)
+	if err := controllerutil.SetControllerReference(owner, expected, scheme); err != nil {
+		return err
+	}
+	existing := &olmv1alpha1.CatalogSource{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: DefaultNamespace, Name: name}, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Creating the CatalogSource", "CatalogSource.Namespace", DefaultNamespace, "CatalogSource.Name", name)
+		return cl.Create(context.TODO(), expected)
+	}
+	if !metav1.IsControlledBy(existing, owner) {
+		return fmt.Errorf("CatalogSource '%s/%s' is not owned by '%s'", DefaultNamespace, name, owner.GetName())
+	}
+	changed := !reflect.DeepEqual(existing.Spec, expected.Spec) || !reflect.DeepEqual(existing.Labels, expected.Labels)
+	existing.Spec = expected.Spec
+	existing.Labels = expected.Labels
+	if toolchain.SetCommonMetadata(existing, metadata) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	logger.Info("Updating the CatalogSource", "CatalogSource.Namespace", DefaultNamespace, "CatalogSource.Name", name)
+	return cl.Update(context.TODO(), existing)
+}
+
+// newCatalogSource returns a new CatalogSource with the given name, which serves the index image of the given spec
+func newCatalogSource(owner Owner, name string, spec *v1alpha1.ManagedCatalogSource) *olmv1alpha1.CatalogSource {
+	catalogSource := &olmv1alpha1.CatalogSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: DefaultNamespace,
+			Name:      name,
+			Labels:    toolchain.LabelsWithOwner(owner.GetName()),
+		},
+		Spec: olmv1alpha1.CatalogSourceSpec{
+			SourceType:  olmv1alpha1.SourceTypeGrpc,
+			Image:       spec.Image,
+			DisplayName: spec.DisplayName,
+			Publisher:   toolchain.ProviderLabelValue,
+		},
+	}
+	if catalogSource.Spec.DisplayName == "" {
+		catalogSource.Spec.DisplayName = name
+	}
+	if spec.PollingInterval != nil {
+		catalogSource.Spec.UpdateStrategy = &olmv1alpha1.UpdateStrategy{
+			RegistryPoll: &olmv1alpha1.RegistryPoll{Interval: spec.PollingInterval},
+		}
+	}
+	return catalogSource
+}
+
+// deleteOutdated deletes the CatalogSources which are controlled by the given owner, except the one with the given name if the spec is set
+func deleteOutdated(logger logr.Logger, cl client.Client, owner Owner, name string, spec *v1alpha1.ManagedCatalogSource) error {
+	catalogSources := &olmv1alpha1.CatalogSourceList{}
+	if err := cl.List(context.TODO(), catalogSources, client.InNamespace(DefaultNamespace), client.MatchingLabels(toolchain.LabelsWithOwner(owner.GetName()))); err != nil {
+		return err
+	}
+	for i := range catalogSources.Items {
+		catalogSource := &catalogSources.Items[i]
+		if !metav1.IsControlledBy(catalogSource, owner) || (spec != nil && catalogSource.Name == name) {
+			continue
+		}
+		logger.Info("Deleting the CatalogSource", "CatalogSource.Namespace", catalogSource.Namespace, "CatalogSource.Name", catalogSource.Name)
+		if err := cl.Delete(context.TODO(), catalogSource); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
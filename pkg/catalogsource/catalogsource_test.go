@@ -0,0 +1,170 @@
+package catalogsource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/test"
+
+	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes/scheme"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestCheckReady(t *testing.T) {
+	require.NoError(t, apis.AddToScheme(scheme.Scheme))
+	ref := v1alpha1.CatalogSourceReference{Namespace: "openshift-marketplace", Name: "mirror"}
+
+	t.Run("ready", func(t *testing.T) {
+		// given
+		cl := test.NewFakeClient(t, test.NewCatalogSource(ref.Namespace, ref.Name, ReadyState))
+
+		// when
+		msg, err := CheckReady(cl, ref)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, msg)
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		// given
+		cl := test.NewFakeClient(t, test.NewCatalogSource(ref.Namespace, ref.Name, "TRANSIENT_FAILURE"))
+
+		// when
+		msg, err := CheckReady(cl, ref)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "CatalogSource 'openshift-marketplace/mirror' is not ready (state: 'TRANSIENT_FAILURE')", msg)
+	})
+
+	t.Run("no connection state yet", func(t *testing.T) {
+		// given
+		cl := test.NewFakeClient(t, test.NewCatalogSource(ref.Namespace, ref.Name, ""))
+
+		// when
+		msg, err := CheckReady(cl, ref)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "CatalogSource 'openshift-marketplace/mirror' is not ready (state: '')", msg)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		// given
+		cl := test.NewFakeClient(t)
+
+		// when
+		msg, err := CheckReady(cl, ref)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "CatalogSource 'openshift-marketplace/mirror' not found", msg)
+	})
+}
+
+func TestSetSubscriptionSource(t *testing.T) {
+	// given
+	sub := &olmv1alpha1.Subscription{Spec: &olmv1alpha1.SubscriptionSpec{CatalogSource: DefaultName, CatalogSourceNamespace: DefaultNamespace}}
+
+	// when
+	changed := SetSubscriptionSource(sub, v1alpha1.CatalogSourceReference{Namespace: "mirrors", Name: "mirror"})
+	unchanged := SetSubscriptionSource(sub, v1alpha1.CatalogSourceReference{Namespace: "mirrors", Name: "mirror"})
+
+	// then
+	assert.True(t, changed)
+	assert.False(t, unchanged)
+	assert.Equal(t, "mirror", sub.Spec.CatalogSource)
+	assert.Equal(t, "mirrors", sub.Spec.CatalogSourceNamespace)
+}
+
+func TestOrDefault(t *testing.T) {
+	assert.Equal(t, v1alpha1.CatalogSourceReference{Namespace: DefaultNamespace, Name: DefaultName}, OrDefault(nil))
+	assert.Equal(t, v1alpha1.CatalogSourceReference{Namespace: "mirrors", Name: "mirror"}, OrDefault(&v1alpha1.CatalogSourceReference{Namespace: "mirrors", Name: "mirror"}))
+}
+
+func TestEnsure(t *testing.T) {
+	require.NoError(t, apis.AddToScheme(scheme.Scheme))
+	logger := logf.Log.WithName("catalogsource-test")
+	owner := &v1alpha1.TektonInstallation{
+		ObjectMeta: metav1.ObjectMeta{Name: "installation", UID: uuid.NewUUID()},
+	}
+
+	t.Run("should create the CatalogSource", func(t *testing.T) {
+		// given
+		cl := test.NewFakeClient(t, owner)
+
+		// when
+		err := Ensure(logger, cl, scheme.Scheme, owner, "pipelines-installation", &v1alpha1.ManagedCatalogSource{Image: "quay.io/qe/pipelines-index:nightly"}, nil)
+
+		// then
+		require.NoError(t, err)
+		catalogSource := getCatalogSource(t, cl, "pipelines-installation")
+		assert.Equal(t, olmv1alpha1.CatalogSourceSpec{
+			SourceType:  olmv1alpha1.SourceTypeGrpc,
+			Image:       "quay.io/qe/pipelines-index:nightly",
+			DisplayName: "pipelines-installation",
+			Publisher:   "toolchain-operator",
+		}, catalogSource.Spec)
+		assert.True(t, metav1.IsControlledBy(catalogSource, owner))
+
+		t.Run("should update the CatalogSource", func(t *testing.T) {
+			// given
+			spec := &v1alpha1.ManagedCatalogSource{
+				Image:           "quay.io/qe/pipelines-index:1.1",
+				DisplayName:     "Pipelines QE",
+				PollingInterval: &metav1.Duration{Duration: 30 * time.Minute},
+			}
+
+			// when
+			err := Ensure(logger, cl, scheme.Scheme, owner, "pipelines-installation", spec, nil)
+
+			// then
+			require.NoError(t, err)
+			catalogSource := getCatalogSource(t, cl, "pipelines-installation")
+			assert.Equal(t, "quay.io/qe/pipelines-index:1.1", catalogSource.Spec.Image)
+			assert.Equal(t, "Pipelines QE", catalogSource.Spec.DisplayName)
+			require.NotNil(t, catalogSource.Spec.UpdateStrategy)
+			assert.Equal(t, 30*time.Minute, catalogSource.Spec.UpdateStrategy.RegistryPoll.Interval.Duration)
+		})
+
+		t.Run("should delete the CatalogSource when it is removed", func(t *testing.T) {
+			// when
+			err := Ensure(logger, cl, scheme.Scheme, owner, "pipelines-installation", nil, nil)
+
+			// then
+			require.NoError(t, err)
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: DefaultNamespace, Name: "pipelines-installation"}, &olmv1alpha1.CatalogSource{})
+			require.Error(t, err)
+			assert.True(t, errors.IsNotFound(err))
+		})
+	})
+
+	t.Run("should not update a CatalogSource owned by another resource", func(t *testing.T) {
+		// given
+		cl := test.NewFakeClient(t, owner, test.NewCatalogSource(DefaultNamespace, "pipelines-installation", ReadyState))
+
+		// when
+		err := Ensure(logger, cl, scheme.Scheme, owner, "pipelines-installation", &v1alpha1.ManagedCatalogSource{Image: "quay.io/qe/pipelines-index:nightly"}, nil)
+
+		// then
+		require.EqualError(t, err, "CatalogSource 'openshift-marketplace/pipelines-installation' is not owned by 'installation'")
+		assert.Empty(t, getCatalogSource(t, cl, "pipelines-installation").Spec.Image)
+	})
+}
+
+func getCatalogSource(t *testing.T, cl *test.FakeClient, name string) *olmv1alpha1.CatalogSource {
+	catalogSource := &olmv1alpha1.CatalogSource{}
+	require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: DefaultNamespace, Name: name}, catalogSource))
+	return catalogSource
+}
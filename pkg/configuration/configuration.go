@@ -0,0 +1,394 @@
+// Package configuration is in charge of the validation and extraction of all
+// the configuration details from a configuration file, from the environment
+// variables or from the command-line flags.
+package configuration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// prefixes
+const (
+	// EnvPrefix will be used for environment variable name prefixing.
+	EnvPrefix = "TOOLCHAIN_OPERATOR"
+)
+
+// Configuration constants
+const (
+	// varConfigFile the path to the optional config file
+	varConfigFile = "config-file"
+
+	varMetricsHost             = "metrics.host"
+	defaultMetricsHost         = "0.0.0.0"
+	varMetricsPort             = "metrics.port"
+	defaultMetricsPort         = 8383
+	varOperatorMetricsPort     = "metrics.operator-port"
+	defaultOperatorMetricsPort = 8686
+	varHealthProbePort         = "health-probe.port"
+	defaultHealthProbePort     = 8081
+
+	varLeaderElectionMode = "leader-election.mode"
+	varLeaseDuration      = "leader-election.lease-duration"
+	defaultLeaseDuration  = 15 * time.Second
+	varRenewDeadline      = "leader-election.renew-deadline"
+	defaultRenewDeadline  = 10 * time.Second
+	varRetryPeriod        = "leader-election.retry-period"
+	defaultRetryPeriod    = 2 * time.Second
+
+	varCheNamespace                       = "che.namespace"
+	defaultCheNamespace                   = "toolchain-workspaces"
+	varCheChannel                         = "che.channel"
+	defaultCheChannel                     = "latest"
+	varCheStartingCSV                     = "che.starting-csv"
+	defaultCheStartingCSV                 = "crwoperator.v2.0.0"
+	varCheRequeueAfter                    = "che.requeue-after"
+	varCheInstallTimeout                  = "che.install-timeout"
+	defaultCheInstallTimeout              = 30 * time.Minute
+	varCheNamespaceTerminationTimeout     = "che.namespace-termination-timeout"
+	defaultCheNamespaceTerminationTimeout = 5 * time.Minute
+
+	varTektonSubscriptionNamespace     = "tekton.subscription-namespace"
+	defaultTektonSubscriptionNamespace = "openshift-operators"
+	varTektonChannel                   = "tekton.channel"
+	defaultTektonChannel               = "ocp-4.4"
+	varTektonStartingCSV               = "tekton.starting-csv"
+	defaultTektonStartingCSV           = "openshift-pipelines-operator.v1.0.1"
+	varTektonRequeueAfter              = "tekton.requeue-after"
+	varTektonInstallTimeout            = "tekton.install-timeout"
+	defaultTektonInstallTimeout        = 15 * time.Minute
+
+	defaultRequeueAfter = 3 * time.Second
+
+	varEnabledControllers = "controllers.enabled"
+
+	varLogLevel     = "log.level"
+	defaultLogLevel = "info"
+)
+
+// Leader election modes
+const (
+	// LeaderElectionModeLease the mode in which the leadership is held by renewing a lease, allowing a fast
+	// failover when the leader pod is gone
+	LeaderElectionModeLease = "lease"
+	// LeaderElectionModeLeaderForLife the mode in which the leadership is held until the leader pod is garbage collected
+	LeaderElectionModeLeaderForLife = "leader-for-life"
+)
+
+// Controller names
+const (
+	// CheInstallationController the name of the controller in charge of the CheInstallation resources
+	CheInstallationController = "cheinstallation"
+	// TektonInstallationController the name of the controller in charge of the TektonInstallation resources
+	TektonInstallationController = "tektoninstallation"
+	// CheUserNamespaceController the name of the controller in charge of the CheUserNamespace resources
+	CheUserNamespaceController = "cheusernamespace"
+)
+
+// knownControllers the controllers that can be enabled
+var knownControllers = []string{CheInstallationController, TektonInstallationController, CheUserNamespaceController}
+
+// Config encapsulates the Viper configuration registry which stores the
+// configuration data in-memory.
+type Config struct {
+	operator *viper.Viper
+}
+
+// NewConfig returns a new configuration with the default values, which can be overridden by the environment variables
+func NewConfig() *Config {
+	c := Config{
+		operator: viper.New(),
+	}
+	c.operator.SetEnvPrefix(EnvPrefix)
+	c.operator.AutomaticEnv()
+	c.operator.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	c.operator.SetTypeByDefaultValue(true)
+	c.setConfigDefaults()
+	return &c
+}
+
+// AddFlags adds the command-line flags of all the configuration settings to the given flag set.
+// Each flag is named after its configuration key, eg: the `che.namespace` setting has a `--che-namespace` flag
+func AddFlags(flags *pflag.FlagSet) {
+	flags.String(flagName(varConfigFile), "", "The path to the optional YAML config file")
+	flags.String(flagName(varMetricsHost), defaultMetricsHost, "The host on which the metrics and the health probes are served")
+	flags.Int32(flagName(varMetricsPort), defaultMetricsPort, "The port on which the operator metrics are served")
+	flags.Int32(flagName(varOperatorMetricsPort), defaultOperatorMetricsPort, "The port on which the custom resource metrics are served")
+	flags.Int32(flagName(varHealthProbePort), defaultHealthProbePort, "The port on which the readiness and liveness probes are served")
+	flags.String(flagName(varLeaderElectionMode), LeaderElectionModeLease,
+		fmt.Sprintf("The leader election mode: '%s' or '%s'", LeaderElectionModeLease, LeaderElectionModeLeaderForLife))
+	flags.Duration(flagName(varLeaseDuration), defaultLeaseDuration,
+		"The duration that non-leader candidates will wait before attempting to acquire the leadership (lease mode only)")
+	flags.Duration(flagName(varRenewDeadline), defaultRenewDeadline,
+		"The duration that the leader will retry refreshing its leadership before giving up (lease mode only)")
+	flags.Duration(flagName(varRetryPeriod), defaultRetryPeriod,
+		"The duration the candidates should wait between tries of actions (lease mode only)")
+	flags.String(flagName(varCheNamespace), defaultCheNamespace, "The namespace in which the CodeReady Workspaces operator is installed")
+	flags.String(flagName(varCheChannel), defaultCheChannel, "The channel of the CodeReady Workspaces operator subscription")
+	flags.String(flagName(varCheStartingCSV), defaultCheStartingCSV, "The CSV the CodeReady Workspaces installation should start with")
+	flags.Duration(flagName(varCheRequeueAfter), defaultRequeueAfter, "The delay before reconciling again a CheInstallation that is waiting for a resource")
+	flags.Duration(flagName(varCheNamespaceTerminationTimeout), defaultCheNamespaceTerminationTimeout,
+		"The duration after which the finalizers which block the deletion of the CodeReady Workspaces namespace are cleared (if enabled in the CheInstallation)")
+	flags.Duration(flagName(varCheInstallTimeout), defaultCheInstallTimeout, "The default maximum duration of a phase of the CodeReady Workspaces installation before it is considered as stalled")
+	flags.String(flagName(varTektonSubscriptionNamespace), defaultTektonSubscriptionNamespace, "The namespace of the OpenShift Pipelines operator subscription")
+	flags.String(flagName(varTektonChannel), defaultTektonChannel, "The channel of the OpenShift Pipelines operator subscription")
+	flags.String(flagName(varTektonStartingCSV), defaultTektonStartingCSV, "The CSV the OpenShift Pipelines installation should start with")
+	flags.Duration(flagName(varTektonRequeueAfter), defaultRequeueAfter, "The delay before reconciling again a TektonInstallation that is waiting for a resource")
+	flags.Duration(flagName(varTektonInstallTimeout), defaultTektonInstallTimeout, "The maximum duration of the OpenShift Pipelines installation before it is considered as failed")
+	flags.StringSlice(flagName(varEnabledControllers), knownControllers, "The controllers to enable")
+	flags.String(flagName(varLogLevel), defaultLogLevel, "The log level: 'debug', 'info', 'error' or an integer greater than 0")
+}
+
+// LoadConfig loads the configuration from the given command-line flags (if set), from the environment
+// variables (prefixed with `TOOLCHAIN_OPERATOR_`) and from the optional config file, in that order of precedence.
+// The resulting configuration is validated.
+func LoadConfig(flags *pflag.FlagSet) (*Config, error) {
+	c := NewConfig()
+	if flags != nil {
+		if err := c.bindFlags(flags); err != nil {
+			return nil, errors.Wrap(err, "failed to bind the command-line flags")
+		}
+	}
+	if configFile := c.operator.GetString(varConfigFile); configFile != "" {
+		c.operator.SetConfigFile(configFile)
+		if err := c.operator.ReadInConfig(); err != nil {
+			return nil, errors.Wrapf(err, "failed to read the config file '%s'", configFile)
+		}
+	}
+	if err := c.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid configuration")
+	}
+	return c, nil
+}
+
+func (c *Config) bindFlags(flags *pflag.FlagSet) error {
+	for _, key := range append(c.operator.AllKeys(), varConfigFile) {
+		if flag := flags.Lookup(flagName(key)); flag != nil {
+			if err := c.operator.BindPFlag(key, flag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Config) setConfigDefaults() {
+	c.operator.SetDefault(varConfigFile, "")
+	c.operator.SetDefault(varMetricsHost, defaultMetricsHost)
+	c.operator.SetDefault(varMetricsPort, defaultMetricsPort)
+	c.operator.SetDefault(varOperatorMetricsPort, defaultOperatorMetricsPort)
+	c.operator.SetDefault(varHealthProbePort, defaultHealthProbePort)
+	c.operator.SetDefault(varLeaderElectionMode, LeaderElectionModeLease)
+	c.operator.SetDefault(varLeaseDuration, defaultLeaseDuration)
+	c.operator.SetDefault(varRenewDeadline, defaultRenewDeadline)
+	c.operator.SetDefault(varRetryPeriod, defaultRetryPeriod)
+	c.operator.SetDefault(varCheNamespace, defaultCheNamespace)
+	c.operator.SetDefault(varCheChannel, defaultCheChannel)
+	c.operator.SetDefault(varCheStartingCSV, defaultCheStartingCSV)
+	c.operator.SetDefault(varCheRequeueAfter, defaultRequeueAfter)
+	c.operator.SetDefault(varCheInstallTimeout, defaultCheInstallTimeout)
+	c.operator.SetDefault(varCheNamespaceTerminationTimeout, defaultCheNamespaceTerminationTimeout)
+	c.operator.SetDefault(varTektonSubscriptionNamespace, defaultTektonSubscriptionNamespace)
+	c.operator.SetDefault(varTektonChannel, defaultTektonChannel)
+	c.operator.SetDefault(varTektonStartingCSV, defaultTektonStartingCSV)
+	c.operator.SetDefault(varTektonRequeueAfter, defaultRequeueAfter)
+	c.operator.SetDefault(varTektonInstallTimeout, defaultTektonInstallTimeout)
+	c.operator.SetDefault(varEnabledControllers, knownControllers)
+	c.operator.SetDefault(varLogLevel, defaultLogLevel)
+}
+
+// Validate verifies that all the configuration settings are valid
+func (c *Config) Validate() error {
+	var errs []string
+	for _, key := range []string{varMetricsPort, varOperatorMetricsPort, varHealthProbePort} {
+		if port := c.operator.GetInt32(key); port <= 0 || port > 65535 {
+			errs = append(errs, fmt.Sprintf("'%s' must be a valid port number but was %d", key, port))
+		}
+	}
+	if c.GetMetricsPort() == c.GetOperatorMetricsPort() || c.GetMetricsPort() == c.GetHealthProbePort() || c.GetOperatorMetricsPort() == c.GetHealthProbePort() {
+		errs = append(errs, fmt.Sprintf("'%s', '%s' and '%s' must be different", varMetricsPort, varOperatorMetricsPort, varHealthProbePort))
+	}
+	for _, key := range []string{varCheNamespace, varCheChannel, varCheStartingCSV, varTektonSubscriptionNamespace, varTektonChannel, varTektonStartingCSV} {
+		if c.operator.GetString(key) == "" {
+			errs = append(errs, fmt.Sprintf("'%s' must not be empty", key))
+		}
+	}
+	for _, key := range []string{varCheRequeueAfter, varCheInstallTimeout, varCheNamespaceTerminationTimeout, varTektonRequeueAfter, varTektonInstallTimeout, varLeaseDuration, varRenewDeadline, varRetryPeriod} {
+		if c.operator.GetDuration(key) <= 0 {
+			errs = append(errs, fmt.Sprintf("'%s' must be a positive duration", key))
+		}
+	}
+	switch c.GetLeaderElectionMode() {
+	case LeaderElectionModeLease:
+		if c.GetLeaseDuration() <= c.GetRenewDeadline() {
+			errs = append(errs, fmt.Sprintf("'%s' must be greater than '%s'", varLeaseDuration, varRenewDeadline))
+		}
+		if c.GetRenewDeadline() <= c.GetRetryPeriod() {
+			errs = append(errs, fmt.Sprintf("'%s' must be greater than '%s'", varRenewDeadline, varRetryPeriod))
+		}
+	case LeaderElectionModeLeaderForLife:
+	default:
+		errs = append(errs, fmt.Sprintf("'%s' must be '%s' or '%s' but was '%s'", varLeaderElectionMode, LeaderElectionModeLease, LeaderElectionModeLeaderForLife, c.GetLeaderElectionMode()))
+	}
+	for _, name := range c.GetEnabledControllers() {
+		if !contains(knownControllers, name) {
+			errs = append(errs, fmt.Sprintf("unknown controller '%s' in '%s' (known controllers: %s)", name, varEnabledControllers, strings.Join(knownControllers, ", ")))
+		}
+	}
+	if !isValidLogLevel(c.GetLogLevel()) {
+		errs = append(errs, fmt.Sprintf("'%s' must be 'debug', 'info', 'error' or an integer greater than 0 but was '%s'", varLogLevel, c.GetLogLevel()))
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// GetMetricsHost returns the host on which the metrics and the health probes are served
+func (c *Config) GetMetricsHost() string {
+	return c.operator.GetString(varMetricsHost)
+}
+
+// GetMetricsPort returns the port on which the operator metrics are served
+func (c *Config) GetMetricsPort() int32 {
+	return c.operator.GetInt32(varMetricsPort)
+}
+
+// GetOperatorMetricsPort returns the port on which the custom resource metrics are served
+func (c *Config) GetOperatorMetricsPort() int32 {
+	return c.operator.GetInt32(varOperatorMetricsPort)
+}
+
+// GetHealthProbePort returns the port on which the readiness and liveness probes are served
+func (c *Config) GetHealthProbePort() int32 {
+	return c.operator.GetInt32(varHealthProbePort)
+}
+
+// GetLeaderElectionMode returns the leader election mode
+func (c *Config) GetLeaderElectionMode() string {
+	return c.operator.GetString(varLeaderElectionMode)
+}
+
+// GetLeaseDuration returns the duration that non-leader candidates will wait before attempting to acquire the leadership
+func (c *Config) GetLeaseDuration() time.Duration {
+	return c.operator.GetDuration(varLeaseDuration)
+}
+
+// GetRenewDeadline returns the duration that the leader will retry refreshing its leadership before giving up
+func (c *Config) GetRenewDeadline() time.Duration {
+	return c.operator.GetDuration(varRenewDeadline)
+}
+
+// GetRetryPeriod returns the duration the candidates should wait between tries of actions
+func (c *Config) GetRetryPeriod() time.Duration {
+	return c.operator.GetDuration(varRetryPeriod)
+}
+
+// GetCheNamespace returns the namespace in which the CodeReady Workspaces operator is installed
+func (c *Config) GetCheNamespace() string {
+	return c.operator.GetString(varCheNamespace)
+}
+
+// GetCheChannel returns the channel of the CodeReady Workspaces operator subscription
+func (c *Config) GetCheChannel() string {
+	return c.operator.GetString(varCheChannel)
+}
+
+// GetCheStartingCSV returns the CSV the CodeReady Workspaces installation should start with
+func (c *Config) GetCheStartingCSV() string {
+	return c.operator.GetString(varCheStartingCSV)
+}
+
+// GetCheRequeueAfter returns the delay before reconciling again a CheInstallation that is waiting for a resource
+func (c *Config) GetCheRequeueAfter() time.Duration {
+	return c.operator.GetDuration(varCheRequeueAfter)
+}
+
+// GetCheInstallTimeout returns the default maximum duration of a phase of the CodeReady Workspaces installation before it is considered as stalled
+func (c *Config) GetCheInstallTimeout() time.Duration {
+	return c.operator.GetDuration(varCheInstallTimeout)
+}
+
+// GetCheNamespaceTerminationTimeout returns the duration after which the finalizers which block the deletion of the CodeReady Workspaces
+// namespace are cleared (if enabled in the CheInstallation)
+func (c *Config) GetCheNamespaceTerminationTimeout() time.Duration {
+	return c.operator.GetDuration(varCheNamespaceTerminationTimeout)
+}
+
+// GetTektonSubscriptionNamespace returns the namespace of the OpenShift Pipelines operator subscription
+func (c *Config) GetTektonSubscriptionNamespace() string {
+	return c.operator.GetString(varTektonSubscriptionNamespace)
+}
+
+// GetTektonChannel returns the channel of the OpenShift Pipelines operator subscription
+func (c *Config) GetTektonChannel() string {
+	return c.operator.GetString(varTektonChannel)
+}
+
+// GetTektonStartingCSV returns the CSV the OpenShift Pipelines installation should start with
+func (c *Config) GetTektonStartingCSV() string {
+	return c.operator.GetString(varTektonStartingCSV)
+}
+
+// GetTektonRequeueAfter returns the delay before reconciling again a TektonInstallation that is waiting for a resource
+func (c *Config) GetTektonRequeueAfter() time.Duration {
+	return c.operator.GetDuration(varTektonRequeueAfter)
+}
+
+// GetTektonInstallTimeout returns the maximum duration of the OpenShift Pipelines installation before it is considered as failed
+func (c *Config) GetTektonInstallTimeout() time.Duration {
+	return c.operator.GetDuration(varTektonInstallTimeout)
+}
+
+// GetEnabledControllers returns the names of the controllers to enable. The names may be separated by
+// commas or by spaces, eg: `TOOLCHAIN_OPERATOR_CONTROLLERS_ENABLED=cheinstallation,tektoninstallation`
+func (c *Config) GetEnabledControllers() []string {
+	var names []string
+	for _, value := range c.operator.GetStringSlice(varEnabledControllers) {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// IsControllerEnabled returns true if the controller with the given name is enabled
+func (c *Config) IsControllerEnabled(name string) bool {
+	return contains(c.GetEnabledControllers(), name)
+}
+
+// GetLogLevel returns the log level
+func (c *Config) GetLogLevel() string {
+	return c.operator.GetString(varLogLevel)
+}
+
+// flagName returns the name of the command-line flag for the given configuration key
+func flagName(key string) string {
+	return strings.ReplaceAll(key, ".", "-")
+}
+
+func isValidLogLevel(level string) bool {
+	switch level {
+	case "debug", "info", "error":
+		return true
+	}
+	l, err := strconv.Atoi(level)
+	return err == nil && l > 0
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+package configuration_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+
+	t.Run("default values", func(t *testing.T) {
+		// when
+		config, err := configuration.LoadConfig(newFlags(t))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "0.0.0.0", config.GetMetricsHost())
+		assert.Equal(t, int32(8383), config.GetMetricsPort())
+		assert.Equal(t, int32(8686), config.GetOperatorMetricsPort())
+		assert.Equal(t, int32(8081), config.GetHealthProbePort())
+		assert.Equal(t, configuration.LeaderElectionModeLease, config.GetLeaderElectionMode())
+		assert.Equal(t, 15*time.Second, config.GetLeaseDuration())
+		assert.Equal(t, 10*time.Second, config.GetRenewDeadline())
+		assert.Equal(t, 2*time.Second, config.GetRetryPeriod())
+		assert.Equal(t, "toolchain-workspaces", config.GetCheNamespace())
+		assert.Equal(t, "latest", config.GetCheChannel())
+		assert.Equal(t, "crwoperator.v2.0.0", config.GetCheStartingCSV())
+		assert.Equal(t, 3*time.Second, config.GetCheRequeueAfter())
+		assert.Equal(t, 30*time.Minute, config.GetCheInstallTimeout())
+		assert.Equal(t, 5*time.Minute, config.GetCheNamespaceTerminationTimeout())
+		assert.Equal(t, "openshift-operators", config.GetTektonSubscriptionNamespace())
+		assert.Equal(t, "ocp-4.4", config.GetTektonChannel())
+		assert.Equal(t, "openshift-pipelines-operator.v1.0.1", config.GetTektonStartingCSV())
+		assert.Equal(t, 3*time.Second, config.GetTektonRequeueAfter())
+		assert.Equal(t, 15*time.Minute, config.GetTektonInstallTimeout())
+		assert.Equal(t, []string{configuration.CheInstallationController, configuration.TektonInstallationController, configuration.CheUserNamespaceController}, config.GetEnabledControllers())
+		assert.Equal(t, "info", config.GetLogLevel())
+	})
+
+	t.Run("without flags", func(t *testing.T) {
+		// when
+		config, err := configuration.LoadConfig(nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int32(8383), config.GetMetricsPort())
+		assert.True(t, config.IsControllerEnabled(configuration.CheInstallationController))
+		assert.True(t, config.IsControllerEnabled(configuration.TektonInstallationController))
+	})
+
+	t.Run("from flags", func(t *testing.T) {
+		// given
+		flags := newFlags(t, "--metrics-port=9090", "--che-namespace=my-workspaces", "--tekton-requeue-after=10s", "--tekton-install-timeout=5m",
+			"--controllers-enabled=tektoninstallation", "--leader-election-mode=leader-for-life", "--log-level=debug")
+
+		// when
+		config, err := configuration.LoadConfig(flags)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int32(9090), config.GetMetricsPort())
+		assert.Equal(t, "my-workspaces", config.GetCheNamespace())
+		assert.Equal(t, 10*time.Second, config.GetTektonRequeueAfter())
+		assert.Equal(t, 5*time.Minute, config.GetTektonInstallTimeout())
+		assert.Equal(t, []string{configuration.TektonInstallationController}, config.GetEnabledControllers())
+		assert.False(t, config.IsControllerEnabled(configuration.CheInstallationController))
+		assert.Equal(t, configuration.LeaderElectionModeLeaderForLife, config.GetLeaderElectionMode())
+		assert.Equal(t, "debug", config.GetLogLevel())
+	})
+
+	t.Run("from env vars", func(t *testing.T) {
+		// given
+		defer setEnv(t, "TOOLCHAIN_OPERATOR_METRICS_OPERATOR_PORT", "9191")()
+		defer setEnv(t, "TOOLCHAIN_OPERATOR_TEKTON_CHANNEL", "ocp-4.5")()
+		defer setEnv(t, "TOOLCHAIN_OPERATOR_LEADER_ELECTION_LEASE_DURATION", "30s")()
+		defer setEnv(t, "TOOLCHAIN_OPERATOR_CONTROLLERS_ENABLED", "cheinstallation,tektoninstallation")()
+
+		// when
+		config, err := configuration.LoadConfig(newFlags(t))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int32(9191), config.GetOperatorMetricsPort())
+		assert.Equal(t, "ocp-4.5", config.GetTektonChannel())
+		assert.Equal(t, 30*time.Second, config.GetLeaseDuration())
+		assert.Equal(t, []string{configuration.CheInstallationController, configuration.TektonInstallationController}, config.GetEnabledControllers())
+	})
+
+	t.Run("from config file", func(t *testing.T) {
+		// given
+		configFile := newConfigFile(t, `
+che:
+  starting-csv: crwoperator.v2.1.0
+  requeue-after: 5s
+health-probe:
+  port: 9292
+`)
+		defer os.Remove(configFile)
+
+		t.Run("with flag", func(t *testing.T) {
+			// when
+			config, err := configuration.LoadConfig(newFlags(t, "--config-file="+configFile))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, "crwoperator.v2.1.0", config.GetCheStartingCSV())
+			assert.Equal(t, 5*time.Second, config.GetCheRequeueAfter())
+			assert.Equal(t, int32(9292), config.GetHealthProbePort())
+			assert.Equal(t, "latest", config.GetCheChannel())
+		})
+
+		t.Run("with env var", func(t *testing.T) {
+			// given
+			defer setEnv(t, "TOOLCHAIN_OPERATOR_CONFIG_FILE", configFile)()
+
+			// when
+			config, err := configuration.LoadConfig(newFlags(t))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, "crwoperator.v2.1.0", config.GetCheStartingCSV())
+		})
+
+		t.Run("env var and flag take precedence over config file", func(t *testing.T) {
+			// given
+			defer setEnv(t, "TOOLCHAIN_OPERATOR_CHE_STARTING_CSV", "crwoperator.v2.2.0")()
+
+			// when
+			config, err := configuration.LoadConfig(newFlags(t, "--config-file="+configFile, "--che-requeue-after=1s"))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, "crwoperator.v2.2.0", config.GetCheStartingCSV())
+			assert.Equal(t, 1*time.Second, config.GetCheRequeueAfter())
+		})
+
+		t.Run("fails when config file is missing", func(t *testing.T) {
+			// when
+			_, err := configuration.LoadConfig(newFlags(t, "--config-file=/does/not/exist.yaml"))
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to read the config file '/does/not/exist.yaml'")
+		})
+	})
+}
+
+func TestValidate(t *testing.T) {
+
+	t.Run("invalid settings", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			args        []string
+			expectedErr string
+		}{
+			"port out of range": {
+				args:        []string{"--metrics-port=70000"},
+				expectedErr: "'metrics.port' must be a valid port number but was 70000",
+			},
+			"same ports": {
+				args:        []string{"--health-probe-port=8383"},
+				expectedErr: "'metrics.port', 'metrics.operator-port' and 'health-probe.port' must be different",
+			},
+			"empty namespace": {
+				args:        []string{"--che-namespace="},
+				expectedErr: "'che.namespace' must not be empty",
+			},
+			"negative requeue": {
+				args:        []string{"--tekton-requeue-after=-1s"},
+				expectedErr: "'tekton.requeue-after' must be a positive duration",
+			},
+			"zero install timeout": {
+				args:        []string{"--tekton-install-timeout=0s"},
+				expectedErr: "'tekton.install-timeout' must be a positive duration",
+			},
+			"unknown leader election mode": {
+				args:        []string{"--leader-election-mode=unknown"},
+				expectedErr: "'leader-election.mode' must be 'lease' or 'leader-for-life' but was 'unknown'",
+			},
+			"renew deadline greater than lease duration": {
+				args:        []string{"--leader-election-renew-deadline=20s"},
+				expectedErr: "'leader-election.lease-duration' must be greater than 'leader-election.renew-deadline'",
+			},
+			"unknown controller": {
+				args:        []string{"--controllers-enabled=cheinstallation,unknown"},
+				expectedErr: "unknown controller 'unknown' in 'controllers.enabled' (known controllers: cheinstallation, tektoninstallation, cheusernamespace)",
+			},
+			"invalid log level": {
+				args:        []string{"--log-level=verbose"},
+				expectedErr: "'log.level' must be 'debug', 'info', 'error' or an integer greater than 0 but was 'verbose'",
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				// when
+				_, err := configuration.LoadConfig(newFlags(t, tc.args...))
+
+				// then
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+			})
+		}
+	})
+
+	t.Run("valid settings", func(t *testing.T) {
+		// given
+		flags := newFlags(t, "--log-level=3", "--leader-election-mode=leader-for-life", "--leader-election-renew-deadline=20s")
+
+		// when
+		_, err := configuration.LoadConfig(flags)
+
+		// then
+		require.NoError(t, err) // lease settings are ignored in the leader-for-life mode
+	})
+}
+
+func newFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	configuration.AddFlags(flags)
+	require.NoError(t, flags.Parse(args))
+	return flags
+}
+
+func newConfigFile(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "toolchain-operator-config-*.yaml")
+	require.NoError(t, err)
+	defer file.Close()
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	return file.Name()
+}
+
+// setEnv sets the env var and returns a func that restores its original value
+func setEnv(t *testing.T, key, value string) func() {
+	original, present := os.LookupEnv(key)
+	require.NoError(t, os.Setenv(key, value))
+	return func() {
+		if present {
+			require.NoError(t, os.Setenv(key, original))
+		} else {
+			require.NoError(t, os.Unsetenv(key))
+		}
+	}
+}
@@ -1,13 +1,22 @@
 package cheinstallation
 
 import (
+	"strconv"
+	"strings"
+	"time"
+
 	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/catalogsource"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/proxy"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
 
+	configv1 "github.com/openshift/api/config/v1"
 	olmv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1"
 	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	orgv1 "github.com/eclipse/che-operator/pkg/apis/org/v1"
@@ -16,14 +25,8 @@ import (
 const (
 	// InstallationName the name of the CheInstallation resource (cluster-scoped)
 	InstallationName = "toolchain-workspaces-installation"
-	// Namespace the namespace in which the OLM OperatorGroup and Subscription resources will be created
-	Namespace = "toolchain-workspaces"
-	// OperatorGroupName the name of the OLM OperatorGroup for Che
-	OperatorGroupName = InstallationName
 	// SubscriptionName the name of the OLM subscription for Che
 	SubscriptionName = "codeready-workspaces"
-	// StartingCSV keeps the CSV version the installation should start with
-	StartingCSV = "crwoperator.v2.0.0"
 	// CheClusterName the name of the CheCluster
 	CheClusterName = "codeready-workspaces"
 	// CheFlavorName the name of the CheCluster flavor
@@ -32,10 +35,49 @@ const (
 	AvailableStatus = "Available"
 	// CheClusterCRDName the fully qualified name of the CheCluster CRD
 	CheClusterCRDName = "checlusters.org.eclipse.che"
+	// StalledEventReason the reason of the event emitted when the installation is stalled
+	StalledEventReason = "InstallationStalled"
+	// StuckPodDeletedEventReason the reason of the event emitted when a stuck pod of the CheCluster is deleted
+	StuckPodDeletedEventReason = "StuckPodDeleted"
+	// RecreatedEventReason the reason of the event emitted when a secondary resource which was deleted out-of-band is recreated
+	RecreatedEventReason = "Recreated"
+	// CertificateExpiringEventReason the reason of the event emitted when the TLS certificate starts expiring soon, or expired
+	CertificateExpiringEventReason = "CertificateExpiring"
+	// FinalizersClearedEventReason the reason of the event emitted when the finalizers of a resource which blocks the deletion of the namespace are cleared
+	FinalizersClearedEventReason = "FinalizersCleared"
+	// TrustStoreField the field of the server spec of the CheCluster with the name of the ConfigMap of the trusted CA bundle
+	TrustStoreField = "serverTrustStoreConfigMapName"
+	// ResourceQuotaName the name of the ResourceQuota of the namespace of the Che operator
+	ResourceQuotaName = "toolchain-quota"
+	// LimitRangeName the name of the LimitRange of the namespace of the Che operator
+	LimitRangeName = "toolchain-limits"
+	// IsolationNetworkPolicyName the name of the NetworkPolicy which isolates the namespace of the Che operator
+	IsolationNetworkPolicyName = "toolchain-isolation"
+	// KeycloakNetworkPolicyName the name of the NetworkPolicy which accepts the traffic to Keycloak from any namespace
+	KeycloakNetworkPolicyName = "toolchain-allow-keycloak"
+	// RouterPolicyGroupLabelKey the label of the namespaces of the OpenShift router
+	RouterPolicyGroupLabelKey = "network.openshift.io/policy-group"
+	// RouterPolicyGroupLabelValue the value of the label of the namespaces of the OpenShift router
+	RouterPolicyGroupLabelValue = "ingress"
+	// CredentialsUserKey the key of the user in the secrets of the credentials of the external database and identity provider
+	CredentialsUserKey = "user"
+	// CredentialsPasswordKey the key of the password in the secrets of the credentials of the external database and identity provider
+	CredentialsPasswordKey = "password"
+	// DefaultDatabasePort the port of the external database when none is set
+	DefaultDatabasePort = 5432
+	// TLSSecretName the name of the secret with the TLS certificate of Che, which is copied in the namespace of the Che operator
+	TLSSecretName = "toolchain-che-tls"
+	// RouterCertificateSecretNamespace the namespace of the secret with the default certificate of the OpenShift router
+	RouterCertificateSecretNamespace = "openshift-ingress"
+	// RouterCertificateSecretName the name of the secret with the default certificate of the OpenShift router
+	RouterCertificateSecretName = "router-certs-default"
+	// DefaultCertificateExpiryWarningPeriod the duration before the expiry of the TLS certificate from which the CertificateExpiringSoon
+	// condition is set, when none is set in the CheInstallation
+	DefaultCertificateExpiryWarningPeriod = 30 * 24 * time.Hour
 )
 
-// NewInstallation returns a new CheInstallation resource
-func NewInstallation() *v1alpha1.CheInstallation {
+// NewInstallation returns a new CheInstallation resource for the configured namespace
+func NewInstallation(config *configuration.Config) *v1alpha1.CheInstallation {
 	return &v1alpha1.CheInstallation{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:       InstallationName, // Che installation resource is cluster-scoped, so no namespace is defined
@@ -43,14 +85,14 @@ func NewInstallation() *v1alpha1.CheInstallation {
 		},
 		Spec: v1alpha1.CheInstallationSpec{
 			CheOperatorSpec: v1alpha1.CheOperator{
-				Namespace: Namespace, // the namespace in which the Che operatorgroup and subscription resources will be created
+				Namespace: config.GetCheNamespace(), // the namespace in which the Che operatorgroup and subscription resources will be created
 			},
 		},
 	}
 }
 
-// NewSubscription for CodeReady Workspaces operator
-func NewSubscription(ns string) *olmv1alpha1.Subscription {
+// NewSubscription for CodeReady Workspaces operator, with the configured channel and starting CSV
+func NewSubscription(config *configuration.Config, ns string) *olmv1alpha1.Subscription {
 	return &olmv1alpha1.Subscription{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      SubscriptionName,
@@ -58,16 +100,21 @@ func NewSubscription(ns string) *olmv1alpha1.Subscription {
 			Labels:    toolchain.Labels(),
 		},
 		Spec: &olmv1alpha1.SubscriptionSpec{
-			Channel:                "latest",
+			Channel:                config.GetCheChannel(),
 			InstallPlanApproval:    olmv1alpha1.ApprovalAutomatic,
 			Package:                "codeready-workspaces",
-			StartingCSV:            StartingCSV,
-			CatalogSource:          "redhat-operators",
-			CatalogSourceNamespace: "openshift-marketplace",
+			StartingCSV:            config.GetCheStartingCSV(),
+			CatalogSource:          catalogsource.DefaultName,
+			CatalogSourceNamespace: catalogsource.DefaultNamespace,
 		},
 	}
 }
 
+// ManagedCatalogSourceName returns the name of the CatalogSource which is managed by the operator for the given CheInstallation
+func ManagedCatalogSourceName(cheInstallation *v1alpha1.CheInstallation) string {
+	return "crw-" + cheInstallation.Name
+}
+
 // NewNamespace return a new namespace with the toolchain labels
 func NewNamespace(name string) *v1.Namespace {
 	return &v1.Namespace{
@@ -78,12 +125,14 @@ func NewNamespace(name string) *v1.Namespace {
 	}
 }
 
-// NewOperatorGroup returns a new OLM Operator Group for the given ns, with the toolchain labels
-func NewOperatorGroup(ns string) *olmv1.OperatorGroup {
+// NewOperatorGroup returns a new OLM Operator Group in the namespace of the given CheInstallation, with the toolchain labels.
+// The OperatorGroup is named after the CheInstallation.
+func NewOperatorGroup(cheInstallation *v1alpha1.CheInstallation) *olmv1.OperatorGroup {
+	ns := cheInstallation.Spec.CheOperatorSpec.Namespace
 	return &olmv1.OperatorGroup{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: ns,
-			Name:      OperatorGroupName,
+			Name:      cheInstallation.Name,
 			Labels:    toolchain.Labels(),
 		},
 		Spec: olmv1.OperatorGroupSpec{
@@ -92,13 +141,107 @@ func NewOperatorGroup(ns string) *olmv1.OperatorGroup {
 	}
 }
 
-// NewCheCluster returns a new CheCluster with the given namespace
-func NewCheCluster(ns string) *orgv1.CheCluster {
-	return &orgv1.CheCluster{
+// NewResourceQuota returns a new ResourceQuota in the namespace of the given CheInstallation, with the hard limits of its namespace policy
+func NewResourceQuota(cheInstallation *v1alpha1.CheInstallation) *v1.ResourceQuota {
+	quota := &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cheInstallation.Spec.CheOperatorSpec.Namespace,
+			Name:      ResourceQuotaName,
+			Labels:    toolchain.LabelsWithOwner(cheInstallation.Name),
+		},
+	}
+	if policy := cheInstallation.Spec.NamespacePolicy; policy != nil {
+		quota.Spec.Hard = policy.Quota.DeepCopy()
+	}
+	return quota
+}
+
+// NewLimitRange returns a new LimitRange in the namespace of the given CheInstallation, with the default container limits of its namespace policy
+func NewLimitRange(cheInstallation *v1alpha1.CheInstallation) *v1.LimitRange {
+	limitRange := &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cheInstallation.Spec.CheOperatorSpec.Namespace,
+			Name:      LimitRangeName,
+			Labels:    toolchain.LabelsWithOwner(cheInstallation.Name),
+		},
+	}
+	if policy := cheInstallation.Spec.NamespacePolicy; policy != nil && policy.DefaultLimits != nil {
+		limitRange.Spec.Limits = []v1.LimitRangeItem{
+			{
+				Type:           v1.LimitTypeContainer,
+				Default:        policy.DefaultLimits.Limits.DeepCopy(),
+				DefaultRequest: policy.DefaultLimits.Requests.DeepCopy(),
+			},
+		}
+	}
+	return limitRange
+}
+
+// NewIsolationNetworkPolicy returns a new NetworkPolicy in the namespace of the given CheInstallation, which only accepts the traffic
+// from the pods of the namespace and from the OpenShift router (hence the browser traffic and the OAuth callbacks through the routes)
+func NewIsolationNetworkPolicy(cheInstallation *v1alpha1.CheInstallation) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cheInstallation.Spec.CheOperatorSpec.Namespace,
+			Name:      IsolationNetworkPolicyName,
+			Labels:    toolchain.LabelsWithOwner(cheInstallation.Name),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							PodSelector: &metav1.LabelSelector{},
+						},
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{RouterPolicyGroupLabelKey: RouterPolicyGroupLabelValue},
+							},
+						},
+					},
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+}
+
+// NewKeycloakNetworkPolicy returns a new NetworkPolicy in the namespace of the given CheInstallation, which accepts the traffic
+// from any namespace to Keycloak, so that the OpenShift OAuth server and the workspaces can reach it
+func NewKeycloakNetworkPolicy(cheInstallation *v1alpha1.CheInstallation) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cheInstallation.Spec.CheOperatorSpec.Namespace,
+			Name:      KeycloakNetworkPolicyName,
+			Labels:    toolchain.LabelsWithOwner(cheInstallation.Name),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": CheFlavorName, "component": "keycloak"},
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{},
+						},
+					},
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+}
+
+// NewCheCluster returns a new CheCluster in the namespace of the given CheInstallation, with the toolchain labels,
+// the label of the CheInstallation which owns it and its air-gap settings
+func NewCheCluster(cheInstallation *v1alpha1.CheInstallation) *orgv1.CheCluster {
+	cluster := &orgv1.CheCluster{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      CheClusterName,
-			Namespace: ns,
-			Labels:    toolchain.Labels(),
+			Namespace: cheInstallation.Spec.CheOperatorSpec.Namespace,
+			Labels:    toolchain.LabelsWithOwner(cheInstallation.Name),
 		},
 
 		Spec: orgv1.CheClusterSpec{
@@ -124,6 +267,170 @@ func NewCheCluster(ns string) *orgv1.CheCluster {
 			},
 		},
 	}
+	setCheClusterAirGap(cluster, cheInstallation.Spec.AirGap)
+	setCheClusterTLS(cluster, cheInstallation.Spec.TLS)
+	return cluster
+}
+
+// setCheClusterTLS enables TLS on the given CheCluster with the given settings, or disables it when there are no TLS settings.
+// The certificate is read from the secret which is copied in the namespace of the Che operator, unless the default certificate of
+// the router is used. Returns true if the settings of the CheCluster changed.
+func setCheClusterTLS(cluster *orgv1.CheCluster, tls *v1alpha1.CheTLS) bool {
+	tlsSupport, selfSignedCert, tlsSecretName := false, false, ""
+	if tls != nil {
+		tlsSupport, selfSignedCert = true, tls.SelfSignedCert
+		if tls.CertificateSecret != nil {
+			tlsSecretName = TLSSecretName
+		}
+	}
+	if cluster.Spec.Server.TlsSupport == tlsSupport && cluster.Spec.Server.SelfSignedCert == selfSignedCert && cluster.Spec.K8s.TlsSecretName == tlsSecretName {
+		return false
+	}
+	cluster.Spec.Server.TlsSupport = tlsSupport
+	cluster.Spec.Server.SelfSignedCert = selfSignedCert
+	cluster.Spec.K8s.TlsSecretName = tlsSecretName
+	return true
+}
+
+// NewTLSSecret returns a new secret in the namespace of the Che operator with the given TLS certificate and private key
+func NewTLSSecret(cheInstallation *v1alpha1.CheInstallation, certificate, key []byte) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cheInstallation.Spec.CheOperatorSpec.Namespace,
+			Name:      TLSSecretName,
+			Labels:    toolchain.LabelsWithOwner(cheInstallation.Name),
+		},
+		Type: v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey:       certificate,
+			v1.TLSPrivateKeyKey: key,
+		},
+	}
+}
+
+// setCheClusterAirGap sets the mirror registry and the images of the given air-gap settings on the given CheCluster, or resets them
+// to the default values when there are no air-gap settings. Returns true if the settings of the CheCluster changed.
+func setCheClusterAirGap(cluster *orgv1.CheCluster, airGap *v1alpha1.CheAirGap) bool {
+	var mirrorRegistry string
+	images := v1alpha1.CheImages{}
+	if airGap != nil {
+		mirrorRegistry = airGap.MirrorRegistry
+		if airGap.Images != nil {
+			images = *airGap.Images
+		}
+	}
+	hostname, organization := mirrorRegistry, ""
+	if i := strings.Index(mirrorRegistry, "/"); i >= 0 {
+		hostname, organization = mirrorRegistry[:i], mirrorRegistry[i+1:]
+	}
+	changed := false
+	for _, field := range []struct {
+		current  *string
+		expected string
+	}{
+		{&cluster.Spec.Server.AirGapContainerRegistryHostname, hostname},
+		{&cluster.Spec.Server.AirGapContainerRegistryOrganization, organization},
+		{&cluster.Spec.Server.CheImage, images.Server},
+		{&cluster.Spec.Server.CheImageTag, images.ServerTag},
+		{&cluster.Spec.Server.DevfileRegistryImage, images.DevfileRegistry},
+		{&cluster.Spec.Server.PluginRegistryImage, images.PluginRegistry},
+		{&cluster.Spec.Auth.IdentityProviderImage, images.IdentityProvider},
+		{&cluster.Spec.Database.PostgresImage, images.Postgres},
+		{&cluster.Spec.Storage.PvcJobsImage, images.PvcJobs},
+	} {
+		if *field.current != field.expected {
+			*field.current = field.expected
+			changed = true
+		}
+	}
+	return changed
+}
+
+// setCheClusterProxy sets the settings of the given cluster-wide Proxy on the given CheCluster, or resets them if there is no Proxy.
+// Returns true if the settings of the CheCluster changed.
+func setCheClusterProxy(cluster *orgv1.CheCluster, clusterProxy *configv1.Proxy) (bool, error) {
+	settings, err := proxy.GetSettings(clusterProxy)
+	if err != nil {
+		return false, err
+	}
+	server := &cluster.Spec.Server
+	if server.ProxyURL == settings.URL && server.ProxyPort == settings.Port && server.ProxyUser == settings.User &&
+		server.ProxyPassword == settings.Password && server.NonProxyHosts == settings.NonProxyHosts {
+		return false, nil
+	}
+	server.ProxyURL = settings.URL
+	server.ProxyPort = settings.Port
+	server.ProxyUser = settings.User
+	server.ProxyPassword = settings.Password
+	server.NonProxyHosts = settings.NonProxyHosts
+	return true, nil
+}
+
+// setCheClusterDatabase sets the given external database and its credentials on the given CheCluster, or resets the settings
+// so that the database is deployed along with Che when there is no external database. The other settings of the database of the
+// CheCluster (eg: the image of the deployed database) are kept. Returns true if the settings of the CheCluster changed.
+func setCheClusterDatabase(cluster *orgv1.CheCluster, database *v1alpha1.CheDatabase, credentials *credentials) bool {
+	expected := cluster.Spec.Database
+	expected.ExternalDb = false
+	expected.ChePostgresHostName = ""
+	expected.ChePostgresPort = ""
+	expected.ChePostgresDb = ""
+	expected.ChePostgresUser = ""
+	expected.ChePostgresPassword = ""
+	if database != nil && credentials != nil {
+		port := database.Port
+		if port == 0 {
+			port = DefaultDatabasePort
+		}
+		expected.ExternalDb = true
+		expected.ChePostgresHostName = database.Host
+		expected.ChePostgresPort = strconv.Itoa(int(port))
+		expected.ChePostgresDb = database.Name
+		// the version of the CheCluster API known to the operator has no reference to a secret, so the password is stored in
+		// plain text in the spec of the CheCluster, where it can be read by anyone allowed to read the CheCluster
+		expected.ChePostgresUser = credentials.user
+		expected.ChePostgresPassword = credentials.password
+	}
+	if expected == cluster.Spec.Database {
+		return false
+	}
+	cluster.Spec.Database = expected
+	return true
+}
+
+// setCheClusterIdentityProvider sets the given external identity provider and its admin credentials on the given CheCluster. When there
+// is no external identity provider anymore, the settings are reset so that Keycloak is deployed along with Che and integrated with the
+// OpenShift OAuth server. The settings of the deployed Keycloak are managed by the Che operator, hence they are left as is.
+// Returns true if the settings of the CheCluster changed.
+func setCheClusterIdentityProvider(cluster *orgv1.CheCluster, identityProvider *v1alpha1.CheIdentityProvider, adminCredentials *credentials) bool {
+	expected := cluster.Spec.Auth
+	if identityProvider == nil || adminCredentials == nil {
+		if !expected.ExternalIdentityProvider {
+			return false
+		}
+		expected.ExternalIdentityProvider = false
+		expected.OpenShiftoAuth = true
+		expected.IdentityProviderURL = ""
+		expected.IdentityProviderRealm = ""
+		expected.IdentityProviderClientId = ""
+		expected.IdentityProviderAdminUserName = ""
+		expected.IdentityProviderPassword = ""
+	} else {
+		expected.ExternalIdentityProvider = true
+		// the OpenShift OAuth integration requires the Keycloak deployed along with Che
+		expected.OpenShiftoAuth = false
+		expected.IdentityProviderURL = identityProvider.URL
+		expected.IdentityProviderRealm = identityProvider.Realm
+		expected.IdentityProviderClientId = identityProvider.ClientID
+		// as for the database, the admin password is stored in plain text in the spec of the CheCluster
+		expected.IdentityProviderAdminUserName = adminCredentials.user
+		expected.IdentityProviderPassword = adminCredentials.password
+	}
+	if expected == cluster.Spec.Auth {
+		return false
+	}
+	cluster.Spec.Auth = expected
+	return true
 }
 
 // Installing returns the status condition to set when Che is (still) being installed
@@ -146,6 +453,105 @@ func Terminating(message string) toolchainv1alpha1.Condition {
 	}
 }
 
+// NamespaceTerminating returns the status condition to set when the namespace of the Che operator is terminating and cannot be recreated yet
+func NamespaceTerminating(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CheReady,
+		Status:  v1.ConditionFalse,
+		Reason:  v1alpha1.NamespaceTerminatingReason,
+		Message: message,
+	}
+}
+
+// NamespaceConflict returns the status condition to set when the namespace of the Che operator is already used by another CheInstallation
+func NamespaceConflict(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CheReady,
+		Status:  v1.ConditionFalse,
+		Reason:  v1alpha1.ConflictReason,
+		Message: message,
+	}
+}
+
+// CatalogSourceNotReady returns the status condition to set when the mirror CatalogSource of the CheInstallation is not ready
+func CatalogSourceNotReady(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CheReady,
+		Status:  v1.ConditionFalse,
+		Reason:  v1alpha1.CatalogSourceNotReadyReason,
+		Message: message,
+	}
+}
+
+// DatabaseConfigInvalid returns the status condition to set when the settings of the external database are invalid
+func DatabaseConfigInvalid(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CheReady,
+		Status:  v1.ConditionFalse,
+		Reason:  v1alpha1.DatabaseConfigInvalidReason,
+		Message: message,
+	}
+}
+
+// IdentityProviderConfigInvalid returns the status condition to set when the settings of the external identity provider are invalid
+func IdentityProviderConfigInvalid(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CheReady,
+		Status:  v1.ConditionFalse,
+		Reason:  v1alpha1.IdentityProviderConfigInvalidReason,
+		Message: message,
+	}
+}
+
+// IdentityProviderUnreachable returns the status condition to set when the external identity provider is unreachable
+func IdentityProviderUnreachable(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CheReady,
+		Status:  v1.ConditionFalse,
+		Reason:  v1alpha1.IdentityProviderUnreachableReason,
+		Message: message,
+	}
+}
+
+// CertificateInvalid returns the status condition to set when the TLS certificate is invalid (eg: its secret does not exist)
+func CertificateInvalid(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CheReady,
+		Status:  v1.ConditionFalse,
+		Reason:  v1alpha1.CertificateInvalidReason,
+		Message: message,
+	}
+}
+
+// CertificateExpiringSoon returns the status condition to set when the TLS certificate expires within the warning period
+func CertificateExpiringSoon(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CertificateExpiringSoon,
+		Status:  v1.ConditionTrue,
+		Reason:  v1alpha1.CertificateExpiringReason,
+		Message: message,
+	}
+}
+
+// CertificateExpired returns the status condition to set when the TLS certificate expired
+func CertificateExpired(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CertificateExpiringSoon,
+		Status:  v1.ConditionTrue,
+		Reason:  v1alpha1.CertificateExpiredReason,
+		Message: message,
+	}
+}
+
+// CertificateNotExpiringSoon returns the status condition to set when the TLS certificate does not expire within the warning period
+func CertificateNotExpiringSoon() toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:   v1alpha1.CertificateExpiringSoon,
+		Status: v1.ConditionFalse,
+		Reason: v1alpha1.CertificateValidReason,
+	}
+}
+
 // InstallationSucceeded returns a status condition for the case where the Che installation succeeded
 func InstallationSucceeded() toolchainv1alpha1.Condition {
 	return toolchainv1alpha1.Condition{
@@ -164,3 +570,22 @@ func InstallationFailed(message string) toolchainv1alpha1.Condition {
 		Message: message,
 	}
 }
+
+// Stalled returns a status condition for the case where the Che installation did not progress within the install timeout
+func Stalled(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.Stalled,
+		Status:  v1.ConditionTrue,
+		Reason:  v1alpha1.InstallTimeoutReason,
+		Message: message,
+	}
+}
+
+// NotStalled returns a status condition for the case where the Che installation progressed again after it was stalled
+func NotStalled() toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:   v1alpha1.Stalled,
+		Status: v1.ConditionFalse,
+		Reason: v1alpha1.ProgressingReason,
+	}
+}
@@ -2,6 +2,7 @@ package cheinstallation
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -10,6 +11,15 @@ import (
 	"github.com/codeready-toolchain/toolchain-common/pkg/condition"
 	commoncontroller "github.com/codeready-toolchain/toolchain-common/pkg/controller"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/cabundle"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/catalogsource"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/health"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/metrics"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/proxy"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/references"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/subscription"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
 
 	che "github.com/eclipse/che-operator/pkg/apis/org/v1"
 	orgv1 "github.com/eclipse/che-operator/pkg/apis/org/v1"
@@ -19,10 +29,16 @@ import (
 	errs "github.com/pkg/errors"
 	"github.com/redhat-cop/operator-utils/pkg/util"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -38,20 +54,23 @@ var log = logf.Log.WithName("controller_cheinstallation")
 
 // Add creates a new CheInstallation Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
-func Add(mgr manager.Manager) error {
+func Add(mgr manager.Manager, config *configuration.Config) error {
 	log.Info("Adding new CheInstallation reconciler")
-	return add(mgr, newReconciler(mgr))
+	return add(mgr, newReconciler(mgr, config))
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) *ReconcileCheInstallation {
-	return &ReconcileCheInstallation{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager, config *configuration.Config) *ReconcileCheInstallation {
+	// the Secrets and ConfigMaps are read from the API server, since they are not in the cache of the manager
+	cl := references.NewClient(mgr.GetClient(), mgr.GetAPIReader(), &corev1.Secret{}, &corev1.SecretList{}, &corev1.ConfigMap{}, &corev1.ConfigMapList{})
+	return &ReconcileCheInstallation{client: cl, scheme: mgr.GetScheme(), config: config, recorder: mgr.GetEventRecorderFor("cheinstallation-controller"),
+		identityProviderBackoff: newIdentityProviderBackoff(config)}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r *ReconcileCheInstallation) error {
 	// Create a new controller
-	c, err := controller.New("cheinstallation-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("cheinstallation-controller", mgr, controller.Options{Reconciler: health.DefaultStatus.TrackReconciles("cheinstallation-controller", r)})
 	if err != nil {
 		return err
 	}
@@ -73,6 +92,17 @@ func add(mgr manager.Manager, r *ReconcileCheInstallation) error {
 		return err
 	}
 
+	log.Info("configuring watchers on the ResourceQuotas, LimitRanges and NetworkPolicies of the namespace policy")
+	if err := c.Watch(&source.Kind{Type: &corev1.ResourceQuota{}}, enqueueRequestForOwner); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &corev1.LimitRange{}}, enqueueRequestForOwner); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &networkingv1.NetworkPolicy{}}, enqueueRequestForOwner); err != nil {
+		return err
+	}
+
 	log.Info("configuring watcher on Operator Groups")
 	if err := c.Watch(&source.Kind{Type: &olmv1.OperatorGroup{}}, enqueueRequestForOwner); err != nil {
 		return err
@@ -83,9 +113,44 @@ func add(mgr manager.Manager, r *ReconcileCheInstallation) error {
 		return err
 	}
 
+	log.Info("configuring watcher on the managed CatalogSources")
+	if err := c.Watch(&source.Kind{Type: &olmv1alpha1.CatalogSource{}}, enqueueRequestForOwner); err != nil {
+		return err
+	}
+
+	// the Secrets and ConfigMaps are only watched in the namespaces of the objects referenced by the CheInstallations and in the
+	// namespaces of the Che operator, instead of caching all the Secrets and ConfigMaps of the cluster
+	log.Info("configuring watchers on the Secrets of the database and identity provider credentials and of the TLS certificates")
+	secrets, err := references.NewWatcher(mgr, c, &corev1.Secret{}, &v1alpha1.CheInstallation{})
+	if err != nil {
+		return err
+	}
+	log.Info("configuring watchers on the ConfigMaps of the trusted CA bundles")
+	configMaps, err := references.NewWatcher(mgr, c, &corev1.ConfigMap{}, &v1alpha1.CheInstallation{})
+	if err != nil {
+		return err
+	}
+	r.watchReferences = func(cheInstallation *v1alpha1.CheInstallation) error {
+		if util.IsBeingDeleted(cheInstallation) {
+			secrets.Remove(cheInstallation.Name)
+			configMaps.Remove(cheInstallation.Name)
+			return nil
+		}
+		namespaces := []string{cheInstallation.Spec.CheOperatorSpec.Namespace}
+		if err := secrets.Watch(cheInstallation.Name, namespaces, referencedSecrets(cheInstallation)...); err != nil {
+			return err
+		}
+		return configMaps.Watch(cheInstallation.Name, namespaces, referencedConfigMaps(cheInstallation)...)
+	}
+
+	log.Info("configuring watcher on the cluster Proxy")
+	if err := proxy.Watch(mgr, c, &handler.EnqueueRequestsFromMapFunc{ToRequests: mapToCheInstallations(mgr.GetClient())}); err != nil {
+		return err
+	}
+
 	r.watchCheCluster = func() error {
 		// make sure that there's a label with this key on the CheCluster in order to trigger a new reconcile loop
-		return c.Watch(&source.Kind{Type: &orgv1.CheCluster{}}, commoncontroller.MapToOwnerByLabel("", "provider"))
+		return c.Watch(&source.Kind{Type: &orgv1.CheCluster{}}, commoncontroller.MapToOwnerByLabel("", toolchain.OwnerLabelKey))
 	}
 
 	log.Info("CheInstallation reconciler successfully added")
@@ -101,8 +166,13 @@ type ReconcileCheInstallation struct {
 	// that reads objects from the cache and writes to the apiserver
 	client          client.Client
 	scheme          *runtime.Scheme
+	config          *configuration.Config
+	recorder        record.EventRecorder
 	watchCheCluster func() error
-	mu              sync.Mutex
+	watchReferences func(cheInstallation *v1alpha1.CheInstallation) error
+	// identityProviderBackoff gives the delays before checking again the external identity providers which are unreachable
+	identityProviderBackoff workqueue.RateLimiter
+	mu                      sync.Mutex
 }
 
 // Reconcile reads that state of the cluster for a CheInstallation object and makes changes based on the state read
@@ -113,7 +183,7 @@ func (r *ReconcileCheInstallation) Reconcile(request reconcile.Request) (reconci
 
 	cheInstallation := &v1alpha1.CheInstallation{}
 	err := r.client.Get(context.TODO(), types.NamespacedName{
-		Name: InstallationName,
+		Name: request.Name,
 	}, cheInstallation)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -122,6 +192,9 @@ func (r *ReconcileCheInstallation) Reconcile(request reconcile.Request) (reconci
 		}
 		return reconcile.Result{}, err
 	}
+	if err := r.watchReferences(cheInstallation); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to watch the Secrets and ConfigMaps referenced by the CheInstallation")
+	}
 	// ensure there's a finalizer, unless it's being deleted
 	if !util.IsBeingDeleted(cheInstallation) {
 		// Add the finalizer if it is not present
@@ -147,42 +220,139 @@ func (r *ReconcileCheInstallation) Reconcile(request reconcile.Request) (reconci
 		return reconcile.Result{}, nil
 	}
 
+	if conflict, err := r.findNamespaceConflict(cheInstallation); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to check the usage of namespace %s", cheInstallation.Spec.CheOperatorSpec.Namespace)
+	} else if conflict != "" {
+		reqLogger.Info("Namespace is already used by another CheInstallation", "message", conflict)
+		// requeue in case the namespace is released by the other CheInstallation
+		return reconcile.Result{Requeue: true, RequeueAfter: r.config.GetCheRequeueAfter()}, r.statusUpdate(reqLogger, cheInstallation, r.setStatusCheNamespaceConflict, conflict)
+	}
+
 	if requeue, err := r.ensureCheNamespace(reqLogger, cheInstallation); err != nil {
 		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to create namespace %s", cheInstallation.Spec.CheOperatorSpec.Namespace)
 	} else if requeue {
-		return reconcile.Result{Requeue: true, RequeueAfter: 3 * time.Second}, nil
+		return reconcile.Result{Requeue: true, RequeueAfter: r.config.GetCheRequeueAfter()}, nil
+	}
+
+	if err := r.ensureNamespacePolicy(reqLogger, cheInstallation); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to apply the namespace policy in namespace %s", cheInstallation.Spec.CheOperatorSpec.Namespace)
 	}
 
 	if created, err := r.ensureCheOperatorGroup(reqLogger, cheInstallation); err != nil {
 		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to create operatorgroup in namespace %s", cheInstallation.Spec.CheOperatorSpec.Namespace)
 	} else if created {
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, r.reportRecreation(reqLogger, cheInstallation, "OperatorGroup", cheInstallation.Name)
+	}
+
+	if err := catalogsource.Ensure(reqLogger, r.client, r.scheme, cheInstallation, ManagedCatalogSourceName(cheInstallation), cheInstallation.Spec.CatalogSource, cheInstallation.Spec.CommonMetadata); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to create the CatalogSource of CodeReady Workspaces")
+	}
+
+	if notReady, err := r.checkCatalogSource(cheInstallation); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to check the CatalogSource of CodeReady Workspaces")
+	} else if notReady != "" {
+		reqLogger.Info("CatalogSource is not ready", "message", notReady)
+		// requeue until the CatalogSource is ready
+		return reconcile.Result{Requeue: true, RequeueAfter: r.config.GetCheRequeueAfter()}, r.statusUpdate(reqLogger, cheInstallation, r.setStatusCatalogSourceNotReady, notReady)
 	}
 
 	if created, err := r.ensureCheSubscription(reqLogger, cheInstallation); err != nil {
 		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to create Che subscription in namespace %s", cheInstallation.Spec.CheOperatorSpec.Namespace)
 	} else if created {
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, r.reportRecreation(reqLogger, cheInstallation, "Subscription", SubscriptionName)
+	}
+
+	if err := cabundle.Ensure(reqLogger, r.client, r.scheme, cheInstallation, cheInstallation.Spec.CheOperatorSpec.Namespace, cheInstallation.Spec.TrustedCABundle, cheInstallation.Spec.CommonMetadata); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to provision the trusted CA bundle in namespace %s", cheInstallation.Spec.CheOperatorSpec.Namespace)
+	}
+
+	databaseCredentials, invalid, err := r.getDatabaseCredentials(cheInstallation)
+	if err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to get the credentials of the database")
+	} else if invalid != "" {
+		reqLogger.Info("Database config is invalid", "message", invalid)
+		// no need to requeue: the changes of the secret trigger a new reconcile loop
+		return reconcile.Result{}, r.statusUpdate(reqLogger, cheInstallation, r.setStatusDatabaseConfigInvalid, invalid)
+	}
+
+	adminCredentials, invalid, err := r.getIdentityProviderCredentials(cheInstallation)
+	if err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to get the admin credentials of the identity provider")
+	} else if invalid != "" {
+		reqLogger.Info("Identity provider config is invalid", "message", invalid)
+		// no need to requeue: the changes of the secret trigger a new reconcile loop
+		return reconcile.Result{}, r.statusUpdate(reqLogger, cheInstallation, r.setStatusIdentityProviderConfigInvalid, invalid)
+	}
+	// the identity provider is only checked when its settings or its admin credentials changed since it was last found reachable
+	if hash := identityProviderHash(cheInstallation.Spec.IdentityProvider, adminCredentials); hash != cheInstallation.Status.IdentityProviderHash {
+		httpClient, err := r.newIdentityProviderClient(cheInstallation)
+		if err != nil {
+			return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to get the trusted CA bundle of the identity provider")
+		}
+		if unreachable := checkIdentityProvider(httpClient, cheInstallation); unreachable != "" {
+			reqLogger.Info("Identity provider is unreachable", "message", unreachable)
+			// requeue with an increasing delay until the identity provider is reachable
+			return reconcile.Result{Requeue: true, RequeueAfter: r.identityProviderBackoff.When(cheInstallation.Name)},
+				r.statusUpdate(reqLogger, cheInstallation, r.setStatusIdentityProviderUnreachable, unreachable)
+		}
+		if err := r.statusUpdate(reqLogger, cheInstallation, r.setStatusIdentityProviderHash(hash), ""); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	r.identityProviderBackoff.Forget(cheInstallation.Name)
+
+	certificate, invalid, err := r.ensureTLSCertificate(reqLogger, cheInstallation)
+	if err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to provision the TLS certificate in namespace %s", cheInstallation.Spec.CheOperatorSpec.Namespace)
+	}
+	certificateCheckAfter, err := r.updateCertificateExpiry(reqLogger, cheInstallation, certificate)
+	if err != nil {
+		return reconcile.Result{}, errs.Wrap(err, "failed to update the expiry of the TLS certificate")
+	}
+	if invalid != "" {
+		reqLogger.Info("TLS certificate is invalid", "message", invalid)
+		// no need to requeue: the changes of the secret trigger a new reconcile loop
+		return reconcile.Result{}, r.statusUpdate(reqLogger, cheInstallation, r.setStatusCertificateInvalid, invalid)
 	}
 
 	if requeue, err := r.ensureWatchCheCluster(); err != nil {
 		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to add watch for CheCluster")
 	} else if requeue {
-		return reconcile.Result{Requeue: true, RequeueAfter: 3 * time.Second}, nil
+		return reconcile.Result{Requeue: true, RequeueAfter: r.config.GetCheRequeueAfter()}, nil
 	}
 
-	cheCluster, err := r.ensureCheCluster(reqLogger, cheInstallation)
+	cheCluster, err := r.ensureCheCluster(reqLogger, cheInstallation, databaseCredentials, adminCredentials)
 	if err != nil {
 		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to create Che cluster in namespace %s", cheInstallation.Spec.CheOperatorSpec.Namespace)
 	}
+	if err := r.ensureCheClusterTrustStore(reqLogger, cheInstallation); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationFailed, err, "failed to set the trust store of the Che cluster in namespace %s", cheInstallation.Spec.CheOperatorSpec.Namespace)
+	}
 	installed, msg := getCheClusterStatus(cheCluster)
 	reqLogger.Info("checluster ensured", "msg", msg, "installed", installed)
 	if !installed {
-		return reconcile.Result{}, r.statusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationInstalling, msg)
+		requeueAfter, err := r.updateInstallProgress(reqLogger, cheInstallation, msg)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		// requeue to detect when the installation is stalled (or when the certificate starts expiring soon)
+		return reconcile.Result{RequeueAfter: earliest(requeueAfter, certificateCheckAfter)}, nil
 	}
 
 	reqLogger.Info("done with Che installation")
-	return reconcile.Result{}, r.statusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationSucceeded(cheCluster), "")
+	// requeue to detect when the certificate starts expiring soon, if TLS is enabled
+	return reconcile.Result{RequeueAfter: certificateCheckAfter}, r.statusUpdate(reqLogger, cheInstallation, r.setStatusCheInstallationSucceeded(cheCluster), "")
+}
+
+// earliest returns the shortest of the given delays, ignoring the zero ones
+func earliest(delays ...time.Duration) time.Duration {
+	var result time.Duration
+	for _, delay := range delays {
+		if delay > 0 && (result == 0 || delay < result) {
+			result = delay
+		}
+	}
+	return result
 }
 
 // setFinalizers sets the finalizers for NSTemplateSet
@@ -199,6 +369,7 @@ func (r *ReconcileCheInstallation) addFinalizer(reqLogger logr.Logger, cheInstal
 func (r *ReconcileCheInstallation) ensureCheNamespace(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation) (bool, error) {
 	cheOpNamespace := cheInstallation.Spec.CheOperatorSpec.Namespace
 	namespace := NewNamespace(cheOpNamespace)
+	toolchain.SetCommonMetadata(namespace, cheInstallation.Spec.CommonMetadata)
 	if err := controllerutil.SetControllerReference(cheInstallation, namespace, r.scheme); err != nil {
 		return false, err
 	}
@@ -211,7 +382,17 @@ func (r *ReconcileCheInstallation) ensureCheNamespace(logger logr.Logger, cheIns
 			}
 			if ns.Status.Phase != corev1.NamespaceActive {
 				logger.Info("Namespace is not in active state", "namespace", ns.Name, "phase", ns.Status.Phase)
-				return true, nil // requeue until the namespace is active
+				// requeue until the namespace is active
+				return true, r.diagnoseTerminatingNamespace(logger, cheInstallation, &ns)
+			}
+			if conflict := findNamespaceOwnerConflict(cheInstallation, &ns); conflict != "" {
+				logger.Info("Namespace is already used by another CheInstallation", "message", conflict)
+				// requeue in case the namespace is released by the other CheInstallation
+				return true, r.statusUpdate(logger, cheInstallation, r.setStatusCheNamespaceConflict, conflict)
+			}
+			if toolchain.SetCommonMetadata(&ns, cheInstallation.Spec.CommonMetadata) {
+				logger.Info("Updating the common labels and annotations of the namespace for Che operator", "Namespace", cheOpNamespace)
+				return false, r.client.Update(context.TODO(), &ns)
 			}
 			return false, nil
 		}
@@ -224,13 +405,22 @@ func (r *ReconcileCheInstallation) ensureCheNamespace(logger logr.Logger, cheIns
 }
 
 func (r *ReconcileCheInstallation) ensureCheOperatorGroup(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation) (bool, error) {
-	cheOg := NewOperatorGroup(cheInstallation.Spec.CheOperatorSpec.Namespace)
+	cheOg := NewOperatorGroup(cheInstallation)
+	toolchain.SetCommonMetadata(cheOg, cheInstallation.Spec.CommonMetadata)
 	if err := controllerutil.SetControllerReference(cheInstallation, cheOg, r.scheme); err != nil {
 		return false, err
 	}
 	if err := r.client.Create(context.TODO(), cheOg); err != nil {
 		if errors.IsAlreadyExists(err) {
 			logger.Info("OperatorGroup for Che already exists", "OperatorGroup.Namespace", cheOg.Namespace, "OperatorGroup.Name", cheOg.Name)
+			existing := &olmv1.OperatorGroup{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cheOg.Namespace, Name: cheOg.Name}, existing); err != nil {
+				return false, err
+			}
+			if toolchain.SetCommonMetadata(existing, cheInstallation.Spec.CommonMetadata) {
+				logger.Info("Updating the common labels and annotations of the OperatorGroup for Che", "OperatorGroup.Namespace", cheOg.Namespace, "OperatorGroup.Name", cheOg.Name)
+				return false, r.client.Update(context.TODO(), existing)
+			}
 			return false, nil
 		}
 		return false, err
@@ -239,14 +429,56 @@ func (r *ReconcileCheInstallation) ensureCheOperatorGroup(logger logr.Logger, ch
 	return true, nil
 }
 
+// checkCatalogSource checks that the managed or mirror CatalogSource of the given CheInstallation is ready, unless the Subscription already uses it.
+// Returns a message which describes why the CatalogSource is not ready, or an empty string if there is nothing to wait for.
+func (r *ReconcileCheInstallation) checkCatalogSource(cheInstallation *v1alpha1.CheInstallation) (string, error) {
+	catalogSource := getCatalogSource(cheInstallation)
+	if catalogSource == nil {
+		return "", nil
+	}
+	ref := *catalogSource
+	sub := &olmv1alpha1.Subscription{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cheInstallation.Spec.CheOperatorSpec.Namespace, Name: SubscriptionName}, sub); err != nil {
+		if !errors.IsNotFound(err) {
+			return "", err
+		}
+	} else if !catalogsource.SetSubscriptionSource(sub, ref) {
+		return "", nil
+	}
+	return catalogsource.CheckReady(r.client, ref)
+}
+
 func (r *ReconcileCheInstallation) ensureCheSubscription(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation) (bool, error) {
-	cheSub := NewSubscription(cheInstallation.Spec.CheOperatorSpec.Namespace)
+	clusterProxy, err := proxy.GetClusterProxy(r.client)
+	if err != nil {
+		return false, err
+	}
+	cheSub := NewSubscription(r.config, cheInstallation.Spec.CheOperatorSpec.Namespace)
 	if err := controllerutil.SetControllerReference(cheInstallation, cheSub, r.scheme); err != nil {
 		return false, err
 	}
+	subscription.SetConfig(cheSub, cheInstallation.Spec.OperatorConfig, clusterProxy)
+	catalogSource := catalogsource.OrDefault(getCatalogSource(cheInstallation))
+	catalogsource.SetSubscriptionSource(cheSub, catalogSource)
+	toolchain.SetCommonMetadata(cheSub, cheInstallation.Spec.CommonMetadata)
 	if err := r.client.Create(context.TODO(), cheSub); err != nil {
 		if errors.IsAlreadyExists(err) {
 			logger.Info("Subscription for Che already exists", "Subscription.Namespace", cheSub.Namespace, "Subscription.Name", cheSub.Name)
+			existing := &olmv1alpha1.Subscription{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cheSub.Namespace, Name: cheSub.Name}, existing); err != nil {
+				return false, err
+			}
+			changed := catalogsource.SetSubscriptionSource(existing, catalogSource)
+			if subscription.SetConfig(existing, cheInstallation.Spec.OperatorConfig, clusterProxy) {
+				changed = true
+			}
+			if toolchain.SetCommonMetadata(existing, cheInstallation.Spec.CommonMetadata) {
+				changed = true
+			}
+			if changed {
+				logger.Info("Updating the Subscription for Che", "Subscription.Namespace", cheSub.Namespace, "Subscription.Name", cheSub.Name)
+				return false, r.client.Update(context.TODO(), existing)
+			}
 			return false, nil
 		}
 		logger.Info("Unexpected error while creating a Subscription for Che", "Subscription.Namespace", cheSub.Namespace, "Subscription.Name", cheSub.Name, "message", err.Error())
@@ -256,6 +488,21 @@ func (r *ReconcileCheInstallation) ensureCheSubscription(logger logr.Logger, che
 	return true, nil
 }
 
+// reportRecreation reports that the given secondary resource was just created. If the installation already went past the creation
+// of the secondary resources (ie, the CheCluster is installed or is being installed), then the resource was deleted out-of-band:
+// the Installing condition is set and an event is emitted, so that the status does not keep reporting a stale installation.
+func (r *ReconcileCheInstallation) reportRecreation(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation, kind, name string) error {
+	cheReady, found := condition.FindConditionByType(cheInstallation.Status.Conditions, v1alpha1.CheReady)
+	if !found || (cheReady.Status != corev1.ConditionTrue && cheReady.Reason != v1alpha1.InstallingReason) {
+		// first installation
+		return nil
+	}
+	msg := fmt.Sprintf("recreated %s '%s'", kind, name)
+	logger.Info("Recreated a resource which was deleted", "Kind", kind, "Name", name)
+	r.recorder.Eventf(cheInstallation, corev1.EventTypeWarning, RecreatedEventReason, "Recreated the %s '%s' in namespace '%s' which was deleted", kind, name, cheInstallation.Spec.CheOperatorSpec.Namespace)
+	return r.statusUpdate(logger, cheInstallation, r.setStatusCheInstallationInstalling, msg)
+}
+
 // ensureWatchCheCluster adds watch for CheCluster resource if CheCluster CRD is installed else return requeue with true
 // CheCluster CRD may takes time to get installed until CheOperator is installed successfully
 // Once watch added for CheCluster, sub-sequent calls to ensureWatchCheCluster() will do nothing
@@ -285,8 +532,18 @@ func (r *ReconcileCheInstallation) ensureWatchCheCluster() (bool, error) {
 	return false, nil
 }
 
-func (r *ReconcileCheInstallation) ensureCheCluster(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation) (*che.CheCluster, error) {
-	cluster := NewCheCluster(cheInstallation.Spec.CheOperatorSpec.Namespace)
+func (r *ReconcileCheInstallation) ensureCheCluster(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation, databaseCredentials, adminCredentials *credentials) (*che.CheCluster, error) {
+	clusterProxy, err := proxy.GetClusterProxy(r.client)
+	if err != nil {
+		return nil, err
+	}
+	cluster := NewCheCluster(cheInstallation)
+	toolchain.SetCommonMetadata(cluster, cheInstallation.Spec.CommonMetadata)
+	setCheClusterDatabase(cluster, cheInstallation.Spec.Database, databaseCredentials)
+	setCheClusterIdentityProvider(cluster, cheInstallation.Spec.IdentityProvider, adminCredentials)
+	if _, err := setCheClusterProxy(cluster, clusterProxy); err != nil {
+		return nil, err
+	}
 	if err := r.client.Create(context.TODO(), cluster); err != nil {
 		if errors.IsAlreadyExists(err) {
 			logger.Info("CheCluster already exists", "CheCluster.Namespace", cluster.Namespace, "CheCluster.Name", cluster.Name)
@@ -294,6 +551,48 @@ func (r *ReconcileCheInstallation) ensureCheCluster(logger logr.Logger, cheInsta
 			if err = r.client.Get(context.TODO(), types.NamespacedName{Name: CheClusterName, Namespace: cheInstallation.Spec.CheOperatorSpec.Namespace}, cluster); err != nil {
 				return nil, err
 			}
+			if !isOwnedBy(cluster.Labels, cheInstallation) {
+				return nil, fmt.Errorf("CheCluster '%s' belongs to the CheInstallation '%s'", cluster.Name, cluster.Labels[toolchain.OwnerLabelKey])
+			}
+			// the changes are patched, so that the fields of the CheCluster which are unknown to the operator are kept (eg: the trust store)
+			original := cluster.DeepCopy()
+			if _, found := cluster.Labels[toolchain.OwnerLabelKey]; !found {
+				// the CheCluster was created before the owner label was introduced: add the label so that its changes trigger a new reconcile loop
+				logger.Info("Adding the owner label on the CheCluster", "CheCluster.Namespace", cluster.Namespace, "CheCluster.Name", cluster.Name)
+				if cluster.Labels == nil {
+					cluster.Labels = map[string]string{}
+				}
+				cluster.Labels[toolchain.OwnerLabelKey] = cheInstallation.Name
+				if err := r.client.Patch(context.TODO(), cluster, client.MergeFrom(original)); err != nil {
+					return nil, err
+				}
+				original = cluster.DeepCopy()
+			}
+			changed := setCheClusterAirGap(cluster, cheInstallation.Spec.AirGap)
+			if setCheClusterDatabase(cluster, cheInstallation.Spec.Database, databaseCredentials) {
+				changed = true
+			}
+			if setCheClusterIdentityProvider(cluster, cheInstallation.Spec.IdentityProvider, adminCredentials) {
+				changed = true
+			}
+			if setCheClusterTLS(cluster, cheInstallation.Spec.TLS) {
+				changed = true
+			}
+			// the settings are also reset when the cluster-wide Proxy was removed
+			proxyChanged, err := setCheClusterProxy(cluster, clusterProxy)
+			if err != nil {
+				return nil, err
+			}
+			changed = changed || proxyChanged
+			if toolchain.SetCommonMetadata(cluster, cheInstallation.Spec.CommonMetadata) {
+				changed = true
+			}
+			if changed {
+				logger.Info("Updating the proxy, air-gap, database, identity provider, TLS settings and common metadata of the CheCluster", "CheCluster.Namespace", cluster.Namespace, "CheCluster.Name", cluster.Name)
+				if err := r.client.Patch(context.TODO(), cluster, client.MergeFrom(original)); err != nil {
+					return nil, err
+				}
+			}
 			return cluster, nil
 		}
 		logger.Info("Unexpected error while creating a CheCluster for Che", "CheCluster.Namespace", cluster.Namespace, "CheCluster.Name", cluster.Name)
@@ -303,6 +602,36 @@ func (r *ReconcileCheInstallation) ensureCheCluster(logger logr.Logger, cheInsta
 	return cluster, nil
 }
 
+// ensureCheClusterTrustStore sets the name of the ConfigMap of the trusted CA bundle as the trust store of the Che server, or removes it
+// when there is no trusted CA bundle. The `serverTrustStoreConfigMapName` field is not part of the CheCluster type known to the operator,
+// so the CheCluster is read as an unstructured object and the field is patched.
+func (r *ReconcileCheInstallation) ensureCheClusterTrustStore(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation) error {
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(orgv1.SchemeGroupVersion.WithKind("CheCluster"))
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cheInstallation.Spec.CheOperatorSpec.Namespace, Name: CheClusterName}, cluster); err != nil {
+		return err
+	}
+	current, _, err := unstructured.NestedString(cluster.Object, "spec", "server", TrustStoreField)
+	if err != nil {
+		return err
+	}
+	var expected interface{}
+	if cheInstallation.Spec.TrustedCABundle != nil {
+		expected = cabundle.ConfigMapName
+	} else if current == "" {
+		return nil
+	}
+	if current == expected {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"server": map[string]interface{}{TrustStoreField: expected}}})
+	if err != nil {
+		return err
+	}
+	logger.Info("Updating the trust store of the CheCluster", "CheCluster.Namespace", cluster.GetNamespace(), "CheCluster.Name", cluster.GetName())
+	return r.client.Patch(context.TODO(), cluster, client.RawPatch(types.MergePatchType, patch))
+}
+
 func (r *ReconcileCheInstallation) ensureCheClusterDeletion(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation) (bool, error) {
 	cluster := &orgv1.CheCluster{}
 	if err := r.client.Get(context.TODO(), types.NamespacedName{
@@ -316,6 +645,11 @@ func (r *ReconcileCheInstallation) ensureCheClusterDeletion(logger logr.Logger,
 		logger.Info("Unexpected error while creating a CheCluster for Che", "CheCluster.Namespace", cluster.Namespace, "CheCluster.Name", cluster.Name)
 		return false, err
 	}
+	if !isOwnedBy(cluster.Labels, cheInstallation) {
+		// the CheCluster belongs to another CheInstallation which targets the same namespace
+		logger.Info("CheCluster belongs to another CheInstallation", "CheCluster.Namespace", cluster.Namespace, "CheCluster.Name", cluster.Name, "Owner", cluster.Labels[toolchain.OwnerLabelKey])
+		return false, nil
+	}
 	logger.Info("Deleting CheCluster for Che", "CheCluster.Namespace", cluster.Namespace, "CheCluster.Name", cluster.Name)
 	return true, r.client.Delete(context.TODO(), cluster)
 }
@@ -347,6 +681,94 @@ func getCheClusterStatus(cluster *che.CheCluster) (bool, string) {
 	}
 }
 
+// updateInstallProgress sets the Installing condition with the given phase of the installation. If the phase did not change
+// within the install timeout, then the installation is considered as stalled: the Stalled condition is set, a warning event is
+// emitted, the metric of the stalled installations is incremented and the recovery action is run (if any). The timer is then
+// restarted, so that the escalation happens again if the installation is still stalled after another timeout.
+// Returns the delay after which the progress of the installation must be checked again.
+func (r *ReconcileCheInstallation) updateInstallProgress(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation, phase string) (time.Duration, error) {
+	status := &cheInstallation.Status
+	timeout := r.getInstallTimeout(cheInstallation)
+	now := metav1.Now()
+	newConditions := []toolchainv1alpha1.Condition{Installing(phase)}
+	changed := false
+	if status.Phase != phase || status.LastProgressTime == nil {
+		status.Phase = phase
+		status.LastProgressTime = &now
+		changed = true
+		if condition.IsTrue(status.Conditions, v1alpha1.Stalled) {
+			logger.Info("The installation is progressing again", "Phase", phase)
+			newConditions = append(newConditions, NotStalled())
+		}
+	}
+	if elapsed := time.Since(status.LastProgressTime.Time); elapsed < timeout {
+		if err := r.updateStatus(cheInstallation, changed, newConditions...); err != nil {
+			return 0, errs.Wrap(err, "failed to update the progress of the installation")
+		}
+		return timeout - elapsed, nil
+	}
+
+	msg := fmt.Sprintf("the installation did not progress since %s: %s", status.LastProgressTime.Format(time.RFC3339), phase)
+	logger.Info("The installation is stalled", "Phase", phase, "LastProgressTime", status.LastProgressTime)
+	r.recorder.Event(cheInstallation, corev1.EventTypeWarning, StalledEventReason, msg)
+	metrics.StalledInstallations.WithLabelValues("CheInstallation", cheInstallation.Name).Inc()
+	var recoveryErr error
+	if cheInstallation.Spec.StalledRecovery == v1alpha1.DeleteStuckPodsRecovery {
+		recoveryErr = r.deleteStuckPods(logger, cheInstallation)
+	}
+	status.LastProgressTime = &now
+	if err := r.updateStatus(cheInstallation, true, append(newConditions, Stalled(msg))...); err != nil {
+		return 0, errs.Wrap(err, "failed to update the progress of the installation")
+	}
+	return timeout, errs.Wrap(recoveryErr, "failed to delete the stuck pods of the CheCluster")
+}
+
+// getInstallTimeout returns the install timeout of the given CheInstallation, or the default one if it is not set
+func (r *ReconcileCheInstallation) getInstallTimeout(cheInstallation *v1alpha1.CheInstallation) time.Duration {
+	if cheInstallation.Spec.InstallTimeout != nil && cheInstallation.Spec.InstallTimeout.Duration > 0 {
+		return cheInstallation.Spec.InstallTimeout.Duration
+	}
+	return r.config.GetCheInstallTimeout()
+}
+
+// deleteStuckPods deletes the pods of the CheCluster (ie, the pods of its components) which are not ready,
+// so that they are recreated by their controller
+func (r *ReconcileCheInstallation) deleteStuckPods(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation) error {
+	selector, err := labels.Parse(fmt.Sprintf("app=%s,component", CheFlavorName))
+	if err != nil {
+		return err
+	}
+	pods := &corev1.PodList{}
+	if err := r.client.List(context.TODO(), pods, client.InNamespace(cheInstallation.Spec.CheOperatorSpec.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !isStuck(pod) {
+			continue
+		}
+		logger.Info("Deleting the stuck pod of the CheCluster", "Pod.Namespace", pod.Namespace, "Pod.Name", pod.Name, "Pod.Phase", pod.Status.Phase)
+		if err := r.client.Delete(context.TODO(), pod); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		r.recorder.Eventf(cheInstallation, corev1.EventTypeNormal, StuckPodDeletedEventReason, "Deleted the stuck pod %s/%s", pod.Namespace, pod.Name)
+	}
+	return nil
+}
+
+// isStuck returns true if the given pod is not ready, unless it completed or it is already being deleted
+func isStuck(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status != corev1.ConditionTrue
+		}
+	}
+	return true
+}
+
 // wrapErrorWithStatusUpdate wraps the error and update the install config status. If the update failed then logs the error.
 func (r *ReconcileCheInstallation) wrapErrorWithStatusUpdate(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation, updateStatus updateStatusFunc, err error, format string, args ...interface{}) error {
 	if err == nil {
@@ -369,9 +791,14 @@ func (r *ReconcileCheInstallation) statusUpdate(logger logr.Logger, cheInstallat
 }
 
 func (r *ReconcileCheInstallation) updateStatusConditions(cheInstallation *v1alpha1.CheInstallation, newConditions ...toolchainv1alpha1.Condition) error {
+	return r.updateStatus(cheInstallation, false, newConditions...)
+}
+
+// updateStatus updates the status of the given CheInstallation if the conditions changed, or if the other fields of the status changed
+func (r *ReconcileCheInstallation) updateStatus(cheInstallation *v1alpha1.CheInstallation, changed bool, newConditions ...toolchainv1alpha1.Condition) error {
 	var updated bool
 	cheInstallation.Status.Conditions, updated = condition.AddOrUpdateStatusConditions(cheInstallation.Status.Conditions, newConditions...)
-	if !updated {
+	if !updated && !changed {
 		// Nothing changed
 		return nil
 	}
@@ -386,6 +813,34 @@ func (r *ReconcileCheInstallation) setStatusCheInstallationFailed(cheInstallatio
 	return r.updateStatusConditions(cheInstallation, InstallationFailed(message))
 }
 
+func (r *ReconcileCheInstallation) setStatusCheNamespaceTerminating(cheInstallation *v1alpha1.CheInstallation, message string) error {
+	return r.updateStatusConditions(cheInstallation, NamespaceTerminating(message))
+}
+
+func (r *ReconcileCheInstallation) setStatusCheNamespaceConflict(cheInstallation *v1alpha1.CheInstallation, message string) error {
+	return r.updateStatusConditions(cheInstallation, NamespaceConflict(message))
+}
+
+func (r *ReconcileCheInstallation) setStatusCatalogSourceNotReady(cheInstallation *v1alpha1.CheInstallation, message string) error {
+	return r.updateStatusConditions(cheInstallation, CatalogSourceNotReady(message))
+}
+
+func (r *ReconcileCheInstallation) setStatusDatabaseConfigInvalid(cheInstallation *v1alpha1.CheInstallation, message string) error {
+	return r.updateStatusConditions(cheInstallation, DatabaseConfigInvalid(message))
+}
+
+func (r *ReconcileCheInstallation) setStatusIdentityProviderConfigInvalid(cheInstallation *v1alpha1.CheInstallation, message string) error {
+	return r.updateStatusConditions(cheInstallation, IdentityProviderConfigInvalid(message))
+}
+
+func (r *ReconcileCheInstallation) setStatusIdentityProviderUnreachable(cheInstallation *v1alpha1.CheInstallation, message string) error {
+	return r.updateStatusConditions(cheInstallation, IdentityProviderUnreachable(message))
+}
+
+func (r *ReconcileCheInstallation) setStatusCertificateInvalid(cheInstallation *v1alpha1.CheInstallation, message string) error {
+	return r.updateStatusConditions(cheInstallation, CertificateInvalid(message))
+}
+
 func (r *ReconcileCheInstallation) setStatusCheInstallationTerminating(cheInstallation *v1alpha1.CheInstallation, message string) error {
 	// make sure the status.CheServerURL is reset during uninstall
 	cheInstallation.Status.CheServerURL = ""
@@ -395,6 +850,51 @@ func (r *ReconcileCheInstallation) setStatusCheInstallationTerminating(cheInstal
 func (r *ReconcileCheInstallation) setStatusCheInstallationSucceeded(cheCluster *che.CheCluster) updateStatusFunc {
 	return func(cheInstallation *v1alpha1.CheInstallation, message string) error {
 		cheInstallation.Status.CheServerURL = cheCluster.Status.CheURL
-		return r.updateStatusConditions(cheInstallation, InstallationSucceeded())
+		newConditions := []toolchainv1alpha1.Condition{InstallationSucceeded()}
+		if condition.IsTrue(cheInstallation.Status.Conditions, v1alpha1.Stalled) {
+			newConditions = append(newConditions, NotStalled())
+		}
+		// the installation is not in progress anymore
+		changed := cheInstallation.Status.Phase != "" || cheInstallation.Status.LastProgressTime != nil
+		cheInstallation.Status.Phase = ""
+		cheInstallation.Status.LastProgressTime = nil
+		return r.updateStatus(cheInstallation, changed, newConditions...)
+	}
+}
+
+// mapToCheInstallations returns a mapper which maps the events on the cluster-wide resources (eg: the cluster Proxy)
+// to requests on all the CheInstallations
+func mapToCheInstallations(cl client.Reader) handler.ToRequestsFunc {
+	return func(_ handler.MapObject) []reconcile.Request {
+		installations := &v1alpha1.CheInstallationList{}
+		if err := cl.List(context.TODO(), installations); err != nil {
+			log.Error(err, "Unable to list the CheInstallations")
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(installations.Items))
+		for _, cheInstallation := range installations.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: cheInstallation.Name}})
+		}
+		return requests
+	}
+}
+
+// referencedConfigMaps returns the ConfigMap which is the source of the trusted CA bundle of the given CheInstallation, if any
+func referencedConfigMaps(cheInstallation *v1alpha1.CheInstallation) []types.NamespacedName {
+	if bundle := cheInstallation.Spec.TrustedCABundle; bundle != nil && bundle.ConfigMap != nil {
+		return []types.NamespacedName{{Namespace: bundle.ConfigMap.Namespace, Name: bundle.ConfigMap.Name}}
+	}
+	return nil
+}
+
+// getCatalogSource returns the CatalogSource from which the CodeReady Workspaces operator is installed: the CatalogSource managed
+// by the operator, or the mirror CatalogSource. Returns nil if the CheInstallation has none of them.
+func getCatalogSource(cheInstallation *v1alpha1.CheInstallation) *v1alpha1.CatalogSourceReference {
+	if cheInstallation.Spec.CatalogSource != nil {
+		return &v1alpha1.CatalogSourceReference{Namespace: catalogsource.DefaultNamespace, Name: ManagedCatalogSourceName(cheInstallation)}
+	}
+	if cheInstallation.Spec.AirGap == nil {
+		return nil
 	}
+	return cheInstallation.Spec.AirGap.CatalogSource
 }
@@ -0,0 +1,66 @@
+package cheinstallation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// credentials the user and password read from a secret (eg: the credentials of the external database)
+type credentials struct {
+	user     string
+	password string
+	// version the resource version of the secret, which changes when the credentials change
+	version string
+}
+
+// getCredentials returns the credentials in the `user` and `password` keys of the referenced secret.
+// Returns a message which describes why the credentials are invalid (eg: the secret does not exist), or an empty string if they are valid.
+func (r *ReconcileCheInstallation) getCredentials(ref v1alpha1.SecretReference, description string) (*credentials, string, error) {
+	secret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Sprintf("Secret '%s/%s' of the %s credentials not found", ref.Namespace, ref.Name, description), nil
+		}
+		return nil, "", err
+	}
+	var missing []string
+	for _, key := range []string{CredentialsUserKey, CredentialsPasswordKey} {
+		if len(secret.Data[key]) == 0 {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Sprintf("Secret '%s/%s' of the %s credentials has no '%s' key", ref.Namespace, ref.Name, description, strings.Join(missing, "', '")), nil
+	}
+	return &credentials{
+		user:     string(secret.Data[CredentialsUserKey]),
+		password: string(secret.Data[CredentialsPasswordKey]),
+		version:  secret.ResourceVersion,
+	}, "", nil
+}
+
+// referencedSecrets returns the secrets referenced by the given CheInstallation: the secrets of the credentials of the external database
+// and identity provider, and the secret of the TLS certificate (or the secret of the default certificate of the router), so that the
+// credentials and the certificates are applied again when they change
+func referencedSecrets(cheInstallation *v1alpha1.CheInstallation) []types.NamespacedName {
+	var refs []types.NamespacedName
+	if database := cheInstallation.Spec.Database; database != nil {
+		refs = append(refs, types.NamespacedName{Namespace: database.CredentialsSecret.Namespace, Name: database.CredentialsSecret.Name})
+	}
+	if identityProvider := cheInstallation.Spec.IdentityProvider; identityProvider != nil {
+		refs = append(refs, types.NamespacedName{Namespace: identityProvider.AdminCredentialsSecret.Namespace, Name: identityProvider.AdminCredentialsSecret.Name})
+	}
+	if tls := cheInstallation.Spec.TLS; tls != nil && tls.CertificateSecret != nil {
+		refs = append(refs, types.NamespacedName{Namespace: tls.CertificateSecret.Namespace, Name: tls.CertificateSecret.Name})
+	} else if tls != nil {
+		refs = append(refs, types.NamespacedName{Namespace: RouterCertificateSecretNamespace, Name: RouterCertificateSecretName})
+	}
+	return refs
+}
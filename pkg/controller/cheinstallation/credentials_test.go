@@ -0,0 +1,61 @@
+package cheinstallation
+
+import (
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestReferencedSecrets(t *testing.T) {
+	t.Run("should return the secrets of the credentials and of the TLS certificate", func(t *testing.T) {
+		// given
+		cheInstallation := NewInstallation(cfg)
+		cheInstallation.Spec.Database = &v1alpha1.CheDatabase{
+			Host:              "postgres.example.com",
+			Name:              "codeready",
+			CredentialsSecret: v1alpha1.SecretReference{Namespace: "databases", Name: "codeready-credentials"},
+		}
+		cheInstallation.Spec.IdentityProvider = &v1alpha1.CheIdentityProvider{
+			URL:                    "https://sso.example.com",
+			Realm:                  "codeready",
+			ClientID:               "codeready-public",
+			AdminCredentialsSecret: v1alpha1.SecretReference{Namespace: "sso", Name: "sso-admin"},
+		}
+		cheInstallation.Spec.TLS = &v1alpha1.CheTLS{
+			CertificateSecret: &v1alpha1.SecretReference{Namespace: "certificates", Name: "codeready-tls"},
+		}
+
+		// when
+		refs := referencedSecrets(cheInstallation)
+
+		// then
+		assert.Equal(t, []types.NamespacedName{
+			{Namespace: "databases", Name: "codeready-credentials"},
+			{Namespace: "sso", Name: "sso-admin"},
+			{Namespace: "certificates", Name: "codeready-tls"},
+		}, refs)
+	})
+
+	t.Run("should return the secret of the default certificate of the router", func(t *testing.T) {
+		// given
+		cheInstallation := NewInstallation(cfg)
+		cheInstallation.Spec.TLS = &v1alpha1.CheTLS{}
+
+		// when
+		refs := referencedSecrets(cheInstallation)
+
+		// then
+		assert.Equal(t, []types.NamespacedName{{Namespace: RouterCertificateSecretNamespace, Name: RouterCertificateSecretName}}, refs)
+	})
+
+	t.Run("should return no secret", func(t *testing.T) {
+		// when
+		refs := referencedSecrets(NewInstallation(cfg))
+
+		// then
+		assert.Empty(t, refs)
+	})
+}
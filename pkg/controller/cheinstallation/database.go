@@ -0,0 +1,16 @@
+package cheinstallation
+
+import (
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+)
+
+// getDatabaseCredentials returns the credentials of the external database of the given CheInstallation, which are read from the
+// referenced secret, or nil if the CheInstallation has no external database.
+// Returns a message which describes why the settings of the database are invalid, or an empty string if they are valid.
+func (r *ReconcileCheInstallation) getDatabaseCredentials(cheInstallation *v1alpha1.CheInstallation) (*credentials, string, error) {
+	database := cheInstallation.Spec.Database
+	if database == nil {
+		return nil, "", nil
+	}
+	return r.getCredentials(database.CredentialsSecret, "database")
+}
@@ -0,0 +1,152 @@
+package cheinstallation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/test"
+	. "github.com/codeready-toolchain/toolchain-operator/test/assert"
+
+	orgv1 "github.com/eclipse/che-operator/pkg/apis/org/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExternalDatabaseForChe(t *testing.T) {
+
+	database := &v1alpha1.CheDatabase{
+		Host:              "postgres.example.com",
+		Name:              "codeready",
+		CredentialsSecret: v1alpha1.SecretReference{Namespace: "databases", Name: "codeready-credentials"},
+	}
+
+	newCredentialsSecret := func(data map[string]string) *v1.Secret {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "databases", Name: "codeready-credentials"},
+			Data:       map[string][]byte{},
+		}
+		for k, v := range data {
+			secret.Data[k] = []byte(v)
+		}
+		return secret
+	}
+
+	newInstallation := func() *v1alpha1.CheInstallation {
+		cheInstallation := NewInstallation(cfg)
+		cheInstallation.Spec.Database = database
+		return cheInstallation
+	}
+
+	prepareReconcile := func(t *testing.T, cheInstallation *v1alpha1.CheInstallation, initObjs ...runtime.Object) (*test.FakeClient, *ReconcileCheInstallation) {
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		initObjs = append(initObjs, cheInstallation,
+			newCheNamespace(cheOperatorNS, v1.NamespaceActive),
+			NewOperatorGroup(cheInstallation),
+			NewSubscription(cfg, cheOperatorNS))
+		cl, r := configureClient(t, initObjs...)
+		r.watchCheCluster = func() error {
+			return nil
+		}
+		return cl, r
+	}
+
+	t.Run("should create the CheCluster with the external database", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation()
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := prepareReconcile(t, cheInstallation, newCredentialsSecret(map[string]string{CredentialsUserKey: "crw", CredentialsPasswordKey: "secret1"}))
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, orgv1.CheClusterSpecDB{
+			ExternalDb:          true,
+			ChePostgresHostName: "postgres.example.com",
+			ChePostgresPort:     "5432",
+			ChePostgresDb:       "codeready",
+			ChePostgresUser:     "crw",
+			ChePostgresPassword: "secret1",
+		}, AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).Get().Spec.Database)
+
+		t.Run("should update the CheCluster when the credentials are rotated", func(t *testing.T) {
+			// given
+			require.NoError(t, cl.Update(context.TODO(), newCredentialsSecret(map[string]string{CredentialsUserKey: "crw", CredentialsPasswordKey: "secret2"})))
+
+			// when
+			_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, "secret2", AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).Get().Spec.Database.ChePostgresPassword)
+		})
+
+		t.Run("should reset the database of the CheCluster when the external database is removed", func(t *testing.T) {
+			// given
+			installation := &v1alpha1.CheInstallation{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: cheInstallation.Name}, installation))
+			installation.Spec.Database = nil
+			require.NoError(t, cl.Update(context.TODO(), installation))
+
+			// when
+			_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, orgv1.CheClusterSpecDB{}, AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).Get().Spec.Database)
+		})
+	})
+
+	t.Run("should set the port of the external database", func(t *testing.T) {
+		// given
+		cluster := &orgv1.CheCluster{}
+		withPort := *database
+		withPort.Port = 5433
+
+		// when
+		changed := setCheClusterDatabase(cluster, &withPort, &credentials{user: "crw", password: "secret"})
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, "5433", cluster.Spec.Database.ChePostgresPort)
+		assert.False(t, setCheClusterDatabase(cluster, &withPort, &credentials{user: "crw", password: "secret"}))
+	})
+
+	t.Run("should report that the secret of the credentials is missing", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation()
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := prepareReconcile(t, cheInstallation)
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		AssertThatCheInstallation(t, "", cheInstallation.Name, cl).
+			HasConditions(DatabaseConfigInvalid("Secret 'databases/codeready-credentials' of the database credentials not found"))
+		AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).DoesNotExist()
+	})
+
+	t.Run("should report the missing keys of the credentials", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation()
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := prepareReconcile(t, cheInstallation, newCredentialsSecret(map[string]string{"username": "crw"}))
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		AssertThatCheInstallation(t, "", cheInstallation.Name, cl).
+			HasConditions(DatabaseConfigInvalid("Secret 'databases/codeready-credentials' of the database credentials has no 'user', 'password' key"))
+		AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).DoesNotExist()
+	})
+}
@@ -0,0 +1,114 @@
+package cheinstallation
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/cabundle"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// identityProviderTimeout the timeout of the requests which check that the external identity providers are reachable
+const identityProviderTimeout = 10 * time.Second
+
+// identityProviderMaxBackoff the maximum delay before checking again an external identity provider which is unreachable
+const identityProviderMaxBackoff = 10 * time.Minute
+
+// newIdentityProviderBackoff returns the rate limiter which gives the delays before checking again the external identity providers
+// which are unreachable: the delay starts at the `che.requeue-after` delay and doubles after each failed check
+func newIdentityProviderBackoff(config *configuration.Config) workqueue.RateLimiter {
+	return workqueue.NewItemExponentialFailureRateLimiter(config.GetCheRequeueAfter(), identityProviderMaxBackoff)
+}
+
+// getIdentityProviderCredentials returns the admin credentials of the external identity provider of the given CheInstallation, which
+// are read from the referenced secret, or nil if the CheInstallation has no external identity provider.
+// Returns a message which describes why the settings of the identity provider are invalid, or an empty string if they are valid.
+func (r *ReconcileCheInstallation) getIdentityProviderCredentials(cheInstallation *v1alpha1.CheInstallation) (*credentials, string, error) {
+	identityProvider := cheInstallation.Spec.IdentityProvider
+	if identityProvider == nil {
+		return nil, "", nil
+	}
+	return r.getCredentials(identityProvider.AdminCredentialsSecret, "identity provider admin")
+}
+
+// newIdentityProviderClient returns the client which checks that the external identity provider of the given CheInstallation is reachable.
+// Besides the system roots, the client trusts the CAs of the trusted CA bundle of the CheInstallation (if any), which are read from the
+// ConfigMap provisioned in the namespace of the Che operator, so that an identity provider signed by a corporate CA can be checked.
+func (r *ReconcileCheInstallation) newIdentityProviderClient(cheInstallation *v1alpha1.CheInstallation) (*http.Client, error) {
+	if cheInstallation.Spec.TrustedCABundle == nil {
+		return &http.Client{Timeout: identityProviderTimeout}, nil
+	}
+	bundle := &corev1.ConfigMap{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cheInstallation.Spec.CheOperatorSpec.Namespace, Name: cabundle.ConfigMapName}, bundle); err != nil {
+		return nil, err
+	}
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		// the system roots are not available on all platforms
+		rootCAs = x509.NewCertPool()
+	}
+	// the injected cluster-wide bundle may be empty until OpenShift injects it
+	rootCAs.AppendCertsFromPEM([]byte(bundle.Data[cabundle.Key]))
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+	return &http.Client{Timeout: identityProviderTimeout, Transport: transport}, nil
+}
+
+// checkIdentityProvider checks with the given client that the realm of the external identity provider of the given CheInstallation
+// is reachable. Returns a message which describes why the identity provider is unreachable, or an empty string if it is reachable
+// (or if the CheInstallation has no external identity provider).
+func checkIdentityProvider(httpClient *http.Client, cheInstallation *v1alpha1.CheInstallation) string {
+	identityProvider := cheInstallation.Spec.IdentityProvider
+	if identityProvider == nil {
+		return ""
+	}
+	endpoint := RealmEndpoint(identityProvider)
+	resp, err := httpClient.Get(endpoint)
+	if err != nil {
+		return fmt.Sprintf("identity provider endpoint '%s' is unreachable: %s", endpoint, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Sprintf("identity provider endpoint '%s' returned the status %d", endpoint, resp.StatusCode)
+	}
+	return ""
+}
+
+// identityProviderHash returns a hash of the settings of the given external identity provider and of the version of the secret of its
+// admin credentials, or an empty string if there is no external identity provider
+func identityProviderHash(identityProvider *v1alpha1.CheIdentityProvider, adminCredentials *credentials) string {
+	if identityProvider == nil || adminCredentials == nil {
+		return ""
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s/%s\n%s\n", identityProvider.URL, identityProvider.Realm, identityProvider.ClientID,
+		identityProvider.AdminCredentialsSecret.Namespace, identityProvider.AdminCredentialsSecret.Name, adminCredentials.version)
+	return fmt.Sprintf("%x", h.Sum(nil))[:16]
+}
+
+// setStatusIdentityProviderHash returns a function which sets the given hash of the settings of the external identity provider
+// in the status of the CheInstallation, once the identity provider was found reachable
+func (r *ReconcileCheInstallation) setStatusIdentityProviderHash(hash string) updateStatusFunc {
+	return func(cheInstallation *v1alpha1.CheInstallation, _ string) error {
+		changed := cheInstallation.Status.IdentityProviderHash != hash
+		cheInstallation.Status.IdentityProviderHash = hash
+		return r.updateStatus(cheInstallation, changed)
+	}
+}
+
+// RealmEndpoint returns the endpoint of the realm of the given identity provider (`<url>/auth/realms/<realm>`)
+func RealmEndpoint(identityProvider *v1alpha1.CheIdentityProvider) string {
+	return strings.TrimSuffix(identityProvider.URL, "/") + "/auth/realms/" + url.PathEscape(identityProvider.Realm)
+}
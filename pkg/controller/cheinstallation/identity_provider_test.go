@@ -0,0 +1,273 @@
+package cheinstallation
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/cabundle"
+	"github.com/codeready-toolchain/toolchain-operator/test"
+	. "github.com/codeready-toolchain/toolchain-operator/test/assert"
+
+	orgv1 "github.com/eclipse/che-operator/pkg/apis/org/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExternalIdentityProviderForChe(t *testing.T) {
+
+	// stand-in for the RH-SSO instance, which only knows the 'codeready' realm
+	sso := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/auth/realms/codeready" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer sso.Close()
+
+	newIdentityProvider := func(url, realm string) *v1alpha1.CheIdentityProvider {
+		return &v1alpha1.CheIdentityProvider{
+			URL:                    url,
+			Realm:                  realm,
+			ClientID:               "codeready-public",
+			AdminCredentialsSecret: v1alpha1.SecretReference{Namespace: "sso", Name: "sso-admin"},
+		}
+	}
+
+	newAdminSecret := func(data map[string]string) *v1.Secret {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "sso", Name: "sso-admin"},
+			Data:       map[string][]byte{},
+		}
+		for k, v := range data {
+			secret.Data[k] = []byte(v)
+		}
+		return secret
+	}
+
+	newInstallation := func(identityProvider *v1alpha1.CheIdentityProvider) *v1alpha1.CheInstallation {
+		cheInstallation := NewInstallation(cfg)
+		cheInstallation.Spec.IdentityProvider = identityProvider
+		return cheInstallation
+	}
+
+	prepareReconcile := func(t *testing.T, cheInstallation *v1alpha1.CheInstallation, initObjs ...runtime.Object) (*test.FakeClient, *ReconcileCheInstallation) {
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		initObjs = append(initObjs, cheInstallation,
+			newCheNamespace(cheOperatorNS, v1.NamespaceActive),
+			NewOperatorGroup(cheInstallation),
+			NewSubscription(cfg, cheOperatorNS))
+		cl, r := configureClient(t, initObjs...)
+		r.watchCheCluster = func() error {
+			return nil
+		}
+		return cl, r
+	}
+
+	adminSecret := map[string]string{CredentialsUserKey: "admin", CredentialsPasswordKey: "secret"}
+
+	t.Run("should create the CheCluster with the external identity provider", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(newIdentityProvider(sso.URL+"/", "codeready"))
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := prepareReconcile(t, cheInstallation, newAdminSecret(adminSecret))
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		auth := AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).Get().Spec.Auth
+		assert.True(t, auth.ExternalIdentityProvider)
+		assert.False(t, auth.OpenShiftoAuth)
+		assert.Equal(t, sso.URL+"/", auth.IdentityProviderURL)
+		assert.Equal(t, "codeready", auth.IdentityProviderRealm)
+		assert.Equal(t, "codeready-public", auth.IdentityProviderClientId)
+		assert.Equal(t, "admin", auth.IdentityProviderAdminUserName)
+		assert.Equal(t, "secret", auth.IdentityProviderPassword)
+
+		t.Run("should reset the authentication of the CheCluster when the external identity provider is removed", func(t *testing.T) {
+			// given
+			installation := &v1alpha1.CheInstallation{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: cheInstallation.Name}, installation))
+			installation.Spec.IdentityProvider = nil
+			require.NoError(t, cl.Update(context.TODO(), installation))
+
+			// when
+			_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, orgv1.CheClusterSpecAuth{OpenShiftoAuth: true}, AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).Get().Spec.Auth)
+		})
+	})
+
+	t.Run("should report that the realm of the identity provider is not found", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(newIdentityProvider(sso.URL, "unknown"))
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := prepareReconcile(t, cheInstallation, newAdminSecret(adminSecret))
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		AssertThatCheInstallation(t, "", cheInstallation.Name, cl).
+			HasConditions(IdentityProviderUnreachable(fmt.Sprintf("identity provider endpoint '%s/auth/realms/unknown' returned the status 404", sso.URL)))
+		AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).DoesNotExist()
+	})
+
+	t.Run("should report that the identity provider is unreachable", func(t *testing.T) {
+		// given
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		cheInstallation := newInstallation(newIdentityProvider(closed.URL, "codeready"))
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := prepareReconcile(t, cheInstallation, newAdminSecret(adminSecret))
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		installation := &v1alpha1.CheInstallation{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: cheInstallation.Name}, installation))
+		require.Len(t, installation.Status.Conditions, 1)
+		assert.Equal(t, v1alpha1.IdentityProviderUnreachableReason, installation.Status.Conditions[0].Reason)
+		assert.Contains(t, installation.Status.Conditions[0].Message, "is unreachable")
+		AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).DoesNotExist()
+	})
+
+	t.Run("should trust the CAs of the trusted CA bundle", func(t *testing.T) {
+		// stand-in for the RH-SSO instance with a certificate signed by a CA which is not part of the system roots
+		tlsSSO := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer tlsSSO.Close()
+		customCA := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "config", Name: "custom-ca"},
+			Data:       map[string]string{cabundle.Key: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsSSO.Certificate().Raw}))},
+		}
+
+		t.Run("when the CA is in the bundle", func(t *testing.T) {
+			// given
+			cheInstallation := newInstallation(newIdentityProvider(tlsSSO.URL, "codeready"))
+			cheInstallation.Spec.TrustedCABundle = &v1alpha1.TrustedCABundle{ConfigMap: &v1alpha1.ConfigMapReference{Namespace: "config", Name: "custom-ca"}}
+			cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+			cl, r := prepareReconcile(t, cheInstallation, newAdminSecret(adminSecret), customCA)
+
+			// when
+			_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+			// then
+			require.NoError(t, err)
+			installation := &v1alpha1.CheInstallation{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: cheInstallation.Name}, installation))
+			assert.NotEmpty(t, installation.Status.IdentityProviderHash)
+			AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).Exists()
+		})
+
+		t.Run("when there is no bundle", func(t *testing.T) {
+			// given
+			cheInstallation := newInstallation(newIdentityProvider(tlsSSO.URL, "codeready"))
+			cl, r := prepareReconcile(t, cheInstallation, newAdminSecret(adminSecret), customCA)
+
+			// when
+			result, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+			// then
+			require.NoError(t, err)
+			assert.True(t, result.Requeue)
+			installation := &v1alpha1.CheInstallation{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: cheInstallation.Name}, installation))
+			require.Len(t, installation.Status.Conditions, 1)
+			assert.Equal(t, v1alpha1.IdentityProviderUnreachableReason, installation.Status.Conditions[0].Reason)
+			assert.Contains(t, installation.Status.Conditions[0].Message, "certificate")
+		})
+	})
+
+	t.Run("should only check the identity provider when its settings or its credentials change", func(t *testing.T) {
+		// given
+		var checks int
+		counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			checks++
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer counting.Close()
+		cheInstallation := newInstallation(newIdentityProvider(counting.URL, "codeready"))
+		secret := newAdminSecret(adminSecret)
+		cl, r := prepareReconcile(t, cheInstallation, secret)
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+		require.NoError(t, err)
+		_, err = r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, checks)
+		installation := &v1alpha1.CheInstallation{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: cheInstallation.Name}, installation))
+		assert.NotEmpty(t, installation.Status.IdentityProviderHash)
+
+		t.Run("should check the identity provider again when the credentials change", func(t *testing.T) {
+			// given
+			updated := &v1.Secret{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, updated))
+			updated.Data[CredentialsPasswordKey] = []byte("rotated")
+			require.NoError(t, cl.Update(context.TODO(), updated))
+
+			// when
+			_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, 2, checks)
+		})
+	})
+
+	t.Run("should check again the unreachable identity provider with an increasing delay", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(newIdentityProvider(sso.URL, "unknown"))
+		_, r := prepareReconcile(t, cheInstallation, newAdminSecret(adminSecret))
+
+		// when
+		first, err := r.Reconcile(newReconcileRequest(cheInstallation))
+		require.NoError(t, err)
+		second, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, cfg.GetCheRequeueAfter(), first.RequeueAfter)
+		assert.Equal(t, 2*cfg.GetCheRequeueAfter(), second.RequeueAfter)
+	})
+
+	t.Run("should report that the secret of the admin credentials is missing", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(newIdentityProvider(sso.URL, "codeready"))
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := prepareReconcile(t, cheInstallation)
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, result.Requeue)
+		AssertThatCheInstallation(t, "", cheInstallation.Name, cl).
+			HasConditions(IdentityProviderConfigInvalid("Secret 'sso/sso-admin' of the identity provider admin credentials not found"))
+		AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).DoesNotExist()
+	})
+}
@@ -0,0 +1,58 @@
+package cheinstallation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// findNamespaceConflict checks that the namespace of the given CheInstallation is not targeted by another CheInstallation
+// which was created before (so that the first CheInstallation keeps the namespace).
+// Returns a message which describes the conflict, or an empty string if there is none.
+func (r *ReconcileCheInstallation) findNamespaceConflict(cheInstallation *v1alpha1.CheInstallation) (string, error) {
+	installations := &v1alpha1.CheInstallationList{}
+	if err := r.client.List(context.TODO(), installations); err != nil {
+		return "", err
+	}
+	for _, other := range installations.Items {
+		if other.Name != cheInstallation.Name && other.Spec.CheOperatorSpec.Namespace == cheInstallation.Spec.CheOperatorSpec.Namespace && createdBefore(&other, cheInstallation) {
+			return namespaceConflictMessage(cheInstallation, other.Name), nil
+		}
+	}
+	return "", nil
+}
+
+// findNamespaceOwnerConflict checks that the given existing namespace is not controlled by another CheInstallation
+// (eg: when the other CheInstallation was created first, but then changed its target namespace).
+// Returns a message which describes the conflict, or an empty string if there is none.
+func findNamespaceOwnerConflict(cheInstallation *v1alpha1.CheInstallation, ns *corev1.Namespace) string {
+	if owner := metav1.GetControllerOf(ns); owner != nil && owner.Kind == "CheInstallation" && owner.Name != cheInstallation.Name {
+		return namespaceConflictMessage(cheInstallation, owner.Name)
+	}
+	return ""
+}
+
+func namespaceConflictMessage(cheInstallation *v1alpha1.CheInstallation, other string) string {
+	return fmt.Sprintf("namespace '%s' is already used by the CheInstallation '%s'", cheInstallation.Spec.CheOperatorSpec.Namespace, other)
+}
+
+// createdBefore returns true if the first CheInstallation was created before the second one.
+// The names are compared when both CheInstallations were created at the same time.
+func createdBefore(first, second *v1alpha1.CheInstallation) bool {
+	if !first.CreationTimestamp.Equal(&second.CreationTimestamp) {
+		return first.CreationTimestamp.Before(&second.CreationTimestamp)
+	}
+	return first.Name < second.Name
+}
+
+// isOwnedBy returns true if the given labels do not hold the name of another CheInstallation than the given one.
+// The resources which were created before the owner label was introduced have no such label.
+func isOwnedBy(labels map[string]string, cheInstallation *v1alpha1.CheInstallation) bool {
+	owner, found := labels[toolchain.OwnerLabelKey]
+	return !found || owner == cheInstallation.Name
+}
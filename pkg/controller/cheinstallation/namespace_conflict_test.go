@@ -0,0 +1,185 @@
+package cheinstallation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+	. "github.com/codeready-toolchain/toolchain-operator/test/assert"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+func TestMultipleCheInstallations(t *testing.T) {
+
+	newInstallation := func(name, ns string, createdAgo time.Duration) *v1alpha1.CheInstallation {
+		cheInstallation := NewInstallation(cfg)
+		cheInstallation.Name = name
+		cheInstallation.UID = uuid.NewUUID()
+		cheInstallation.CreationTimestamp = metav1.NewTime(time.Now().Add(-createdAgo))
+		cheInstallation.Spec.CheOperatorSpec.Namespace = ns
+		return cheInstallation
+	}
+
+	t.Run("should install in different namespaces", func(t *testing.T) {
+		// given
+		tenant1 := newInstallation("tenant1", "tenant1-workspaces", time.Hour)
+		tenant2 := newInstallation("tenant2", "tenant2-workspaces", time.Minute)
+		cl, r := configureClient(t, tenant1, tenant2,
+			newCheNamespace("tenant1-workspaces", v1.NamespaceActive),
+			newCheNamespace("tenant2-workspaces", v1.NamespaceActive))
+
+		for _, cheInstallation := range []*v1alpha1.CheInstallation{tenant1, tenant2} {
+			// when
+			for i := 0; i < 2; i++ { // operator group and subscription
+				_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+				require.NoError(t, err)
+			}
+
+			// then
+			cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+			AssertThatNamespace(t, cheOperatorNS, cl).Exists()
+			AssertThatOperatorGroup(t, cheOperatorNS, cheInstallation.Name, cl).
+				HasSpec(NewOperatorGroup(cheInstallation).Spec)
+			AssertThatSubscription(t, cheOperatorNS, SubscriptionName, cl).Exists()
+			AssertThatCheInstallation(t, "", cheInstallation.Name, cl).
+				HasNoCondition()
+		}
+	})
+
+	t.Run("should create the CheCluster with the label of its owner", func(t *testing.T) {
+		// given
+		tenant2 := newInstallation("tenant2", "tenant2-workspaces", time.Minute)
+		cl, r := configureClient(t, tenant2,
+			newCheNamespace("tenant2-workspaces", v1.NamespaceActive),
+			NewOperatorGroup(tenant2),
+			NewSubscription(cfg, "tenant2-workspaces"))
+		r.watchCheCluster = func() error {
+			return nil
+		}
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tenant2))
+
+		// then
+		require.NoError(t, err)
+		AssertThatCheCluster(t, "tenant2-workspaces", CheClusterName, cl).
+			HasLabels(toolchain.LabelsWithOwner("tenant2"))
+	})
+
+	t.Run("should add the owner label on an existing CheCluster", func(t *testing.T) {
+		// given
+		cheInstallation := NewInstallation(cfg)
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cheCluster := NewCheCluster(cheInstallation)
+		cheCluster.Labels = toolchain.Labels()
+		cl, r := configureClient(t, cheInstallation,
+			newCheNamespace(cheOperatorNS, v1.NamespaceActive),
+			NewOperatorGroup(cheInstallation),
+			NewSubscription(cfg, cheOperatorNS),
+			cheCluster)
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).
+			HasLabels(toolchain.LabelsWithOwner(cheInstallation.Name))
+	})
+
+	t.Run("should reject the namespace of an older CheInstallation", func(t *testing.T) {
+		// given
+		tenant1 := newInstallation("tenant1", "shared-workspaces", time.Hour)
+		tenant2 := newInstallation("tenant2", "shared-workspaces", time.Minute)
+		cl, r := configureClient(t, tenant1, tenant2)
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(tenant2))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		AssertThatNamespace(t, "shared-workspaces", cl).DoesNotExist()
+		AssertThatCheInstallation(t, "", tenant2.Name, cl).
+			HasConditions(NamespaceConflict("namespace 'shared-workspaces' is already used by the CheInstallation 'tenant1'"))
+
+		t.Run("older CheInstallation keeps the namespace", func(t *testing.T) {
+			// when
+			_, err := r.Reconcile(newReconcileRequest(tenant1))
+
+			// then
+			require.NoError(t, err)
+			AssertThatNamespace(t, "shared-workspaces", cl).Exists()
+			AssertThatCheInstallation(t, "", tenant1.Name, cl).
+				HasNoCondition()
+		})
+	})
+
+	t.Run("should compare the names of CheInstallations created at the same time", func(t *testing.T) {
+		// given
+		tenant1 := newInstallation("tenant1", "shared-workspaces", time.Hour)
+		tenant2 := tenant1.DeepCopy()
+		tenant2.Name = "tenant2"
+
+		// then
+		assert.True(t, createdBefore(tenant1, tenant2))
+		assert.False(t, createdBefore(tenant2, tenant1))
+	})
+
+	t.Run("should reject a namespace controlled by another CheInstallation", func(t *testing.T) {
+		// given
+		tenant1 := newInstallation("tenant1", "tenant1-workspaces", time.Hour)
+		tenant2 := newInstallation("tenant2", "tenant1-workspaces", time.Minute)
+		ns := newCheNamespace("tenant1-workspaces", v1.NamespaceActive)
+		ns.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(tenant1, v1alpha1.SchemeGroupVersion.WithKind("CheInstallation"))}
+		cl, r := configureClient(t, tenant2, ns) // tenant1 targets another namespace now
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(tenant2))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		AssertThatOperatorGroup(t, "tenant1-workspaces", tenant2.Name, cl).DoesNotExist()
+		AssertThatCheInstallation(t, "", tenant2.Name, cl).
+			HasConditions(NamespaceConflict("namespace 'tenant1-workspaces' is already used by the CheInstallation 'tenant1'"))
+	})
+
+	t.Run("should not delete the CheCluster of another CheInstallation", func(t *testing.T) {
+		// given
+		tenant1 := newInstallation("tenant1", "shared-workspaces", time.Hour)
+		tenant2 := newInstallation("tenant2", "shared-workspaces", time.Minute)
+		tenant2.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		cl, r := configureClient(t, tenant1, tenant2, NewCheCluster(tenant1))
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tenant2))
+
+		// then
+		require.NoError(t, err)
+		AssertThatCheCluster(t, "shared-workspaces", CheClusterName, cl).Exists()
+		AssertThatCheInstallation(t, "", tenant2.Name, cl).
+			HasNoFinalizer()
+	})
+
+	t.Run("should fail when the CheCluster belongs to another CheInstallation", func(t *testing.T) {
+		// given
+		tenant1 := newInstallation("tenant1", "shared-workspaces", time.Hour)
+		tenant2 := newInstallation("tenant2", "shared-workspaces", time.Minute)
+		cl, r := configureClient(t, tenant2, NewCheCluster(tenant1))
+
+		// when
+		_, err := r.ensureCheCluster(testLogger(), tenant2, nil, nil)
+
+		// then
+		require.EqualError(t, err, "CheCluster 'codeready-workspaces' belongs to the CheInstallation 'tenant1'")
+		AssertThatCheCluster(t, "shared-workspaces", CheClusterName, cl).
+			HasLabels(toolchain.LabelsWithOwner("tenant1"))
+	})
+}
@@ -0,0 +1,153 @@
+package cheinstallation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// policyResource a ResourceQuota, LimitRange or NetworkPolicy which enforces the namespace policy of a CheInstallation
+type policyResource struct {
+	kind     string
+	expected object
+	existing object
+	// enabled true if the resource is required by the namespace policy, false if it must be deleted
+	enabled bool
+	// updateSpec sets the spec of the expected resource on the existing one. Returns true if the spec of the existing resource changed.
+	updateSpec func() bool
+}
+
+// ensureNamespacePolicy creates, updates or deletes the ResourceQuota, the LimitRange and the NetworkPolicies of the namespace
+// of the Che operator, according to the namespace policy of the given CheInstallation
+func (r *ReconcileCheInstallation) ensureNamespacePolicy(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation) error {
+	policy := cheInstallation.Spec.NamespacePolicy
+	if policy == nil {
+		policy = &v1alpha1.NamespacePolicy{}
+	}
+	isolated := policy.NetworkIsolation == v1alpha1.IsolatedNetwork
+
+	expectedQuota, existingQuota := NewResourceQuota(cheInstallation), &corev1.ResourceQuota{}
+	expectedLimitRange, existingLimitRange := NewLimitRange(cheInstallation), &corev1.LimitRange{}
+	expectedIsolation, existingIsolation := NewIsolationNetworkPolicy(cheInstallation), &networkingv1.NetworkPolicy{}
+	expectedKeycloak, existingKeycloak := NewKeycloakNetworkPolicy(cheInstallation), &networkingv1.NetworkPolicy{}
+
+	resources := []policyResource{
+		{
+			kind:     "ResourceQuota",
+			expected: expectedQuota,
+			existing: existingQuota,
+			enabled:  len(policy.Quota) > 0,
+			updateSpec: func() bool {
+				// the quantities are compared semantically, since they may be serialized in another format by the API server
+				if equality.Semantic.DeepEqual(existingQuota.Spec, expectedQuota.Spec) {
+					return false
+				}
+				existingQuota.Spec = expectedQuota.Spec
+				return true
+			},
+		},
+		{
+			kind:     "LimitRange",
+			expected: expectedLimitRange,
+			existing: existingLimitRange,
+			enabled:  policy.DefaultLimits != nil,
+			updateSpec: func() bool {
+				if equality.Semantic.DeepEqual(existingLimitRange.Spec, expectedLimitRange.Spec) {
+					return false
+				}
+				existingLimitRange.Spec = expectedLimitRange.Spec
+				return true
+			},
+		},
+		{
+			kind:     "NetworkPolicy",
+			expected: expectedIsolation,
+			existing: existingIsolation,
+			enabled:  isolated,
+			updateSpec: func() bool {
+				if equality.Semantic.DeepEqual(existingIsolation.Spec, expectedIsolation.Spec) {
+					return false
+				}
+				existingIsolation.Spec = expectedIsolation.Spec
+				return true
+			},
+		},
+		{
+			kind:     "NetworkPolicy",
+			expected: expectedKeycloak,
+			existing: existingKeycloak,
+			enabled:  isolated,
+			updateSpec: func() bool {
+				if equality.Semantic.DeepEqual(existingKeycloak.Spec, expectedKeycloak.Spec) {
+					return false
+				}
+				existingKeycloak.Spec = expectedKeycloak.Spec
+				return true
+			},
+		},
+	}
+	for _, resource := range resources {
+		if err := r.ensurePolicyResource(logger, cheInstallation, resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensurePolicyResource creates or updates the given resource if it is enabled, or deletes it otherwise
+func (r *ReconcileCheInstallation) ensurePolicyResource(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation, resource policyResource) error {
+	namespace, name := resource.expected.GetNamespace(), resource.expected.GetName()
+	toolchain.SetCommonMetadata(resource.expected, cheInstallation.Spec.CommonMetadata)
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, resource.existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if !resource.enabled {
+			return nil
+		}
+		if err := controllerutil.SetControllerReference(cheInstallation, resource.expected, r.scheme); err != nil {
+			return err
+		}
+		logger.Info("Creating the "+resource.kind+" of the namespace policy", "Namespace", namespace, "Name", name)
+		return r.client.Create(context.TODO(), resource.expected)
+	}
+	if !metav1.IsControlledBy(resource.existing, cheInstallation) {
+		if !resource.enabled {
+			// not created by the operator, hence left as is
+			return nil
+		}
+		return fmt.Errorf("%s '%s/%s' is not owned by '%s'", resource.kind, namespace, name, cheInstallation.Name)
+	}
+	if !resource.enabled {
+		logger.Info("Deleting the "+resource.kind+" of the namespace policy", "Namespace", namespace, "Name", name)
+		if err := r.client.Delete(context.TODO(), resource.existing); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	changed := resource.updateSpec()
+	if !reflect.DeepEqual(resource.existing.GetLabels(), resource.expected.GetLabels()) {
+		resource.existing.SetLabels(resource.expected.GetLabels())
+		changed = true
+	}
+	if toolchain.SetCommonMetadata(resource.existing, cheInstallation.Spec.CommonMetadata) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	logger.Info("Updating the "+resource.kind+" of the namespace policy", "Namespace", namespace, "Name", name)
+	return r.client.Update(context.TODO(), resource.existing)
+}
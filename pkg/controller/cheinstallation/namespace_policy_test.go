@@ -0,0 +1,206 @@
+package cheinstallation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+	"github.com/codeready-toolchain/toolchain-operator/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+func TestNamespacePolicy(t *testing.T) {
+
+	newInstallation := func(policy *v1alpha1.NamespacePolicy) *v1alpha1.CheInstallation {
+		cheInstallation := NewInstallation(cfg)
+		cheInstallation.UID = uuid.NewUUID()
+		cheInstallation.Spec.NamespacePolicy = policy
+		return cheInstallation
+	}
+
+	fullPolicy := func() *v1alpha1.NamespacePolicy {
+		return &v1alpha1.NamespacePolicy{
+			Quota: v1.ResourceList{
+				v1.ResourceRequestsCPU:            resource.MustParse("4"),
+				v1.ResourceLimitsMemory:           resource.MustParse("16Gi"),
+				v1.ResourcePersistentVolumeClaims: resource.MustParse("10"),
+			},
+			DefaultLimits: &v1alpha1.DefaultLimits{
+				Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("512Mi")},
+				Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+			NetworkIsolation: v1alpha1.IsolatedNetwork,
+		}
+	}
+
+	t.Run("should create the ResourceQuota, the LimitRange and the NetworkPolicies", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(fullPolicy())
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := configureClient(t, cheInstallation, newCheNamespace(cheOperatorNS, v1.NamespaceActive))
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		quota := &v1.ResourceQuota{}
+		getPolicyResource(t, cl, cheOperatorNS, ResourceQuotaName, quota)
+		assert.Equal(t, fullPolicy().Quota, quota.Spec.Hard)
+		assert.Equal(t, toolchain.LabelsWithOwner(cheInstallation.Name), quota.Labels)
+		assert.True(t, metav1.IsControlledBy(quota, cheInstallation))
+
+		limitRange := &v1.LimitRange{}
+		getPolicyResource(t, cl, cheOperatorNS, LimitRangeName, limitRange)
+		require.Len(t, limitRange.Spec.Limits, 1)
+		assert.Equal(t, v1.LimitTypeContainer, limitRange.Spec.Limits[0].Type)
+		assert.Equal(t, fullPolicy().DefaultLimits.Limits, limitRange.Spec.Limits[0].Default)
+		assert.Equal(t, fullPolicy().DefaultLimits.Requests, limitRange.Spec.Limits[0].DefaultRequest)
+
+		isolation := &networkingv1.NetworkPolicy{}
+		getPolicyResource(t, cl, cheOperatorNS, IsolationNetworkPolicyName, isolation)
+		assert.Equal(t, NewIsolationNetworkPolicy(cheInstallation).Spec, isolation.Spec)
+		keycloak := &networkingv1.NetworkPolicy{}
+		getPolicyResource(t, cl, cheOperatorNS, KeycloakNetworkPolicyName, keycloak)
+		assert.Equal(t, NewKeycloakNetworkPolicy(cheInstallation).Spec, keycloak.Spec)
+
+		t.Run("should update the ResourceQuota and the LimitRange when the policy changes", func(t *testing.T) {
+			// given
+			policy := fullPolicy()
+			policy.Quota[v1.ResourceRequestsCPU] = resource.MustParse("8")
+			policy.DefaultLimits.Limits[v1.ResourceMemory] = resource.MustParse("1Gi")
+			cheInstallation.Spec.NamespacePolicy = policy
+
+			// when
+			err := r.ensureNamespacePolicy(testLogger(), cheInstallation)
+
+			// then
+			require.NoError(t, err)
+			quota := &v1.ResourceQuota{}
+			getPolicyResource(t, cl, cheOperatorNS, ResourceQuotaName, quota)
+			assert.Equal(t, policy.Quota, quota.Spec.Hard)
+			limitRange := &v1.LimitRange{}
+			getPolicyResource(t, cl, cheOperatorNS, LimitRangeName, limitRange)
+			assert.Equal(t, policy.DefaultLimits.Limits, limitRange.Spec.Limits[0].Default)
+		})
+
+		t.Run("should restore the NetworkPolicy which was modified out-of-band", func(t *testing.T) {
+			// given
+			isolation := &networkingv1.NetworkPolicy{}
+			getPolicyResource(t, cl, cheOperatorNS, IsolationNetworkPolicyName, isolation)
+			isolation.Spec.Ingress = nil
+			require.NoError(t, cl.Update(context.TODO(), isolation))
+
+			// when
+			err := r.ensureNamespacePolicy(testLogger(), cheInstallation)
+
+			// then
+			require.NoError(t, err)
+			getPolicyResource(t, cl, cheOperatorNS, IsolationNetworkPolicyName, isolation)
+			assert.Equal(t, NewIsolationNetworkPolicy(cheInstallation).Spec, isolation.Spec)
+		})
+
+		t.Run("should delete the resources when the policy is removed", func(t *testing.T) {
+			// given
+			cheInstallation.Spec.NamespacePolicy = nil
+
+			// when
+			err := r.ensureNamespacePolicy(testLogger(), cheInstallation)
+
+			// then
+			require.NoError(t, err)
+			assertNoPolicyResource(t, cl, cheOperatorNS, ResourceQuotaName, &v1.ResourceQuota{})
+			assertNoPolicyResource(t, cl, cheOperatorNS, LimitRangeName, &v1.LimitRange{})
+			assertNoPolicyResource(t, cl, cheOperatorNS, IsolationNetworkPolicyName, &networkingv1.NetworkPolicy{})
+			assertNoPolicyResource(t, cl, cheOperatorNS, KeycloakNetworkPolicyName, &networkingv1.NetworkPolicy{})
+		})
+	})
+
+	t.Run("should only create the NetworkPolicies when the namespace is isolated", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(&v1alpha1.NamespacePolicy{NetworkIsolation: v1alpha1.IsolatedNetwork})
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := configureClient(t, cheInstallation, newCheNamespace(cheOperatorNS, v1.NamespaceActive))
+
+		// when
+		err := r.ensureNamespacePolicy(testLogger(), cheInstallation)
+
+		// then
+		require.NoError(t, err)
+		assertNoPolicyResource(t, cl, cheOperatorNS, ResourceQuotaName, &v1.ResourceQuota{})
+		assertNoPolicyResource(t, cl, cheOperatorNS, LimitRangeName, &v1.LimitRange{})
+		getPolicyResource(t, cl, cheOperatorNS, IsolationNetworkPolicyName, &networkingv1.NetworkPolicy{})
+		getPolicyResource(t, cl, cheOperatorNS, KeycloakNetworkPolicyName, &networkingv1.NetworkPolicy{})
+	})
+
+	t.Run("should not create any resource without a policy", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(nil)
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := configureClient(t, cheInstallation, newCheNamespace(cheOperatorNS, v1.NamespaceActive))
+
+		// when
+		err := r.ensureNamespacePolicy(testLogger(), cheInstallation)
+
+		// then
+		require.NoError(t, err)
+		assertNoPolicyResource(t, cl, cheOperatorNS, ResourceQuotaName, &v1.ResourceQuota{})
+		assertNoPolicyResource(t, cl, cheOperatorNS, LimitRangeName, &v1.LimitRange{})
+		assertNoPolicyResource(t, cl, cheOperatorNS, IsolationNetworkPolicyName, &networkingv1.NetworkPolicy{})
+		assertNoPolicyResource(t, cl, cheOperatorNS, KeycloakNetworkPolicyName, &networkingv1.NetworkPolicy{})
+	})
+
+	t.Run("should keep the resources which are not owned by the CheInstallation", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(nil)
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		quota := &v1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Namespace: cheOperatorNS, Name: ResourceQuotaName}}
+		cl, r := configureClient(t, cheInstallation, newCheNamespace(cheOperatorNS, v1.NamespaceActive), quota)
+
+		// when
+		err := r.ensureNamespacePolicy(testLogger(), cheInstallation)
+
+		// then
+		require.NoError(t, err)
+		getQuota(t, cl, cheOperatorNS)
+
+		t.Run("should fail to update them", func(t *testing.T) {
+			// given
+			cheInstallation.Spec.NamespacePolicy = fullPolicy()
+
+			// when
+			err := r.ensureNamespacePolicy(testLogger(), cheInstallation)
+
+			// then
+			require.EqualError(t, err, "ResourceQuota '"+cheOperatorNS+"/toolchain-quota' is not owned by '"+cheInstallation.Name+"'")
+			assert.Empty(t, getQuota(t, cl, cheOperatorNS).Spec.Hard)
+		})
+	})
+}
+
+func getPolicyResource(t *testing.T, cl *test.FakeClient, namespace, name string, obj runtime.Object) {
+	require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, obj))
+}
+
+func getQuota(t *testing.T, cl *test.FakeClient, namespace string) *v1.ResourceQuota {
+	quota := &v1.ResourceQuota{}
+	getPolicyResource(t, cl, namespace, ResourceQuotaName, quota)
+	return quota
+}
+
+func assertNoPolicyResource(t *testing.T, cl *test.FakeClient, namespace, name string, obj runtime.Object) {
+	err := cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, obj)
+	require.Error(t, err)
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,137 @@
+package cheinstallation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+
+	orgv1 "github.com/eclipse/che-operator/pkg/apis/org/v1"
+	"github.com/go-logr/logr"
+	olmv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1"
+	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// namespaceDeletionConditions the types of the namespace conditions which describe what blocks the deletion of a namespace
+var namespaceDeletionConditions = []corev1.NamespaceConditionType{
+	corev1.NamespaceDeletionDiscoveryFailure,
+	corev1.NamespaceDeletionContentFailure,
+	corev1.NamespaceDeletionGVParsingFailure,
+	corev1.NamespaceContentRemaining,
+	corev1.NamespaceFinalizersRemaining,
+}
+
+type object interface {
+	runtime.Object
+	metav1.Object
+}
+
+// resourceWithFinalizers a resource of the Che namespace which has finalizers, and hence which may block the deletion of the namespace
+type resourceWithFinalizers struct {
+	kind string
+	obj  object
+}
+
+// diagnoseTerminatingNamespace reports the resources and the finalizers which block the deletion of the given namespace in the status
+// of the CheInstallation. If enabled in the CheInstallation, the finalizers of the resources created by the operator are cleared once
+// the namespace has been terminating for longer than the configured timeout, so that the namespace can be deleted and then recreated.
+func (r *ReconcileCheInstallation) diagnoseTerminatingNamespace(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation, ns *corev1.Namespace) error {
+	resources, err := r.listResourcesWithFinalizers(ns.Name)
+	if err != nil {
+		return err
+	}
+	if cheInstallation.Spec.ClearStuckFinalizers && isTerminatingFor(ns, r.config.GetCheNamespaceTerminationTimeout()) {
+		var remaining []resourceWithFinalizers
+		for _, resource := range resources {
+			if resource.obj.GetLabels()[toolchain.ProviderLabelKey] != toolchain.ProviderLabelValue {
+				// not created by the operator, hence not known to be safe
+				remaining = append(remaining, resource)
+				continue
+			}
+			finalizers := resource.obj.GetFinalizers()
+			logger.Info("Clearing the finalizers of the resource which blocks the deletion of the namespace",
+				"Kind", resource.kind, "Namespace", ns.Name, "Name", resource.obj.GetName(), "Finalizers", finalizers)
+			resource.obj.SetFinalizers(nil)
+			if err := r.client.Update(context.TODO(), resource.obj); err != nil {
+				return err
+			}
+			r.recorder.Eventf(cheInstallation, corev1.EventTypeWarning, FinalizersClearedEventReason, "Cleared the finalizers %s of the %s %s/%s which blocked the deletion of the namespace",
+				strings.Join(finalizers, ", "), resource.kind, ns.Name, resource.obj.GetName())
+		}
+		resources = remaining
+	}
+	return r.setStatusCheNamespaceTerminating(cheInstallation, describeTerminatingNamespace(ns, resources))
+}
+
+// listResourcesWithFinalizers returns the resources of the Che installation in the given namespace which have finalizers
+func (r *ReconcileCheInstallation) listResourcesWithFinalizers(ns string) ([]resourceWithFinalizers, error) {
+	var resources []resourceWithFinalizers
+	appendIfFinalized := func(kind string, obj object) {
+		if len(obj.GetFinalizers()) > 0 {
+			resources = append(resources, resourceWithFinalizers{kind: kind, obj: obj})
+		}
+	}
+	cheClusters := &orgv1.CheClusterList{}
+	if err := r.client.List(context.TODO(), cheClusters, client.InNamespace(ns)); err != nil && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	for i := range cheClusters.Items {
+		appendIfFinalized("CheCluster", &cheClusters.Items[i])
+	}
+	subscriptions := &olmv1alpha1.SubscriptionList{}
+	if err := r.client.List(context.TODO(), subscriptions, client.InNamespace(ns)); err != nil {
+		return nil, err
+	}
+	for i := range subscriptions.Items {
+		appendIfFinalized("Subscription", &subscriptions.Items[i])
+	}
+	operatorGroups := &olmv1.OperatorGroupList{}
+	if err := r.client.List(context.TODO(), operatorGroups, client.InNamespace(ns)); err != nil {
+		return nil, err
+	}
+	for i := range operatorGroups.Items {
+		appendIfFinalized("OperatorGroup", &operatorGroups.Items[i])
+	}
+	return resources, nil
+}
+
+// isTerminatingFor returns true if the given namespace has been terminating for at least the given duration
+func isTerminatingFor(ns *corev1.Namespace, timeout time.Duration) bool {
+	return ns.DeletionTimestamp != nil && time.Since(ns.DeletionTimestamp.Time) >= timeout
+}
+
+// describeTerminatingNamespace returns a message with the reasons why the deletion of the given namespace is blocked,
+// as reported in the conditions of the namespace, along with the given resources and their finalizers
+func describeTerminatingNamespace(ns *corev1.Namespace, resources []resourceWithFinalizers) string {
+	msg := fmt.Sprintf("namespace '%s' is terminating", ns.Name)
+	var details []string
+	for _, cond := range ns.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue && containsConditionType(namespaceDeletionConditions, cond.Type) {
+			details = append(details, cond.Message)
+		}
+	}
+	for _, resource := range resources {
+		details = append(details, fmt.Sprintf("%s '%s' has finalizers: %s", resource.kind, resource.obj.GetName(), strings.Join(resource.obj.GetFinalizers(), ", ")))
+	}
+	if len(details) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", msg, strings.Join(details, "; "))
+}
+
+func containsConditionType(types []corev1.NamespaceConditionType, t corev1.NamespaceConditionType) bool {
+	for _, typ := range types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package cheinstallation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	. "github.com/codeready-toolchain/toolchain-operator/test/assert"
+
+	orgv1 "github.com/eclipse/che-operator/pkg/apis/org/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestDiagnoseTerminatingNamespace(t *testing.T) {
+
+	cheOauthFinalizer := "oauthclients.finalizers.che.eclipse.org"
+
+	newTerminatingNamespace := func(ns string, since time.Duration) *v1.Namespace {
+		terminating := newCheNamespace(ns, v1.NamespaceTerminating)
+		terminating.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-since)}
+		terminating.Status.Conditions = []v1.NamespaceCondition{
+			{
+				Type:    v1.NamespaceContentRemaining,
+				Status:  v1.ConditionTrue,
+				Message: "Some resources are remaining: checlusters.org.eclipse.che has 1 resource instances",
+			},
+			{
+				Type:    v1.NamespaceFinalizersRemaining,
+				Status:  v1.ConditionTrue,
+				Message: "Some content in the namespace has finalizers remaining: oauthclients.finalizers.che.eclipse.org in 1 resource instances",
+			},
+			{
+				Type:   v1.NamespaceDeletionDiscoveryFailure,
+				Status: v1.ConditionFalse,
+			},
+		}
+		return terminating
+	}
+
+	newFinalizedCheCluster := func(cheInstallation *v1alpha1.CheInstallation) *orgv1.CheCluster {
+		cheCluster := NewCheCluster(cheInstallation)
+		cheCluster.Finalizers = []string{cheOauthFinalizer}
+		return cheCluster
+	}
+
+	expectedMessage := "namespace 'toolchain-workspaces' is terminating: " +
+		"Some resources are remaining: checlusters.org.eclipse.che has 1 resource instances; " +
+		"Some content in the namespace has finalizers remaining: oauthclients.finalizers.che.eclipse.org in 1 resource instances; " +
+		"CheCluster 'codeready-workspaces' has finalizers: oauthclients.finalizers.che.eclipse.org"
+
+	t.Run("should report the resources and finalizers which block the deletion", func(t *testing.T) {
+		// given
+		cheInstallation := NewInstallation(cfg)
+		ns := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cheCluster := newFinalizedCheCluster(cheInstallation)
+		cl, r := configureClient(t, cheInstallation, newTerminatingNamespace(ns, time.Hour), cheCluster)
+
+		// when
+		requeue, err := r.ensureCheNamespace(testLogger(), cheInstallation)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, requeue)
+		AssertThatCheInstallation(t, cheInstallation.Namespace, cheInstallation.Name, cl).
+			HasConditions(NamespaceTerminating(expectedMessage))
+		// the finalizers are not cleared since it is not enabled
+		assertCheClusterFinalizers(t, cl, ns, cheOauthFinalizer)
+	})
+
+	t.Run("should clear the finalizers of the toolchain resources when enabled", func(t *testing.T) {
+		// given
+		cheInstallation := NewInstallation(cfg)
+		cheInstallation.Spec.ClearStuckFinalizers = true
+		ns := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cheCluster := newFinalizedCheCluster(cheInstallation)
+		sub := NewSubscription(cfg, ns)
+		sub.Labels = nil // not created by the operator
+		sub.Finalizers = []string{"example.com/finalizer"}
+		cl, r := configureClient(t, cheInstallation, newTerminatingNamespace(ns, 2*cfg.GetCheNamespaceTerminationTimeout()), cheCluster, sub)
+		recorder := record.NewFakeRecorder(10)
+		r.recorder = recorder
+
+		// when
+		requeue, err := r.ensureCheNamespace(testLogger(), cheInstallation)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, requeue)
+		assertCheClusterFinalizers(t, cl, ns)
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Warning FinalizersCleared Cleared the finalizers oauthclients.finalizers.che.eclipse.org of the CheCluster toolchain-workspaces/codeready-workspaces")
+		AssertThatCheInstallation(t, cheInstallation.Namespace, cheInstallation.Name, cl).
+			HasConditions(NamespaceTerminating("namespace 'toolchain-workspaces' is terminating: " +
+				"Some resources are remaining: checlusters.org.eclipse.che has 1 resource instances; " +
+				"Some content in the namespace has finalizers remaining: oauthclients.finalizers.che.eclipse.org in 1 resource instances; " +
+				"Subscription 'codeready-workspaces' has finalizers: example.com/finalizer"))
+	})
+
+	t.Run("should not clear the finalizers before the timeout", func(t *testing.T) {
+		// given
+		cheInstallation := NewInstallation(cfg)
+		cheInstallation.Spec.ClearStuckFinalizers = true
+		ns := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cheCluster := newFinalizedCheCluster(cheInstallation)
+		cl, r := configureClient(t, cheInstallation, newTerminatingNamespace(ns, time.Second), cheCluster)
+
+		// when
+		_, err := r.ensureCheNamespace(testLogger(), cheInstallation)
+
+		// then
+		require.NoError(t, err)
+		assertCheClusterFinalizers(t, cl, ns, cheOauthFinalizer)
+		AssertThatCheInstallation(t, cheInstallation.Namespace, cheInstallation.Name, cl).
+			HasConditions(NamespaceTerminating(expectedMessage))
+	})
+
+	t.Run("should fail when the finalizers cannot be cleared", func(t *testing.T) {
+		// given
+		cheInstallation := NewInstallation(cfg)
+		cheInstallation.Spec.ClearStuckFinalizers = true
+		ns := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := configureClient(t, cheInstallation, newTerminatingNamespace(ns, time.Hour), newFinalizedCheCluster(cheInstallation))
+		cl.MockUpdate = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+			if _, ok := obj.(*orgv1.CheCluster); ok {
+				return errors.New("unable to update the CheCluster")
+			}
+			return cl.Client.Update(ctx, obj, opts...)
+		}
+
+		// when
+		_, err := r.ensureCheNamespace(testLogger(), cheInstallation)
+
+		// then
+		require.EqualError(t, err, "unable to update the CheCluster")
+		assertCheClusterFinalizers(t, cl, ns, cheOauthFinalizer)
+	})
+
+	t.Run("should fail when the resources cannot be listed", func(t *testing.T) {
+		// given
+		cheInstallation := NewInstallation(cfg)
+		ns := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := configureClient(t, cheInstallation, newTerminatingNamespace(ns, time.Hour))
+		cl.MockList = func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+			return errors.New("unable to list")
+		}
+
+		// when
+		_, err := r.ensureCheNamespace(testLogger(), cheInstallation)
+
+		// then
+		require.EqualError(t, err, "unable to list")
+	})
+}
+
+func TestDescribeTerminatingNamespace(t *testing.T) {
+	// given
+	ns := newCheNamespace("toolchain-workspaces", v1.NamespaceTerminating)
+
+	// when
+	msg := describeTerminatingNamespace(ns, nil)
+
+	// then
+	assert.Equal(t, "namespace 'toolchain-workspaces' is terminating", msg)
+}
+
+func assertCheClusterFinalizers(t *testing.T, cl client.Client, ns string, finalizers ...string) {
+	cheCluster := &orgv1.CheCluster{}
+	err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: CheClusterName}, cheCluster)
+	require.NoError(t, err)
+	if len(finalizers) == 0 {
+		assert.Empty(t, cheCluster.Finalizers)
+	} else {
+		assert.Equal(t, finalizers, cheCluster.Finalizers)
+	}
+}
@@ -0,0 +1,200 @@
+package cheinstallation
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-common/pkg/condition"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ensureTLSCertificate copies the secret of the TLS certificate of the given CheInstallation in the namespace of the Che operator, and
+// returns the certificate so that its expiry can be monitored. When the default certificate of the router is used, the certificate is
+// read from the secret of the router, and the copy is deleted (as well as when TLS is disabled).
+// Returns a message which describes why the certificate is invalid, or an empty string if it is valid.
+func (r *ReconcileCheInstallation) ensureTLSCertificate(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation) (*x509.Certificate, string, error) {
+	tls := cheInstallation.Spec.TLS
+	if tls == nil || tls.CertificateSecret == nil {
+		if err := r.deleteTLSSecret(logger, cheInstallation); err != nil {
+			return nil, "", err
+		}
+		if tls == nil {
+			return nil, "", nil
+		}
+		_, certificate, invalid, err := r.readCertificate(RouterCertificateSecretNamespace, RouterCertificateSecretName)
+		if err != nil {
+			return nil, "", err
+		} else if invalid != "" {
+			// the router may use a custom default certificate, in which case its expiry can't be monitored
+			logger.Info("Unable to read the default certificate of the router", "message", invalid)
+			return nil, "", nil
+		}
+		return certificate, "", nil
+	}
+	source, certificate, invalid, err := r.readCertificate(tls.CertificateSecret.Namespace, tls.CertificateSecret.Name)
+	if err != nil || invalid != "" {
+		return nil, invalid, err
+	}
+	return certificate, "", r.ensureTLSSecret(logger, cheInstallation, source)
+}
+
+// readCertificate returns the secret with the given namespace and name, along with the first certificate in its `tls.crt` key.
+// Returns a message which describes why the certificate is invalid (eg: the secret does not exist), or an empty string if it is valid.
+func (r *ReconcileCheInstallation) readCertificate(namespace, name string) (*corev1.Secret, *x509.Certificate, string, error) {
+	secret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil, fmt.Sprintf("Secret '%s/%s' of the TLS certificate not found", namespace, name), nil
+		}
+		return nil, nil, "", err
+	}
+	var missing []string
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		if len(secret.Data[key]) == 0 {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Sprintf("Secret '%s/%s' of the TLS certificate has no '%s' key", namespace, name, strings.Join(missing, "', '")), nil
+	}
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, nil, fmt.Sprintf("Secret '%s/%s' of the TLS certificate has no PEM-encoded certificate in its '%s' key", namespace, name, corev1.TLSCertKey), nil
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Sprintf("Secret '%s/%s' of the TLS certificate has an invalid certificate: %s", namespace, name, err.Error()), nil
+	}
+	return secret, certificate, "", nil
+}
+
+// ensureTLSSecret creates or updates the copy of the given secret of the TLS certificate in the namespace of the Che operator
+func (r *ReconcileCheInstallation) ensureTLSSecret(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation, source *corev1.Secret) error {
+	expected := NewTLSSecret(cheInstallation, source.Data[corev1.TLSCertKey], source.Data[corev1.TLSPrivateKeyKey])
+	toolchain.SetCommonMetadata(expected, cheInstallation.Spec.CommonMetadata)
+	existing := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: expected.Namespace, Name: expected.Name}, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if err := controllerutil.SetControllerReference(cheInstallation, expected, r.scheme); err != nil {
+			return err
+		}
+		logger.Info("Creating the secret of the TLS certificate", "Secret.Namespace", expected.Namespace, "Secret.Name", expected.Name)
+		return r.client.Create(context.TODO(), expected)
+	}
+	if !metav1.IsControlledBy(existing, cheInstallation) {
+		return fmt.Errorf("Secret '%s/%s' is not owned by '%s'", existing.Namespace, existing.Name, cheInstallation.Name)
+	}
+	changed := false
+	if !reflect.DeepEqual(existing.Data, expected.Data) {
+		existing.Data = expected.Data
+		changed = true
+	}
+	if !reflect.DeepEqual(existing.Labels, expected.Labels) {
+		existing.Labels = expected.Labels
+		changed = true
+	}
+	if toolchain.SetCommonMetadata(existing, cheInstallation.Spec.CommonMetadata) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	logger.Info("Updating the secret of the TLS certificate", "Secret.Namespace", existing.Namespace, "Secret.Name", existing.Name)
+	return r.client.Update(context.TODO(), existing)
+}
+
+// deleteTLSSecret deletes the copy of the secret of the TLS certificate in the namespace of the Che operator, if it was created by the operator
+func (r *ReconcileCheInstallation) deleteTLSSecret(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation) error {
+	secret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cheInstallation.Spec.CheOperatorSpec.Namespace, Name: TLSSecretName}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(secret, cheInstallation) {
+		// not created by the operator, hence left as is
+		return nil
+	}
+	logger.Info("Deleting the secret of the TLS certificate", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+	if err := r.client.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// updateCertificateExpiry sets the expiry time of the given TLS certificate in the status of the given CheInstallation, along with the
+// CertificateExpiringSoon condition. A warning event is emitted when the certificate starts expiring soon. The expiry time and the
+// condition are removed when there is no certificate. Returns the delay after which the expiry of the certificate must be checked again
+// (0 if there is nothing left to check until the certificate changes).
+func (r *ReconcileCheInstallation) updateCertificateExpiry(logger logr.Logger, cheInstallation *v1alpha1.CheInstallation, certificate *x509.Certificate) (time.Duration, error) {
+	status := &cheInstallation.Status
+	if certificate == nil {
+		changed := status.CertificateExpiry != nil
+		status.CertificateExpiry = nil
+		var removed bool
+		status.Conditions, removed = removeCondition(status.Conditions, v1alpha1.CertificateExpiringSoon)
+		if !changed && !removed {
+			return 0, nil
+		}
+		return 0, r.updateStatus(cheInstallation, true)
+	}
+
+	expiry := metav1.NewTime(certificate.NotAfter)
+	changed := status.CertificateExpiry == nil || !status.CertificateExpiry.Equal(&expiry)
+	status.CertificateExpiry = &expiry
+	warningPeriod := getCertificateExpiryWarningPeriod(cheInstallation)
+	untilExpiry := time.Until(certificate.NotAfter)
+	var cond toolchainv1alpha1.Condition
+	var checkAfter time.Duration
+	switch {
+	case untilExpiry <= 0:
+		cond = CertificateExpired(fmt.Sprintf("the TLS certificate expired on %s", certificate.NotAfter.UTC().Format(time.RFC3339)))
+	case untilExpiry <= warningPeriod:
+		cond = CertificateExpiringSoon(fmt.Sprintf("the TLS certificate expires on %s", certificate.NotAfter.UTC().Format(time.RFC3339)))
+		checkAfter = untilExpiry
+	default:
+		cond = CertificateNotExpiringSoon()
+		checkAfter = untilExpiry - warningPeriod
+	}
+	if cond.Status == corev1.ConditionTrue && !condition.HasConditionReason(status.Conditions, v1alpha1.CertificateExpiringSoon, cond.Reason) {
+		logger.Info("The TLS certificate is expiring", "Expiry", certificate.NotAfter)
+		r.recorder.Event(cheInstallation, corev1.EventTypeWarning, CertificateExpiringEventReason, cond.Message)
+	}
+	return checkAfter, r.updateStatus(cheInstallation, changed, cond)
+}
+
+// getCertificateExpiryWarningPeriod returns the expiry warning period of the TLS certificate of the given CheInstallation,
+// or the default one if it is not set
+func getCertificateExpiryWarningPeriod(cheInstallation *v1alpha1.CheInstallation) time.Duration {
+	if tls := cheInstallation.Spec.TLS; tls != nil && tls.ExpiryWarningPeriod != nil && tls.ExpiryWarningPeriod.Duration > 0 {
+		return tls.ExpiryWarningPeriod.Duration
+	}
+	return DefaultCertificateExpiryWarningPeriod
+}
+
+// removeCondition removes the condition of the given type from the given conditions. Returns true if the condition was found.
+func removeCondition(conditions []toolchainv1alpha1.Condition, conditionType toolchainv1alpha1.ConditionType) ([]toolchainv1alpha1.Condition, bool) {
+	for i, cond := range conditions {
+		if cond.Type == conditionType {
+			return append(conditions[:i:i], conditions[i+1:]...), true
+		}
+	}
+	return conditions, false
+}
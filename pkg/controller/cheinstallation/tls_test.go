@@ -0,0 +1,309 @@
+package cheinstallation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-common/pkg/condition"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/test"
+	. "github.com/codeready-toolchain/toolchain-operator/test/assert"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestTLSForChe(t *testing.T) {
+
+	tls := &v1alpha1.CheTLS{
+		CertificateSecret: &v1alpha1.SecretReference{Namespace: "certificates", Name: "codeready-tls"},
+	}
+
+	newInstallation := func(tls *v1alpha1.CheTLS) *v1alpha1.CheInstallation {
+		cheInstallation := NewInstallation(cfg)
+		cheInstallation.Spec.TLS = tls
+		return cheInstallation
+	}
+
+	prepareReconcile := func(t *testing.T, cheInstallation *v1alpha1.CheInstallation, initObjs ...runtime.Object) (*test.FakeClient, *ReconcileCheInstallation) {
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		initObjs = append(initObjs, cheInstallation,
+			newCheNamespace(cheOperatorNS, v1.NamespaceActive),
+			NewOperatorGroup(cheInstallation),
+			NewSubscription(cfg, cheOperatorNS))
+		cl, r := configureClient(t, initObjs...)
+		r.watchCheCluster = func() error {
+			return nil
+		}
+		return cl, r
+	}
+
+	getInstallation := func(t *testing.T, cl *test.FakeClient, name string) *v1alpha1.CheInstallation {
+		installation := &v1alpha1.CheInstallation{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: name}, installation))
+		return installation
+	}
+
+	t.Run("should enable TLS with the certificate of the secret", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(tls)
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		notAfter := time.Now().Add(90 * 24 * time.Hour).Truncate(time.Second)
+		source := newTLSSecret(t, "certificates", "codeready-tls", notAfter)
+		cl, r := prepareReconcile(t, cheInstallation, source)
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		server := AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).Get().Spec
+		assert.True(t, server.Server.TlsSupport)
+		assert.False(t, server.Server.SelfSignedCert)
+		assert.Equal(t, TLSSecretName, server.K8s.TlsSecretName)
+		copied := &v1.Secret{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: cheOperatorNS, Name: TLSSecretName}, copied))
+		assert.Equal(t, v1.SecretTypeTLS, copied.Type)
+		assert.Equal(t, source.Data, copied.Data)
+		installation := getInstallation(t, cl, cheInstallation.Name)
+		assert.True(t, metav1.IsControlledBy(copied, installation))
+		require.NotNil(t, installation.Status.CertificateExpiry)
+		assert.True(t, notAfter.Equal(installation.Status.CertificateExpiry.Time))
+		assert.True(t, condition.HasConditionReason(installation.Status.Conditions, v1alpha1.CertificateExpiringSoon, v1alpha1.CertificateValidReason))
+
+		t.Run("should report that the renewed certificate expires soon", func(t *testing.T) {
+			// given
+			recorder := record.NewFakeRecorder(10)
+			r.recorder = recorder
+			notAfter := time.Now().Add(10 * 24 * time.Hour).Truncate(time.Second)
+			renewed := newTLSSecret(t, "certificates", "codeready-tls", notAfter)
+			require.NoError(t, cl.Update(context.TODO(), renewed))
+
+			// when
+			_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+			// then
+			require.NoError(t, err)
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: cheOperatorNS, Name: TLSSecretName}, copied))
+			assert.Equal(t, renewed.Data, copied.Data)
+			installation := getInstallation(t, cl, cheInstallation.Name)
+			assert.True(t, notAfter.Equal(installation.Status.CertificateExpiry.Time))
+			msg := "the TLS certificate expires on " + notAfter.UTC().Format(time.RFC3339)
+			AssertThatCheInstallation(t, "", cheInstallation.Name, cl).HasConditions(Installing("Status is unknown for CheCluster 'codeready-workspaces'"), CertificateExpiringSoon(msg))
+			require.Len(t, recorder.Events, 1)
+			assert.Equal(t, "Warning CertificateExpiring "+msg, <-recorder.Events)
+		})
+
+		t.Run("should disable TLS when the TLS settings are removed", func(t *testing.T) {
+			// given
+			installation := getInstallation(t, cl, cheInstallation.Name)
+			installation.Spec.TLS = nil
+			require.NoError(t, cl.Update(context.TODO(), installation))
+
+			// when
+			_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+			// then
+			require.NoError(t, err)
+			server := AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).Get().Spec
+			assert.False(t, server.Server.TlsSupport)
+			assert.Empty(t, server.K8s.TlsSecretName)
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: cheOperatorNS, Name: TLSSecretName}, &v1.Secret{})
+			assert.True(t, errors.IsNotFound(err))
+			installation = getInstallation(t, cl, cheInstallation.Name)
+			assert.Nil(t, installation.Status.CertificateExpiry)
+			_, found := condition.FindConditionByType(installation.Status.Conditions, v1alpha1.CertificateExpiringSoon)
+			assert.False(t, found)
+		})
+	})
+
+	t.Run("should enable TLS with the default certificate of the router", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(&v1alpha1.CheTLS{SelfSignedCert: true})
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		notAfter := time.Now().Add(365 * 24 * time.Hour).Truncate(time.Second)
+		cl, r := prepareReconcile(t, cheInstallation, newTLSSecret(t, RouterCertificateSecretNamespace, RouterCertificateSecretName, notAfter))
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		server := AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).Get().Spec
+		assert.True(t, server.Server.TlsSupport)
+		assert.True(t, server.Server.SelfSignedCert)
+		assert.Empty(t, server.K8s.TlsSecretName)
+		err = cl.Get(context.TODO(), types.NamespacedName{Namespace: cheOperatorNS, Name: TLSSecretName}, &v1.Secret{})
+		assert.True(t, errors.IsNotFound(err))
+		installation := getInstallation(t, cl, cheInstallation.Name)
+		require.NotNil(t, installation.Status.CertificateExpiry)
+		assert.True(t, notAfter.Equal(installation.Status.CertificateExpiry.Time))
+	})
+
+	t.Run("should enable TLS without monitoring the expiry when the default certificate of the router is not found", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(&v1alpha1.CheTLS{})
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := prepareReconcile(t, cheInstallation)
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).Get().Spec.Server.TlsSupport)
+		installation := getInstallation(t, cl, cheInstallation.Name)
+		assert.Nil(t, installation.Status.CertificateExpiry)
+		_, found := condition.FindConditionByType(installation.Status.Conditions, v1alpha1.CertificateExpiringSoon)
+		assert.False(t, found)
+	})
+
+	t.Run("should report that the secret of the certificate is missing", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(tls)
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		cl, r := prepareReconcile(t, cheInstallation)
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		AssertThatCheInstallation(t, "", cheInstallation.Name, cl).
+			HasConditions(CertificateInvalid("Secret 'certificates/codeready-tls' of the TLS certificate not found"))
+		AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).DoesNotExist()
+	})
+
+	t.Run("should report that the certificate is not PEM-encoded", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(tls)
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		source := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "certificates", Name: "codeready-tls"},
+			Type:       v1.SecretTypeTLS,
+			Data:       map[string][]byte{v1.TLSCertKey: []byte("not a certificate"), v1.TLSPrivateKeyKey: []byte("key")},
+		}
+		cl, r := prepareReconcile(t, cheInstallation, source)
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.NoError(t, err)
+		AssertThatCheInstallation(t, "", cheInstallation.Name, cl).
+			HasConditions(CertificateInvalid("Secret 'certificates/codeready-tls' of the TLS certificate has no PEM-encoded certificate in its 'tls.crt' key"))
+		AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).DoesNotExist()
+	})
+
+	t.Run("should not override a secret which is not owned by the CheInstallation", func(t *testing.T) {
+		// given
+		cheInstallation := newInstallation(tls)
+		cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
+		existing := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: cheOperatorNS, Name: TLSSecretName}}
+		cl, r := prepareReconcile(t, cheInstallation, newTLSSecret(t, "certificates", "codeready-tls", time.Now().Add(time.Hour)), existing)
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(cheInstallation))
+
+		// then
+		require.EqualError(t, err, "failed to provision the TLS certificate in namespace toolchain-workspaces: Secret 'toolchain-workspaces/toolchain-che-tls' is not owned by 'toolchain-workspaces-installation'")
+		AssertThatCheCluster(t, cheOperatorNS, CheClusterName, cl).DoesNotExist()
+	})
+}
+
+func TestUpdateCertificateExpiry(t *testing.T) {
+
+	newCertificate := func(notAfter time.Time) *x509.Certificate {
+		return &x509.Certificate{NotAfter: notAfter}
+	}
+
+	t.Run("should check the certificate again when the warning period starts", func(t *testing.T) {
+		// given
+		cheInstallation := NewInstallation(cfg)
+		cheInstallation.Spec.TLS = &v1alpha1.CheTLS{ExpiryWarningPeriod: &metav1.Duration{Duration: 24 * time.Hour}}
+		_, r := configureClient(t, cheInstallation)
+
+		// when
+		checkAfter, err := r.updateCertificateExpiry(testLogger(), cheInstallation, newCertificate(time.Now().Add(72*time.Hour)))
+
+		// then
+		require.NoError(t, err)
+		assert.InDelta(t, 48*time.Hour, checkAfter, float64(time.Minute))
+		assert.True(t, condition.HasConditionReason(cheInstallation.Status.Conditions, v1alpha1.CertificateExpiringSoon, v1alpha1.CertificateValidReason))
+	})
+
+	t.Run("should check the certificate again when it expires", func(t *testing.T) {
+		// given
+		cheInstallation := NewInstallation(cfg)
+		_, r := configureClient(t, cheInstallation)
+
+		// when
+		checkAfter, err := r.updateCertificateExpiry(testLogger(), cheInstallation, newCertificate(time.Now().Add(72*time.Hour)))
+
+		// then
+		require.NoError(t, err)
+		assert.InDelta(t, 72*time.Hour, checkAfter, float64(time.Minute))
+		assert.True(t, condition.HasConditionReason(cheInstallation.Status.Conditions, v1alpha1.CertificateExpiringSoon, v1alpha1.CertificateExpiringReason))
+	})
+
+	t.Run("should report that the certificate expired", func(t *testing.T) {
+		// given
+		cheInstallation := NewInstallation(cfg)
+		recorder := record.NewFakeRecorder(10)
+		_, r := configureClient(t, cheInstallation)
+		r.recorder = recorder
+		notAfter := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+		// when
+		checkAfter, err := r.updateCertificateExpiry(testLogger(), cheInstallation, newCertificate(notAfter))
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, checkAfter)
+		msg := "the TLS certificate expired on " + notAfter.UTC().Format(time.RFC3339)
+		cond, found := condition.FindConditionByType(cheInstallation.Status.Conditions, v1alpha1.CertificateExpiringSoon)
+		require.True(t, found)
+		assert.Equal(t, v1alpha1.CertificateExpiredReason, cond.Reason)
+		assert.Equal(t, msg, cond.Message)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Warning CertificateExpiring "+msg, <-recorder.Events)
+	})
+}
+
+// newTLSSecret returns a new secret of type `kubernetes.io/tls` with a self-signed certificate which expires at the given time
+func newTLSSecret(t *testing.T, namespace, name string, notAfter time.Time) *v1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "codeready.apps.example.com"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Type:       v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			v1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+		},
+	}
+}
@@ -0,0 +1,180 @@
+package cheusernamespace
+
+import (
+	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/controller/cheinstallation"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// WorkspaceServiceAccountName the name of the ServiceAccount used by the workspaces (as configured by the Che operator)
+	WorkspaceServiceAccountName = "che-workspace"
+	// CheServiceAccountName the name of the ServiceAccount of the Che server running in the Che namespace
+	CheServiceAccountName = "che"
+	// ExecRoleName the name of the Role allowing to exec into the workspace pods
+	ExecRoleName = "exec"
+	// ViewRoleName the name of the Role allowing to view the workspace pods and services
+	ViewRoleName = "workspace-view"
+	// ExecRoleBindingName the name of the RoleBinding of the exec Role to the workspace ServiceAccount
+	ExecRoleBindingName = "che-workspace-exec"
+	// ViewRoleBindingName the name of the RoleBinding of the view Role to the workspace ServiceAccount
+	ViewRoleBindingName = "che-workspace-view"
+	// CheRoleBindingName the name of the RoleBinding which allows the Che server to manage the workspaces of the user
+	CheRoleBindingName = "che"
+	// AdminRoleBindingName the name of the RoleBinding which grants the user the admin role in the namespace
+	AdminRoleBindingName = "admin"
+)
+
+// NamespaceName returns the name of the namespace of the given user, following the `<username>-<flavor>` pattern
+// used by the Che server to look up the workspace namespace when the OpenShift OAuth is enabled
+func NamespaceName(username string) string {
+	return username + "-" + cheinstallation.CheFlavorName
+}
+
+// CheInstallationName returns the name of the CheInstallation which manages the workspaces of the given CheUserNamespace
+func CheInstallationName(userNamespace *v1alpha1.CheUserNamespace) string {
+	if userNamespace.Spec.CheInstallation != "" {
+		return userNamespace.Spec.CheInstallation
+	}
+	return cheinstallation.InstallationName
+}
+
+// Labels returns the toolchain labels along with the label of the given owning CheUserNamespace
+func Labels(owner string) map[string]string {
+	return toolchain.LabelsWithOwnerKey(toolchain.CheUserNamespaceOwnerLabelKey, owner)
+}
+
+// NewNamespace returns a new namespace with the toolchain labels and the label of the given owner
+func NewNamespace(owner, name string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: Labels(owner),
+		},
+	}
+}
+
+// NewWorkspaceServiceAccount returns a new ServiceAccount for the workspaces running in the given namespace
+func NewWorkspaceServiceAccount(owner, ns string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: newObjectMeta(owner, ns, WorkspaceServiceAccountName),
+	}
+}
+
+// NewExecRole returns a new Role allowing to exec into the pods of the given namespace
+func NewExecRole(owner, ns string) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: newObjectMeta(owner, ns, ExecRoleName),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods/exec"},
+				Verbs:     []string{"create"},
+			},
+		},
+	}
+}
+
+// NewViewRole returns a new Role allowing to view the pods and services of the given namespace
+func NewViewRole(owner, ns string) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: newObjectMeta(owner, ns, ViewRoleName),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods", "services"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}
+}
+
+// NewRoleBindings returns the RoleBindings expected by the CheCluster in the given user namespace:
+// - the exec and view Roles bound to the workspace ServiceAccount
+// - the edit ClusterRole bound to the ServiceAccount of the Che server running in the Che namespace
+// - the admin ClusterRole bound to the user
+func NewRoleBindings(owner, ns, username, cheNamespace string) []*rbacv1.RoleBinding {
+	workspaceSA := rbacv1.Subject{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      WorkspaceServiceAccountName,
+		Namespace: ns,
+	}
+	return []*rbacv1.RoleBinding{
+		newRoleBinding(owner, ns, ExecRoleBindingName, "Role", ExecRoleName, workspaceSA),
+		newRoleBinding(owner, ns, ViewRoleBindingName, "Role", ViewRoleName, workspaceSA),
+		newRoleBinding(owner, ns, CheRoleBindingName, "ClusterRole", "edit", rbacv1.Subject{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      CheServiceAccountName,
+			Namespace: cheNamespace,
+		}),
+		newRoleBinding(owner, ns, AdminRoleBindingName, "ClusterRole", "admin", rbacv1.Subject{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.UserKind,
+			Name:     username,
+		}),
+	}
+}
+
+func newRoleBinding(owner, ns, name, roleKind, roleName string, subject rbacv1.Subject) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: newObjectMeta(owner, ns, name),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     roleKind,
+			Name:     roleName,
+		},
+		Subjects: []rbacv1.Subject{subject},
+	}
+}
+
+func newObjectMeta(owner, ns, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: ns,
+		Labels:    Labels(owner),
+	}
+}
+
+// Provisioning returns the status condition to set when the user namespace is (still) being provisioned
+func Provisioning(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CheUserNamespaceReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1alpha1.ProvisioningReason,
+		Message: message,
+	}
+}
+
+// Provisioned returns the status condition to set when the user namespace was provisioned
+func Provisioned() toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:   v1alpha1.CheUserNamespaceReady,
+		Status: corev1.ConditionTrue,
+		Reason: v1alpha1.ProvisionedReason,
+	}
+}
+
+// ProvisioningFailed returns the status condition to set when the user namespace provisioning failed
+func ProvisioningFailed(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CheUserNamespaceReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1alpha1.FailedToProvisionReason,
+		Message: message,
+	}
+}
+
+// Terminating returns the status condition to set when the user namespace is being deleted
+func Terminating(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.CheUserNamespaceReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1alpha1.TerminatingReason,
+		Message: message,
+	}
+}
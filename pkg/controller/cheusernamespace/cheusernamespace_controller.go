@@ -0,0 +1,407 @@
+package cheusernamespace
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-common/pkg/condition"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/health"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+
+	"github.com/go-logr/logr"
+	errs "github.com/pkg/errors"
+	"github.com/redhat-cop/operator-utils/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_cheusernamespace")
+
+// Add creates a new CheUserNamespace Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, config *configuration.Config) error {
+	log.Info("Adding new CheUserNamespace reconciler")
+	return add(mgr, newReconciler(mgr, config))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, config *configuration.Config) *ReconcileCheUserNamespace {
+	return &ReconcileCheUserNamespace{client: mgr.GetClient(), scheme: mgr.GetScheme(), config: config}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r *ReconcileCheUserNamespace) error {
+	// Create a new controller
+	c, err := controller.New("cheusernamespace-controller", mgr, controller.Options{Reconciler: health.DefaultStatus.TrackReconciles("cheusernamespace-controller", r)})
+	if err != nil {
+		return err
+	}
+	log.Info("configuring watcher on CheUserNamespaces")
+	// Watch for changes to primary resource CheUserNamespace
+	if err := c.Watch(&source.Kind{Type: &v1alpha1.CheUserNamespace{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{}); err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resources
+	enqueueRequestForOwner := &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &v1alpha1.CheUserNamespace{},
+	}
+	for _, secondary := range []runtime.Object{&corev1.Namespace{}, &corev1.ServiceAccount{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}} {
+		log.Info("configuring watcher on secondary resources", "type", fmt.Sprintf("%T", secondary))
+		if err := c.Watch(&source.Kind{Type: secondary}, enqueueRequestForOwner); err != nil {
+			return err
+		}
+	}
+
+	log.Info("configuring watcher on CheInstallations")
+	// Watch for changes to the CheInstallations, whose namespace is bound to the user namespaces
+	if err := c.Watch(&source.Kind{Type: &v1alpha1.CheInstallation{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: cheInstallationToCheUserNamespaces(mgr.GetClient())}, predicate.GenerationChangedPredicate{}); err != nil {
+		return err
+	}
+
+	log.Info("CheUserNamespace reconciler successfully added")
+	return nil
+}
+
+// blank assignment to verify that ReconcileCheUserNamespace implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileCheUserNamespace{}
+
+// ReconcileCheUserNamespace reconciles a CheUserNamespace object
+type ReconcileCheUserNamespace struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+	config *configuration.Config
+}
+
+// Reconcile provisions the namespace of the user along with the ServiceAccount and the RoleBindings expected by the
+// CheCluster, and deletes the namespace when the CheUserNamespace is being deleted
+func (r *ReconcileCheUserNamespace) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling CheUserNamespace")
+
+	userNamespace := &v1alpha1.CheUserNamespace{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: request.Name}, userNamespace); err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("CheUserNamespace not found")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if util.IsBeingDeleted(userNamespace) {
+		if !util.HasFinalizer(userNamespace, toolchainv1alpha1.FinalizerName) {
+			reqLogger.Info("CheUserNamespace already in termination")
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Info("Terminating CheUserNamespace")
+		if remaining, err := r.ensureNamespaceDeletion(reqLogger, userNamespace); err != nil {
+			return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, userNamespace, r.setStatusTerminating, err, "failed to delete the namespace of user '%s'", userNamespace.Spec.Username)
+		} else if remaining {
+			return reconcile.Result{Requeue: true, RequeueAfter: r.config.GetCheRequeueAfter()}, r.setStatusTerminating(userNamespace, "deleting the namespace")
+		}
+		// the namespace is gone, we can now remove the finalizer
+		util.RemoveFinalizer(userNamespace, toolchainv1alpha1.FinalizerName)
+		if err := r.client.Update(context.TODO(), userNamespace); err != nil {
+			return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, userNamespace, r.setStatusTerminating, err, "failed to remove finalizer")
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// Add the finalizer if it is not present
+	if !util.HasFinalizer(userNamespace, toolchainv1alpha1.FinalizerName) {
+		util.AddFinalizer(userNamespace, toolchainv1alpha1.FinalizerName)
+		reqLogger.Info("Adding finalizer on the CheUserNamespace resource")
+		if err := r.client.Update(context.TODO(), userNamespace); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	nsName := NamespaceName(userNamespace.Spec.Username)
+	if msgs := validation.IsDNS1123Label(nsName); len(msgs) > 0 {
+		// no need to requeue, the CheUserNamespace needs to be updated with a valid username
+		msg := fmt.Sprintf("invalid namespace name '%s' for user '%s': %s", nsName, userNamespace.Spec.Username, strings.Join(msgs, ", "))
+		return reconcile.Result{}, r.statusUpdate(reqLogger, userNamespace, r.setStatusProvisioningFailed, msg)
+	}
+
+	cheInstallation := &v1alpha1.CheInstallation{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: CheInstallationName(userNamespace)}, cheInstallation); err != nil {
+		if errors.IsNotFound(err) {
+			// no need to requeue, the CheInstallations are watched
+			return reconcile.Result{}, r.statusUpdate(reqLogger, userNamespace, r.setStatusProvisioning, fmt.Sprintf("waiting for the CheInstallation '%s'", CheInstallationName(userNamespace)))
+		}
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, userNamespace, r.setStatusProvisioningFailed, err, "failed to get the CheInstallation '%s'", CheInstallationName(userNamespace))
+	}
+
+	if requeue, err := r.ensureNamespace(reqLogger, userNamespace, nsName); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, userNamespace, r.setStatusProvisioningFailed, err, "failed to create namespace %s", nsName)
+	} else if requeue {
+		return reconcile.Result{Requeue: true, RequeueAfter: r.config.GetCheRequeueAfter()}, r.statusUpdate(reqLogger, userNamespace, r.setStatusProvisioning, "waiting for the namespace to be active")
+	}
+
+	objects := []runtime.Object{
+		NewWorkspaceServiceAccount(userNamespace.Name, nsName),
+		NewExecRole(userNamespace.Name, nsName),
+		NewViewRole(userNamespace.Name, nsName),
+	}
+	for _, rb := range NewRoleBindings(userNamespace.Name, nsName, userNamespace.Spec.Username, cheInstallation.Spec.CheOperatorSpec.Namespace) {
+		objects = append(objects, rb)
+	}
+	for _, obj := range objects {
+		if err := r.ensureObject(reqLogger, userNamespace, obj); err != nil {
+			return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, userNamespace, r.setStatusProvisioningFailed, err, "failed to create %T in namespace %s", obj, nsName)
+		}
+	}
+
+	reqLogger.Info("done with the provisioning of the user namespace", "namespace", nsName)
+	return reconcile.Result{}, r.statusUpdate(reqLogger, userNamespace, r.setStatusProvisioned(nsName), "")
+}
+
+// ensureNamespace creates the namespace of the user if it does not exist yet, or restores its labels. Returns `true` if the namespace
+// is not active yet (ie, if the reconcile loop should be requeued), or an error if the namespace exists but is not owned by the given
+// CheUserNamespace
+func (r *ReconcileCheUserNamespace) ensureNamespace(logger logr.Logger, userNamespace *v1alpha1.CheUserNamespace, name string) (bool, error) {
+	namespace := NewNamespace(userNamespace.Name, name)
+	if err := controllerutil.SetControllerReference(userNamespace, namespace, r.scheme); err != nil {
+		return false, err
+	}
+	if err := r.client.Create(context.TODO(), namespace); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return false, err
+		}
+		ns := &corev1.Namespace{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name}, ns); err != nil {
+			return false, err
+		}
+		// the namespaces provisioned by the previous versions of the operator only have the controller reference
+		// (and the generic owner label), so they are relabelled below
+		if ns.Labels[toolchain.CheUserNamespaceOwnerLabelKey] != userNamespace.Name && !metav1.IsControlledBy(ns, userNamespace) {
+			return false, fmt.Errorf("namespace '%s' already exists and is not owned by the CheUserNamespace '%s'", name, userNamespace.Name)
+		}
+		if updateLabels(ns, namespace.Labels) {
+			logger.Info("Restoring the labels of the namespace of the user", "Namespace", name)
+			if err := r.client.Update(context.TODO(), ns); err != nil {
+				return false, err
+			}
+		}
+		if ns.Status.Phase != corev1.NamespaceActive {
+			logger.Info("Namespace is not in active state", "namespace", ns.Name, "phase", ns.Status.Phase)
+			return true, nil // requeue until the namespace is active
+		}
+		return false, nil
+	}
+	logger.Info("Created the namespace of the user", "Namespace", name)
+	return true, nil
+}
+
+// ensureObject creates the given object with the CheUserNamespace as its controller, or updates the existing object
+// if it drifted from the given one
+func (r *ReconcileCheUserNamespace) ensureObject(logger logr.Logger, userNamespace *v1alpha1.CheUserNamespace, obj runtime.Object) error {
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return fmt.Errorf("unsupported object of type %T", obj)
+	}
+	if err := controllerutil.SetControllerReference(userNamespace, metaObj, r.scheme); err != nil {
+		return err
+	}
+	// an empty object, so that the fields which are not set in the existing object are not kept from the given object
+	existing := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtime.Object)
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: metaObj.GetNamespace(), Name: metaObj.GetName()}, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if err := r.client.Create(context.TODO(), obj); err != nil {
+			return err
+		}
+		logger.Info("Created an object in the namespace of the user", "Type", fmt.Sprintf("%T", obj), "Namespace", metaObj.GetNamespace(), "Name", metaObj.GetName())
+		return nil
+	}
+	existingMeta := existing.(metav1.Object)
+	controlled := metav1.IsControlledBy(existingMeta, userNamespace)
+	// fails if the object is controlled by another resource
+	if err := controllerutil.SetControllerReference(userNamespace, existingMeta, r.scheme); err != nil {
+		return err
+	}
+	changed, recreate := merge(existing, obj)
+	if recreate {
+		// the role reference of a RoleBinding is immutable
+		logger.Info("Recreating an object in the namespace of the user", "Type", fmt.Sprintf("%T", obj), "Namespace", metaObj.GetNamespace(), "Name", metaObj.GetName())
+		if err := r.client.Delete(context.TODO(), existing); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return r.client.Create(context.TODO(), obj)
+	}
+	if updateLabels(existingMeta, metaObj.GetLabels()) {
+		changed = true
+	}
+	if !changed && controlled {
+		return nil
+	}
+	logger.Info("Updating an object in the namespace of the user", "Type", fmt.Sprintf("%T", obj), "Namespace", metaObj.GetNamespace(), "Name", metaObj.GetName())
+	return r.client.Update(context.TODO(), existing)
+}
+
+// merge copies the specification of the desired object into the existing object. Returns `true` if the existing object changed,
+// and `true` if the existing object cannot be updated in place and needs to be recreated
+func merge(existing, desired runtime.Object) (bool, bool) {
+	switch desired := desired.(type) {
+	case *rbacv1.Role:
+		existing := existing.(*rbacv1.Role)
+		if reflect.DeepEqual(existing.Rules, desired.Rules) {
+			return false, false
+		}
+		existing.Rules = desired.Rules
+		return true, false
+	case *rbacv1.RoleBinding:
+		existing := existing.(*rbacv1.RoleBinding)
+		if existing.RoleRef != desired.RoleRef {
+			return false, true
+		}
+		if reflect.DeepEqual(existing.Subjects, desired.Subjects) {
+			return false, false
+		}
+		existing.Subjects = desired.Subjects
+		return true, false
+	}
+	// the ServiceAccount has no specification, its secrets are managed by the cluster
+	return false, false
+}
+
+// updateLabels sets the given labels on the object, preserving its other labels. Returns `true` if the labels changed,
+// in which case the generic owner label set by the previous versions of the operator is also removed
+func updateLabels(obj metav1.Object, labels map[string]string) bool {
+	changed := false
+	existing := obj.GetLabels()
+	if existing == nil {
+		existing = map[string]string{}
+	}
+	for k, v := range labels {
+		if existing[k] != v {
+			existing[k] = v
+			changed = true
+		}
+	}
+	if _, found := existing[toolchain.OwnerLabelKey]; found && changed {
+		delete(existing, toolchain.OwnerLabelKey)
+	}
+	obj.SetLabels(existing)
+	return changed
+}
+
+// ensureNamespaceDeletion deletes the namespaces owned by the given CheUserNamespace (which also deletes the
+// ServiceAccount and the RoleBindings). Returns `true` if some namespaces still exist.
+func (r *ReconcileCheUserNamespace) ensureNamespaceDeletion(logger logr.Logger, userNamespace *v1alpha1.CheUserNamespace) (bool, error) {
+	namespaces := &corev1.NamespaceList{}
+	if err := r.client.List(context.TODO(), namespaces, client.MatchingLabels(Labels(userNamespace.Name))); err != nil {
+		return false, err
+	}
+	for i := range namespaces.Items {
+		ns := namespaces.Items[i]
+		if util.IsBeingDeleted(&ns) {
+			logger.Info("Namespace is already being deleted", "namespace", ns.Name)
+			continue
+		}
+		logger.Info("Deleting the namespace of the user", "namespace", ns.Name)
+		if err := r.client.Delete(context.TODO(), &ns); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	return len(namespaces.Items) > 0, nil
+}
+
+// wrapErrorWithStatusUpdate wraps the error and update the status. If the update failed then logs the error.
+func (r *ReconcileCheUserNamespace) wrapErrorWithStatusUpdate(logger logr.Logger, userNamespace *v1alpha1.CheUserNamespace, updateStatus updateStatusFunc, err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	if err := updateStatus(userNamespace, err.Error()); err != nil {
+		logger.Error(err, "status update failed")
+	}
+	return errs.Wrapf(err, format, args...)
+}
+
+type updateStatusFunc func(userNamespace *v1alpha1.CheUserNamespace, message string) error
+
+func (r *ReconcileCheUserNamespace) statusUpdate(logger logr.Logger, userNamespace *v1alpha1.CheUserNamespace, updateStatus updateStatusFunc, msg string) error {
+	if err := updateStatus(userNamespace, msg); err != nil {
+		logger.Error(err, "unable to update status")
+		return errs.Wrapf(err, "failed to update status")
+	}
+	return nil
+}
+
+func (r *ReconcileCheUserNamespace) updateStatusConditions(userNamespace *v1alpha1.CheUserNamespace, newConditions ...toolchainv1alpha1.Condition) error {
+	var updated bool
+	userNamespace.Status.Conditions, updated = condition.AddOrUpdateStatusConditions(userNamespace.Status.Conditions, newConditions...)
+	if !updated {
+		// Nothing changed
+		return nil
+	}
+	return r.client.Status().Update(context.TODO(), userNamespace)
+}
+
+func (r *ReconcileCheUserNamespace) setStatusProvisioning(userNamespace *v1alpha1.CheUserNamespace, message string) error {
+	return r.updateStatusConditions(userNamespace, Provisioning(message))
+}
+
+func (r *ReconcileCheUserNamespace) setStatusProvisioningFailed(userNamespace *v1alpha1.CheUserNamespace, message string) error {
+	return r.updateStatusConditions(userNamespace, ProvisioningFailed(message))
+}
+
+func (r *ReconcileCheUserNamespace) setStatusTerminating(userNamespace *v1alpha1.CheUserNamespace, message string) error {
+	return r.updateStatusConditions(userNamespace, Terminating(message))
+}
+
+func (r *ReconcileCheUserNamespace) setStatusProvisioned(namespace string) updateStatusFunc {
+	return func(userNamespace *v1alpha1.CheUserNamespace, _ string) error {
+		namespaceChanged := userNamespace.Status.Namespace != namespace
+		userNamespace.Status.Namespace = namespace
+		var updated bool
+		userNamespace.Status.Conditions, updated = condition.AddOrUpdateStatusConditions(userNamespace.Status.Conditions, Provisioned())
+		if !updated && !namespaceChanged {
+			// Nothing changed
+			return nil
+		}
+		return r.client.Status().Update(context.TODO(), userNamespace)
+	}
+}
+
+// cheInstallationToCheUserNamespaces returns a mapper which maps the events on a CheInstallation to requests on the
+// CheUserNamespaces whose workspaces are managed by this CheInstallation
+func cheInstallationToCheUserNamespaces(cl client.Reader) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		userNamespaces := &v1alpha1.CheUserNamespaceList{}
+		if err := cl.List(context.TODO(), userNamespaces); err != nil {
+			log.Error(err, "Unable to list the CheUserNamespaces")
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range userNamespaces.Items {
+			if CheInstallationName(&userNamespaces.Items[i]) == obj.Meta.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: userNamespaces.Items[i].Name}})
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,459 @@
+package cheusernamespace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/controller/cheinstallation"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+	"github.com/codeready-toolchain/toolchain-operator/test"
+	. "github.com/codeready-toolchain/toolchain-operator/test/assert"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	// enable logs in tests
+	logf.SetLogger(zap.Logger(true))
+}
+
+func TestReconcile(t *testing.T) {
+
+	t.Run("should not reconcile without CheUserNamespace", func(t *testing.T) {
+		// given
+		cl, r := configureClient(t)
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest("johnsmith"))
+
+		// then
+		require.NoError(t, err)
+		AssertThatNamespace(t, "johnsmith-codeready", cl).DoesNotExist()
+	})
+
+	t.Run("should wait for CheInstallation", func(t *testing.T) {
+		// given
+		userNamespace := newCheUserNamespace("johnsmith", "johnsmith")
+		cl, r := configureClient(t, userNamespace)
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+		// then
+		require.NoError(t, err) // no need to requeue, the CheInstallations are watched
+		assert.Equal(t, reconcile.Result{}, result)
+		AssertThatNamespace(t, "johnsmith-codeready", cl).DoesNotExist()
+		AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+			HasConditions(Provisioning("waiting for the CheInstallation 'toolchain-workspaces-installation'"))
+	})
+
+	t.Run("should create namespace and requeue until it is active", func(t *testing.T) {
+		// given
+		userNamespace := newCheUserNamespace("johnsmith", "johnsmith")
+		cl, r := configureClient(t, userNamespace, cheinstallation.NewInstallation(cfg))
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, reconcile.Result{Requeue: true, RequeueAfter: 3 * time.Second}, result)
+		AssertThatNamespace(t, "johnsmith-codeready", cl).
+			Exists().
+			HasLabels(map[string]string{
+				"provider": "toolchain-operator",
+				"toolchain.openshift.dev/cheusernamespace": "johnsmith",
+			})
+		AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+			HasFinalizer(toolchainv1alpha1.FinalizerName).
+			HasConditions(Provisioning("waiting for the namespace to be active"))
+	})
+
+	t.Run("should create service account, roles and role bindings when namespace is active", func(t *testing.T) {
+		// given
+		userNamespace := newCheUserNamespace("johnsmith", "johnsmith")
+		cl, r := configureClient(t, userNamespace, cheinstallation.NewInstallation(cfg), newUserNamespace("johnsmith", "johnsmith-codeready", corev1.NamespaceActive))
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, reconcile.Result{}, result)
+		AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+			HasFinalizer(toolchainv1alpha1.FinalizerName).
+			HasNamespace("johnsmith-codeready").
+			HasConditions(Provisioned())
+
+		sa := &corev1.ServiceAccount{}
+		err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: "che-workspace"}, sa)
+		require.NoError(t, err)
+		assert.Equal(t, Labels("johnsmith"), sa.Labels)
+		assertControlledBy(t, sa.OwnerReferences, userNamespace)
+
+		for _, name := range []string{"exec", "workspace-view"} {
+			role := &rbacv1.Role{}
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: name}, role)
+			require.NoError(t, err)
+			assertControlledBy(t, role.OwnerReferences, userNamespace)
+		}
+
+		expectedBindings := map[string]struct {
+			roleRef rbacv1.RoleRef
+			subject rbacv1.Subject
+		}{
+			"che-workspace-exec": {
+				roleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "exec"},
+				subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "che-workspace", Namespace: "johnsmith-codeready"},
+			},
+			"che-workspace-view": {
+				roleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "workspace-view"},
+				subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "che-workspace", Namespace: "johnsmith-codeready"},
+			},
+			"che": {
+				roleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "edit"},
+				subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "che", Namespace: "toolchain-workspaces"},
+			},
+			"admin": {
+				roleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "admin"},
+				subject: rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "johnsmith"},
+			},
+		}
+		for name, expected := range expectedBindings {
+			rb := &rbacv1.RoleBinding{}
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: name}, rb)
+			require.NoError(t, err)
+			assert.Equal(t, expected.roleRef, rb.RoleRef)
+			assert.Equal(t, []rbacv1.Subject{expected.subject}, rb.Subjects)
+			assertControlledBy(t, rb.OwnerReferences, userNamespace)
+		}
+
+		t.Run("should not fail when reconciling again", func(t *testing.T) {
+			// when
+			result, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, reconcile.Result{}, result)
+			AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+				HasNamespace("johnsmith-codeready").
+				HasConditions(Provisioned())
+		})
+
+		t.Run("should repair the drifted objects", func(t *testing.T) {
+			// given
+			sa := &corev1.ServiceAccount{}
+			err := cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: "che-workspace"}, sa)
+			require.NoError(t, err)
+			sa.Labels = nil
+			err = cl.Update(context.TODO(), sa)
+			require.NoError(t, err)
+			role := &rbacv1.Role{}
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: "exec"}, role)
+			require.NoError(t, err)
+			role.Rules[0].Verbs = []string{"create", "get"}
+			err = cl.Update(context.TODO(), role)
+			require.NoError(t, err)
+			rb := &rbacv1.RoleBinding{}
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: "admin"}, rb)
+			require.NoError(t, err)
+			rb.Subjects = append(rb.Subjects, rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "janedoe"})
+			err = cl.Update(context.TODO(), rb)
+			require.NoError(t, err)
+			rb = &rbacv1.RoleBinding{}
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: "che"}, rb)
+			require.NoError(t, err)
+			rb.RoleRef.Name = "view"
+			err = cl.Update(context.TODO(), rb)
+			require.NoError(t, err)
+
+			// when
+			result, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, reconcile.Result{}, result)
+			sa = &corev1.ServiceAccount{}
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: "che-workspace"}, sa)
+			require.NoError(t, err)
+			assert.Equal(t, Labels("johnsmith"), sa.Labels)
+			role = &rbacv1.Role{}
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: "exec"}, role)
+			require.NoError(t, err)
+			assert.Equal(t, NewExecRole("johnsmith", "johnsmith-codeready").Rules, role.Rules)
+			rb = &rbacv1.RoleBinding{}
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: "admin"}, rb)
+			require.NoError(t, err)
+			assert.Equal(t, []rbacv1.Subject{expectedBindings["admin"].subject}, rb.Subjects)
+			rb = &rbacv1.RoleBinding{}
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: "che"}, rb)
+			require.NoError(t, err)
+			assert.Equal(t, expectedBindings["che"].roleRef, rb.RoleRef)
+			assertControlledBy(t, rb.OwnerReferences, userNamespace)
+		})
+	})
+
+	t.Run("should bind the Che server of the referenced CheInstallation", func(t *testing.T) {
+		// given
+		userNamespace := newCheUserNamespace("johnsmith", "johnsmith")
+		userNamespace.Spec.CheInstallation = "tenant-a"
+		cheInstallation := cheinstallation.NewInstallation(cfg)
+		cheInstallation.Name = "tenant-a"
+		cheInstallation.Spec.CheOperatorSpec.Namespace = "tenant-a-workspaces"
+		cl, r := configureClient(t, userNamespace, cheinstallation.NewInstallation(cfg), cheInstallation, newUserNamespace("johnsmith", "johnsmith-codeready", corev1.NamespaceActive))
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+		// then
+		require.NoError(t, err)
+		rb := &rbacv1.RoleBinding{}
+		err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "johnsmith-codeready", Name: "che"}, rb)
+		require.NoError(t, err)
+		assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "che", Namespace: "tenant-a-workspaces"}}, rb.Subjects)
+	})
+
+	t.Run("should relabel namespace provisioned by a previous version", func(t *testing.T) {
+		// given
+		userNamespace := newCheUserNamespace("johnsmith", "johnsmith")
+		userNamespace.UID = "johnsmith-uid"
+		ns := newUserNamespace("johnsmith", "johnsmith-codeready", corev1.NamespaceActive)
+		ns.Labels = toolchain.LabelsWithOwner("johnsmith")
+		err := controllerutil.SetControllerReference(userNamespace, ns, apiScheme(t))
+		require.NoError(t, err)
+		cl, r := configureClient(t, userNamespace, cheinstallation.NewInstallation(cfg), ns)
+
+		// when
+		_, err = r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+		// then
+		require.NoError(t, err)
+		AssertThatNamespace(t, "johnsmith-codeready", cl).
+			HasLabels(Labels("johnsmith"))
+		AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+			HasConditions(Provisioned())
+	})
+
+	t.Run("should fail with invalid username", func(t *testing.T) {
+		// given
+		userNamespace := newCheUserNamespace("john", "John.Smith@example.com")
+		cl, r := configureClient(t, userNamespace)
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+		// then
+		require.NoError(t, err) // no need to requeue
+		assert.Equal(t, reconcile.Result{}, result)
+		AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+			HasConditions(ProvisioningFailed("invalid namespace name 'John.Smith@example.com-codeready' for user 'John.Smith@example.com': " +
+				"a DNS-1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character " +
+				"(e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')"))
+	})
+
+	t.Run("should fail when namespace is owned by someone else", func(t *testing.T) {
+		// given
+		userNamespace := newCheUserNamespace("johnsmith", "johnsmith")
+		cl, r := configureClient(t, userNamespace, cheinstallation.NewInstallation(cfg), newUserNamespace("someone-else", "johnsmith-codeready", corev1.NamespaceActive))
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+		// then
+		msg := "namespace 'johnsmith-codeready' already exists and is not owned by the CheUserNamespace 'johnsmith'"
+		require.EqualError(t, err, "failed to create namespace johnsmith-codeready: "+msg)
+		AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+			HasConditions(ProvisioningFailed(msg))
+	})
+
+	t.Run("should fail when creating role binding failed", func(t *testing.T) {
+		// given
+		userNamespace := newCheUserNamespace("johnsmith", "johnsmith")
+		cl, r := configureClient(t, userNamespace, cheinstallation.NewInstallation(cfg), newUserNamespace("johnsmith", "johnsmith-codeready", corev1.NamespaceActive))
+		cl.MockCreate = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+			if _, ok := obj.(*rbacv1.RoleBinding); ok {
+				return errors.New("something went wrong")
+			}
+			return cl.Client.Create(ctx, obj, opts...)
+		}
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+		// then
+		require.EqualError(t, err, "failed to create *v1.RoleBinding in namespace johnsmith-codeready: something went wrong")
+		AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+			HasConditions(ProvisioningFailed("something went wrong"))
+	})
+
+	t.Run("when being deleted", func(t *testing.T) {
+
+		t.Run("should delete namespace and requeue", func(t *testing.T) {
+			// given
+			userNamespace := newCheUserNamespace("johnsmith", "johnsmith")
+			userNamespace.Finalizers = []string{toolchainv1alpha1.FinalizerName}
+			userNamespace.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			cl, r := configureClient(t, userNamespace, newUserNamespace("johnsmith", "johnsmith-codeready", corev1.NamespaceActive))
+
+			// when
+			result, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, reconcile.Result{Requeue: true, RequeueAfter: 3 * time.Second}, result)
+			AssertThatNamespace(t, "johnsmith-codeready", cl).DoesNotExist()
+			AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+				HasFinalizer(toolchainv1alpha1.FinalizerName).
+				HasConditions(Terminating("deleting the namespace"))
+
+			t.Run("should remove finalizer once namespace is gone", func(t *testing.T) {
+				// when
+				result, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+				// then
+				require.NoError(t, err)
+				assert.Equal(t, reconcile.Result{}, result)
+				AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+					HasNoFinalizer()
+			})
+		})
+
+		t.Run("should not delete namespace owned by someone else", func(t *testing.T) {
+			// given
+			userNamespace := newCheUserNamespace("johnsmith", "johnsmith")
+			userNamespace.Finalizers = []string{toolchainv1alpha1.FinalizerName}
+			userNamespace.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			cl, r := configureClient(t, userNamespace, newUserNamespace("someone-else", "johnsmith-codeready", corev1.NamespaceActive))
+
+			// when
+			result, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, reconcile.Result{}, result)
+			AssertThatNamespace(t, "johnsmith-codeready", cl).Exists()
+			AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+				HasNoFinalizer()
+		})
+
+		t.Run("should fail when deleting namespace failed", func(t *testing.T) {
+			// given
+			userNamespace := newCheUserNamespace("johnsmith", "johnsmith")
+			userNamespace.Finalizers = []string{toolchainv1alpha1.FinalizerName}
+			userNamespace.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			cl, r := configureClient(t, userNamespace, newUserNamespace("johnsmith", "johnsmith-codeready", corev1.NamespaceActive))
+			cl.MockDelete = func(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+				return apierrors.NewForbidden(corev1.Resource("namespaces"), "johnsmith-codeready", errors.New("not allowed"))
+			}
+
+			// when
+			_, err := r.Reconcile(newReconcileRequest(userNamespace.Name))
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to delete the namespace of user 'johnsmith'")
+			AssertThatNamespace(t, "johnsmith-codeready", cl).Exists()
+			AssertThatCheUserNamespace(t, userNamespace.Name, cl).
+				HasFinalizer(toolchainv1alpha1.FinalizerName)
+		})
+	})
+}
+
+func TestCheInstallationToCheUserNamespaces(t *testing.T) {
+	// given
+	johnsmith := newCheUserNamespace("johnsmith", "johnsmith")
+	janedoe := newCheUserNamespace("janedoe", "janedoe")
+	janedoe.Spec.CheInstallation = "tenant-a"
+	cl, _ := configureClient(t, johnsmith, janedoe)
+	mapper := cheInstallationToCheUserNamespaces(cl)
+
+	t.Run("should map the default CheInstallation", func(t *testing.T) {
+		// when
+		requests := mapper(handler.MapObject{Meta: &metav1.ObjectMeta{Name: "toolchain-workspaces-installation"}})
+
+		// then
+		assert.Equal(t, []reconcile.Request{newReconcileRequest("johnsmith")}, requests)
+	})
+
+	t.Run("should map the referenced CheInstallation", func(t *testing.T) {
+		// when
+		requests := mapper(handler.MapObject{Meta: &metav1.ObjectMeta{Name: "tenant-a"}})
+
+		// then
+		assert.Equal(t, []reconcile.Request{newReconcileRequest("janedoe")}, requests)
+	})
+
+	t.Run("should not map other CheInstallations", func(t *testing.T) {
+		// when
+		requests := mapper(handler.MapObject{Meta: &metav1.ObjectMeta{Name: "tenant-b"}})
+
+		// then
+		assert.Empty(t, requests)
+	})
+}
+
+func assertControlledBy(t *testing.T, references []metav1.OwnerReference, userNamespace *v1alpha1.CheUserNamespace) {
+	require.Len(t, references, 1)
+	assert.Equal(t, "CheUserNamespace", references[0].Kind)
+	assert.Equal(t, userNamespace.Name, references[0].Name)
+	require.NotNil(t, references[0].Controller)
+	assert.True(t, *references[0].Controller)
+}
+
+// cfg the operator configuration with the default values
+var cfg = configuration.NewConfig()
+
+func configureClient(t *testing.T, initObjs ...runtime.Object) (*test.FakeClient, *ReconcileCheUserNamespace) {
+	s := apiScheme(t)
+	cl := test.NewFakeClient(t, initObjs...)
+	reconcileCheUserNamespace := &ReconcileCheUserNamespace{scheme: s, client: cl, config: cfg}
+	return cl, reconcileCheUserNamespace
+}
+
+func newReconcileRequest(name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+}
+
+func apiScheme(t *testing.T) *runtime.Scheme {
+	s := scheme.Scheme
+	err := apis.AddToScheme(s)
+	require.NoError(t, err)
+	return s
+}
+
+func newCheUserNamespace(name, username string) *v1alpha1.CheUserNamespace {
+	return &v1alpha1.CheUserNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1alpha1.CheUserNamespaceSpec{
+			Username: username,
+		},
+	}
+}
+
+func newUserNamespace(owner, name string, phase corev1.NamespacePhase) *corev1.Namespace {
+	ns := NewNamespace(owner, name)
+	ns.Status.Phase = phase
+	return ns
+}
@@ -1,23 +1,32 @@
 package controller
 
 import (
+	"fmt"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/controller/cheinstallation"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/controller/cheusernamespace"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/controller/tektoninstallation"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 func init() {
-	AddToManagerFuncs = append(AddToManagerFuncs, cheinstallation.Add)
-	AddToManagerFuncs = append(AddToManagerFuncs, tektoninstallation.Add)
+	AddToManagerFuncs[configuration.CheInstallationController] = cheinstallation.Add
+	AddToManagerFuncs[configuration.TektonInstallationController] = tektoninstallation.Add
+	AddToManagerFuncs[configuration.CheUserNamespaceController] = cheusernamespace.Add
 }
 
-// AddToManagerFuncs is a list of functions to add all Controllers to the Manager
-var AddToManagerFuncs []func(manager.Manager) error
+// AddToManagerFuncs is a map of functions to add all Controllers to the Manager, indexed by the controller name
+var AddToManagerFuncs = map[string]func(manager.Manager, *configuration.Config) error{}
 
-// AddToManager adds all Controllers to the Manager
-func AddToManager(m manager.Manager) error {
-	for _, f := range AddToManagerFuncs {
-		if err := f(m); err != nil {
+// AddToManager adds all the enabled Controllers to the Manager
+func AddToManager(m manager.Manager, config *configuration.Config) error {
+	for _, name := range config.GetEnabledControllers() {
+		f, ok := AddToManagerFuncs[name]
+		if !ok {
+			return fmt.Errorf("unknown controller '%s'", name)
+		}
+		if err := f(m, config); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,73 @@
+package tektoninstallation
+
+import (
+	"context"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// getActiveInstallation returns the TektonInstallation which installs OpenShift Pipelines in the cluster. Since the Subscription
+// and the TektonConfig are unique in the cluster, there can be a single active TektonInstallation: the oldest one (the names are
+// compared when several TektonInstallations were created at the same time). Returns nil if there is no TektonInstallation.
+func getActiveInstallation(cl client.Reader) (*v1alpha1.TektonInstallation, error) {
+	installations := &v1alpha1.TektonInstallationList{}
+	if err := cl.List(context.TODO(), installations); err != nil {
+		return nil, err
+	}
+	var active *v1alpha1.TektonInstallation
+	for i := range installations.Items {
+		if active == nil || createdBefore(&installations.Items[i], active) {
+			active = &installations.Items[i]
+		}
+	}
+	return active, nil
+}
+
+// createdBefore returns true if the first TektonInstallation was created before the second one.
+// The names are compared when both TektonInstallations were created at the same time.
+func createdBefore(first, second *v1alpha1.TektonInstallation) bool {
+	if !first.CreationTimestamp.Equal(&second.CreationTimestamp) {
+		return first.CreationTimestamp.Before(&second.CreationTimestamp)
+	}
+	return first.Name < second.Name
+}
+
+// mapToActiveInstallation returns a mapper which maps the events on the cluster-wide resources (eg: the TektonConfig or the namespaces)
+// to a request on the active TektonInstallation
+func mapToActiveInstallation(cl client.Reader) handler.ToRequestsFunc {
+	return func(_ handler.MapObject) []reconcile.Request {
+		active, err := getActiveInstallation(cl)
+		if err != nil || active == nil {
+			return nil
+		}
+		return requestFor(active)
+	}
+}
+
+// mapToOtherInstallations returns a mapper which maps the deletion of a TektonInstallation to requests on the other TektonInstallations,
+// so that the next active TektonInstallation takes over and the others refresh their Conflict condition
+func mapToOtherInstallations(cl client.Reader) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		installations := &v1alpha1.TektonInstallationList{}
+		if err := cl.List(context.TODO(), installations); err != nil {
+			log.Error(err, "Unable to list the TektonInstallations")
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range installations.Items {
+			if installations.Items[i].Name != obj.Meta.GetName() {
+				requests = append(requests, requestFor(&installations.Items[i])...)
+			}
+		}
+		return requests
+	}
+}
+
+func requestFor(tektonInstallation *v1alpha1.TektonInstallation) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: tektonInstallation.Name}}}
+}
@@ -0,0 +1,172 @@
+package tektoninstallation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	. "github.com/codeready-toolchain/toolchain-operator/test/assert"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	config "github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestGetActiveInstallation(t *testing.T) {
+
+	t.Run("oldest installation", func(t *testing.T) {
+		// given
+		cl, _ := configureClient(t, newInstallationCreatedAgo("other", time.Minute), newInstallationCreatedAgo(InstallationName, time.Hour))
+
+		// when
+		active, err := getActiveInstallation(cl)
+
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, active)
+		assert.Equal(t, InstallationName, active.Name)
+	})
+
+	t.Run("installations created at the same time", func(t *testing.T) {
+		// given
+		first := newInstallationCreatedAgo("a-installation", time.Hour)
+		second := first.DeepCopy()
+		second.Name = "b-installation"
+		cl, _ := configureClient(t, second, first)
+
+		// when
+		active, err := getActiveInstallation(cl)
+
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, active)
+		assert.Equal(t, "a-installation", active.Name)
+	})
+
+	t.Run("no installation", func(t *testing.T) {
+		// given
+		cl, _ := configureClient(t)
+
+		// when
+		active, err := getActiveInstallation(cl)
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, active)
+	})
+}
+
+func TestMapToActiveInstallation(t *testing.T) {
+
+	t.Run("should map the TektonConfig to the active installation", func(t *testing.T) {
+		// given
+		cl, _ := configureClient(t, newInstallationCreatedAgo("other", time.Minute), newInstallationCreatedAgo("first", time.Hour))
+		tektonConfig := newTektonConfig(config.InstalledStatus)
+
+		// when
+		requests := mapToActiveInstallation(cl)(handler.MapObject{Meta: tektonConfig, Object: tektonConfig})
+
+		// then
+		require.Len(t, requests, 1)
+		assert.Equal(t, "first", requests[0].Name)
+	})
+
+	t.Run("should not map anything without installation", func(t *testing.T) {
+		// given
+		cl, _ := configureClient(t)
+		tektonConfig := newTektonConfig(config.InstalledStatus)
+
+		// when
+		requests := mapToActiveInstallation(cl)(handler.MapObject{Meta: tektonConfig, Object: tektonConfig})
+
+		// then
+		assert.Empty(t, requests)
+	})
+}
+
+func TestMapToOtherInstallations(t *testing.T) {
+
+	t.Run("should map the deletion of the active installation to the other installations", func(t *testing.T) {
+		// given
+		deleted := newInstallationCreatedAgo("first", time.Hour)
+		cl, _ := configureClient(t, newInstallationCreatedAgo("second", time.Minute), newInstallationCreatedAgo("third", time.Second))
+
+		// when
+		requests := mapToOtherInstallations(cl)(handler.MapObject{Meta: deleted, Object: deleted})
+
+		// then
+		require.Len(t, requests, 2)
+		assert.ElementsMatch(t, []string{"second", "third"}, []string{requests[0].Name, requests[1].Name})
+	})
+
+	t.Run("should not map the deleted installation", func(t *testing.T) {
+		// given
+		deleted := newInstallationCreatedAgo("first", time.Hour)
+		cl, _ := configureClient(t, deleted)
+
+		// when
+		requests := mapToOtherInstallations(cl)(handler.MapObject{Meta: deleted, Object: deleted})
+
+		// then
+		assert.Empty(t, requests)
+	})
+}
+
+func TestSecondTektonInstallation(t *testing.T) {
+
+	t.Run("should reject the second installation", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationCreatedAgo(InstallationName, time.Hour)
+		second := newInstallationCreatedAgo("second", time.Minute)
+		cl, r := configureClient(t, tektonInstallation, second)
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(second))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, reconcile.Result{}, result) // no need to requeue, the deletion of the first installation is watched
+		AssertThatSubscription(t, cfg.GetTektonSubscriptionNamespace(), SubscriptionName, cl).
+			DoesNotExist()
+		AssertThatTektonInstallation(t, second.Namespace, second.Name, cl).
+			HasConditions(Conflict("OpenShift Pipelines is already installed by the TektonInstallation 'toolchain-tekton-installation'"))
+
+		t.Run("should reconcile the first installation", func(t *testing.T) {
+			// when
+			_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+			// then
+			require.NoError(t, err)
+			AssertThatSubscription(t, cfg.GetTektonSubscriptionNamespace(), SubscriptionName, cl).
+				Exists()
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasConditions(Installing("created tekton subscription"))
+		})
+	})
+
+	t.Run("should reconcile the second installation once the first one is deleted", func(t *testing.T) {
+		// given
+		second := newInstallationCreatedAgo("second", time.Minute)
+		cl, r := configureClient(t, second)
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(second))
+
+		// then
+		require.NoError(t, err)
+		AssertThatSubscription(t, cfg.GetTektonSubscriptionNamespace(), SubscriptionName, cl).
+			Exists()
+		AssertThatTektonInstallation(t, second.Namespace, second.Name, cl).
+			HasConditions(Installing("created tekton subscription"))
+	})
+}
+
+func newInstallationCreatedAgo(name string, createdAgo time.Duration) *v1alpha1.TektonInstallation {
+	tektonInstallation := NewInstallation()
+	tektonInstallation.Name = name
+	tektonInstallation.CreationTimestamp = metav1.NewTime(time.Now().Add(-createdAgo))
+	return tektonInstallation
+}
@@ -0,0 +1,136 @@
+package tektoninstallation
+
+// DefaultTaskBundleName the name of the bundle of the default ClusterTasks embedded in the operator
+const DefaultTaskBundleName = "default"
+
+// DefaultTaskBundleVersion the version of the bundle of the default ClusterTasks. It must be incremented
+// each time the manifests below are changed, so that the ClusterTasks are updated in the cluster
+const DefaultTaskBundleVersion = "1"
+
+// embeddedTaskBundles the bundles of tasks which are embedded in the operator, with the manifests of the tasks indexed by name
+var embeddedTaskBundles = map[string]embeddedTaskBundle{
+	DefaultTaskBundleName: {
+		version: DefaultTaskBundleVersion,
+		manifests: map[string]string{
+			"git-clone": gitCloneClusterTask,
+			"buildah":   buildahClusterTask,
+			"maven":     mavenClusterTask,
+		},
+	},
+}
+
+type embeddedTaskBundle struct {
+	version   string
+	manifests map[string]string
+}
+
+const gitCloneClusterTask = `
+apiVersion: tekton.dev/v1beta1
+kind: ClusterTask
+metadata:
+  name: toolchain-git-clone
+spec:
+  description: Clones a git repository into the output workspace
+  workspaces:
+  - name: output
+  params:
+  - name: url
+    type: string
+    description: the URL of the repository to clone
+  - name: revision
+    type: string
+    description: the revision to checkout (branch, tag, sha, ref)
+    default: master
+  results:
+  - name: commit
+    description: the SHA of the commit which was checked out
+  steps:
+  - name: clone
+    image: gcr.io/tekton-releases/github.com/tektoncd/pipeline/cmd/git-init:v0.12.1
+    script: |
+      #!/bin/sh
+      set -eu
+      /ko-app/git-init -url "$(params.url)" -revision "$(params.revision)" -path "$(workspaces.output.path)"
+      cd "$(workspaces.output.path)"
+      echo -n "$(git rev-parse HEAD)" > $(results.commit.path)
+`
+
+const buildahClusterTask = `
+apiVersion: tekton.dev/v1beta1
+kind: ClusterTask
+metadata:
+  name: toolchain-buildah
+spec:
+  description: Builds the source in the workspace into a container image and pushes it to a registry
+  workspaces:
+  - name: source
+  params:
+  - name: IMAGE
+    type: string
+    description: the reference of the image to build
+  - name: DOCKERFILE
+    type: string
+    description: the path to the Dockerfile
+    default: ./Dockerfile
+  - name: TLSVERIFY
+    type: string
+    description: verify the TLS on the registry endpoint
+    default: "true"
+  steps:
+  - name: build
+    image: quay.io/buildah/stable:v1.14.8
+    workingDir: $(workspaces.source.path)
+    command: ["buildah", "bud", "--tls-verify=$(params.TLSVERIFY)", "--layers", "-f", "$(params.DOCKERFILE)", "-t", "$(params.IMAGE)", "."]
+    securityContext:
+      privileged: true
+    volumeMounts:
+    - name: varlibcontainers
+      mountPath: /var/lib/containers
+  - name: push
+    image: quay.io/buildah/stable:v1.14.8
+    workingDir: $(workspaces.source.path)
+    command: ["buildah", "push", "--tls-verify=$(params.TLSVERIFY)", "$(params.IMAGE)", "docker://$(params.IMAGE)"]
+    securityContext:
+      privileged: true
+    volumeMounts:
+    - name: varlibcontainers
+      mountPath: /var/lib/containers
+  volumes:
+  - name: varlibcontainers
+    emptyDir: {}
+`
+
+const mavenClusterTask = `
+apiVersion: tekton.dev/v1beta1
+kind: ClusterTask
+metadata:
+  name: toolchain-maven
+spec:
+  description: Runs the given Maven goals on the source in the workspace
+  workspaces:
+  - name: source
+  params:
+  - name: GOALS
+    type: array
+    description: the Maven goals to run
+    default:
+    - package
+  - name: MAVEN_MIRROR_URL
+    type: string
+    description: the URL of the Maven repository mirror
+    default: ""
+  steps:
+  - name: mvn
+    image: registry.access.redhat.com/ubi8/openjdk-11:1.3
+    workingDir: $(workspaces.source.path)
+    command: ["/bin/sh", "-c"]
+    args:
+    - |
+      MIRROR_OPTS=""
+      if [ -n "$(params.MAVEN_MIRROR_URL)" ]; then
+        MIRROR_OPTS="-Dmaven.repo.remote=$(params.MAVEN_MIRROR_URL)"
+      fi
+      mvn -B ${MIRROR_OPTS} "$@"
+    - mvn
+    - $(params.GOALS)
+`
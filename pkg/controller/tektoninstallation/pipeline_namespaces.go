@@ -0,0 +1,354 @@
+package tektoninstallation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	toolchainapiv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-common/pkg/condition"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+
+	"github.com/go-logr/logr"
+	errs "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ensurePipelineNamespaces makes sure that the namespaces selected in the TektonInstallation spec have the pipeline ServiceAccount,
+// its RoleBinding and its secrets, and removes them from the namespaces which are not selected anymore
+func (r *ReconcileTektonInstallation) ensurePipelineNamespaces(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation) error {
+	spec := tektonInstallation.Spec.PipelineNamespaces
+	selected := map[string]bool{}
+	var namespaces []string
+	if spec != nil {
+		var err error
+		if namespaces, err = r.selectPipelineNamespaces(spec); err != nil {
+			return r.wrapErrorWithStatusUpdate(logger, tektonInstallation, r.setStatusPipelineNamespacesFailed, err, "failed to select the pipeline namespaces")
+		}
+	}
+	// the namespaces which are not selected anymore are not watched anymore
+	if err := r.watchReferences(tektonInstallation, namespaces...); err != nil {
+		return r.wrapErrorWithStatusUpdate(logger, tektonInstallation, r.setStatusPipelineNamespacesFailed, err, "failed to watch the pipeline namespaces")
+	}
+	if spec != nil {
+		sources, err := r.getPipelineSecrets(spec)
+		if err != nil {
+			return r.wrapErrorWithStatusUpdate(logger, tektonInstallation, r.setStatusPipelineNamespacesFailed, err, "failed to get the pipeline secrets")
+		}
+		for _, ns := range namespaces {
+			if err := r.ensurePipelineNamespace(logger, tektonInstallation, ns, sources); err != nil {
+				return r.wrapErrorWithStatusUpdate(logger, tektonInstallation, r.setStatusPipelineNamespacesFailed, err, "failed to provision the pipeline namespace %s", ns)
+			}
+			selected[ns] = true
+		}
+	}
+	if err := r.cleanupPipelineNamespaces(logger, tektonInstallation, selected); err != nil {
+		return r.wrapErrorWithStatusUpdate(logger, tektonInstallation, r.setStatusPipelineNamespacesFailed, err, "failed to clean up the namespaces which are not selected anymore")
+	}
+	return r.statusUpdate(logger, tektonInstallation, r.setStatusPipelineNamespacesProvisioned(selected), "")
+}
+
+// selectPipelineNamespaces returns the sorted names of the active namespaces matching the selector
+func (r *ReconcileTektonInstallation) selectPipelineNamespaces(spec *v1alpha1.PipelineNamespaces) ([]string, error) {
+	if len(spec.Selector.MatchLabels) == 0 && len(spec.Selector.MatchExpressions) == 0 {
+		// an empty selector would match all the namespaces of the cluster
+		return nil, fmt.Errorf("the namespace selector must not be empty")
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err := r.client.List(context.TODO(), namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, ns := range namespaces.Items {
+		if ns.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		names = append(names, ns.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (r *ReconcileTektonInstallation) getPipelineSecrets(spec *v1alpha1.PipelineNamespaces) ([]*corev1.Secret, error) {
+	sources := make([]*corev1.Secret, 0, len(spec.Secrets))
+	for _, ref := range spec.Secrets {
+		secret := &corev1.Secret{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+			return nil, errs.Wrapf(err, "unable to get the secret %s/%s", ref.Namespace, ref.Name)
+		}
+		sources = append(sources, secret)
+	}
+	return sources, nil
+}
+
+func (r *ReconcileTektonInstallation) ensurePipelineNamespace(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation, ns string, sources []*corev1.Secret) error {
+	secretNames := make([]string, 0, len(sources))
+	for _, source := range sources {
+		if source.Namespace == ns {
+			// no need to copy the secret into its own namespace
+			secretNames = append(secretNames, source.Name)
+			continue
+		}
+		if err := r.ensurePipelineSecret(logger, tektonInstallation, NewPipelineSecret(tektonInstallation, ns, source)); err != nil {
+			return err
+		}
+		secretNames = append(secretNames, source.Name)
+	}
+	if err := r.ensurePipelineServiceAccount(logger, tektonInstallation, ns, secretNames); err != nil {
+		return err
+	}
+	return r.ensurePipelineRoleBinding(logger, tektonInstallation, NewPipelineRoleBinding(tektonInstallation, ns))
+}
+
+func (r *ReconcileTektonInstallation) ensurePipelineSecret(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation, secret *corev1.Secret) error {
+	toolchain.SetCommonMetadata(secret, tektonInstallation.Spec.CommonMetadata)
+	existing := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if err := controllerutil.SetControllerReference(tektonInstallation, secret, r.scheme); err != nil {
+			return err
+		}
+		logger.Info("Creating the pipeline secret", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+		return r.client.Create(context.TODO(), secret)
+	}
+	if existing.Labels[toolchain.OwnerLabelKey] != tektonInstallation.Name {
+		return fmt.Errorf("secret %s/%s already exists and is not managed by the operator", secret.Namespace, secret.Name)
+	}
+	changed := existing.Type != secret.Type || !reflect.DeepEqual(existing.Data, secret.Data)
+	existing.Type = secret.Type
+	existing.Data = secret.Data
+	if toolchain.SetCommonMetadata(existing, tektonInstallation.Spec.CommonMetadata) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	logger.Info("Updating the pipeline secret", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+	return r.client.Update(context.TODO(), existing)
+}
+
+// ensurePipelineServiceAccount creates the pipeline ServiceAccount, or adds the missing secret references if it already
+// exists (eg: if it was created by the OpenShift Pipelines operator)
+func (r *ReconcileTektonInstallation) ensurePipelineServiceAccount(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation, ns string, secretNames []string) error {
+	sa := &corev1.ServiceAccount{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: PipelineServiceAccountName}, sa); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		sa = NewPipelineServiceAccount(tektonInstallation, ns, secretNames...)
+		toolchain.SetCommonMetadata(sa, tektonInstallation.Spec.CommonMetadata)
+		if err := controllerutil.SetControllerReference(tektonInstallation, sa, r.scheme); err != nil {
+			return err
+		}
+		logger.Info("Creating the pipeline ServiceAccount", "ServiceAccount.Namespace", ns)
+		return r.client.Create(context.TODO(), sa)
+	}
+	changed := false
+	for _, name := range secretNames {
+		if !hasSecretReference(sa, name) {
+			sa.Secrets = append(sa.Secrets, corev1.ObjectReference{Name: name})
+			changed = true
+		}
+	}
+	// the common metadata is only set on the ServiceAccount created by the operator
+	if sa.Labels[toolchain.OwnerLabelKey] == tektonInstallation.Name && toolchain.SetCommonMetadata(sa, tektonInstallation.Spec.CommonMetadata) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	logger.Info("Updating the secret references and the common metadata of the pipeline ServiceAccount", "ServiceAccount.Namespace", ns)
+	return r.client.Update(context.TODO(), sa)
+}
+
+func (r *ReconcileTektonInstallation) ensurePipelineRoleBinding(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation, rb *rbacv1.RoleBinding) error {
+	toolchain.SetCommonMetadata(rb, tektonInstallation.Spec.CommonMetadata)
+	existing := &rbacv1.RoleBinding{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: rb.Namespace, Name: rb.Name}, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if err := controllerutil.SetControllerReference(tektonInstallation, rb, r.scheme); err != nil {
+			return err
+		}
+		logger.Info("Creating the pipeline RoleBinding", "RoleBinding.Namespace", rb.Namespace)
+		return r.client.Create(context.TODO(), rb)
+	}
+	if existing.Labels[toolchain.OwnerLabelKey] != tektonInstallation.Name {
+		return fmt.Errorf("RoleBinding %s/%s already exists and is not managed by the operator", rb.Namespace, rb.Name)
+	}
+	if !reflect.DeepEqual(existing.RoleRef, rb.RoleRef) {
+		// the role of a RoleBinding can't be changed
+		logger.Info("Recreating the pipeline RoleBinding with another role", "RoleBinding.Namespace", rb.Namespace, "RoleRef.Name", existing.RoleRef.Name)
+		if err := r.client.Delete(context.TODO(), existing); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err := controllerutil.SetControllerReference(tektonInstallation, rb, r.scheme); err != nil {
+			return err
+		}
+		return r.client.Create(context.TODO(), rb)
+	}
+	changed := !reflect.DeepEqual(existing.Subjects, rb.Subjects)
+	existing.Subjects = rb.Subjects
+	if toolchain.SetCommonMetadata(existing, tektonInstallation.Spec.CommonMetadata) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	logger.Info("Restoring the subjects and the common metadata of the pipeline RoleBinding", "RoleBinding.Namespace", rb.Namespace)
+	return r.client.Update(context.TODO(), existing)
+}
+
+// cleanupPipelineNamespaces deletes the secrets, ServiceAccounts and RoleBindings managed by the operator in the namespaces
+// which are not selected anymore. It also removes the references to the deleted secrets from the pipeline ServiceAccounts
+// that were not created by the operator.
+func (r *ReconcileTektonInstallation) cleanupPipelineNamespaces(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation, selected map[string]bool) error {
+	managed := client.MatchingLabels(toolchain.LabelsWithOwner(tektonInstallation.Name))
+
+	secrets := &corev1.SecretList{}
+	if err := r.client.List(context.TODO(), secrets, managed); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if selected[secret.Namespace] {
+			continue
+		}
+		logger.Info("Deleting the pipeline secret", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+		if err := r.client.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err := r.removeSecretReference(logger, tektonInstallation, secret.Namespace, secret.Name); err != nil {
+			return err
+		}
+	}
+
+	serviceAccounts := &corev1.ServiceAccountList{}
+	if err := r.client.List(context.TODO(), serviceAccounts, managed); err != nil {
+		return err
+	}
+	for i := range serviceAccounts.Items {
+		sa := &serviceAccounts.Items[i]
+		if selected[sa.Namespace] {
+			continue
+		}
+		logger.Info("Deleting the pipeline ServiceAccount", "ServiceAccount.Namespace", sa.Namespace)
+		if err := r.client.Delete(context.TODO(), sa); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	roleBindings := &rbacv1.RoleBindingList{}
+	if err := r.client.List(context.TODO(), roleBindings, managed); err != nil {
+		return err
+	}
+	for i := range roleBindings.Items {
+		rb := &roleBindings.Items[i]
+		if selected[rb.Namespace] {
+			continue
+		}
+		logger.Info("Deleting the pipeline RoleBinding", "RoleBinding.Namespace", rb.Namespace)
+		if err := r.client.Delete(context.TODO(), rb); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReconcileTektonInstallation) removeSecretReference(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation, ns, secretName string) error {
+	sa := &corev1.ServiceAccount{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: PipelineServiceAccountName}, sa); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if sa.Labels[toolchain.OwnerLabelKey] == tektonInstallation.Name || !hasSecretReference(sa, secretName) {
+		// the ServiceAccount created by the operator is deleted anyway
+		return nil
+	}
+	var refs []corev1.ObjectReference
+	for _, ref := range sa.Secrets {
+		if ref.Name != secretName {
+			refs = append(refs, ref)
+		}
+	}
+	sa.Secrets = refs
+	logger.Info("Removing the secret reference from the pipeline ServiceAccount", "ServiceAccount.Namespace", ns, "Secret.Name", secretName)
+	return r.client.Update(context.TODO(), sa)
+}
+
+func hasSecretReference(sa *corev1.ServiceAccount, name string) bool {
+	for _, ref := range sa.Secrets {
+		if ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ReconcileTektonInstallation) setStatusPipelineNamespacesFailed(tektonInstallation *v1alpha1.TektonInstallation, message string) error {
+	return r.updateStatusConditions(tektonInstallation, PipelineNamespacesProvisioningFailed(message))
+}
+
+func (r *ReconcileTektonInstallation) setStatusPipelineNamespacesProvisioned(selected map[string]bool) func(*v1alpha1.TektonInstallation, string) error {
+	return func(tektonInstallation *v1alpha1.TektonInstallation, _ string) error {
+		var namespaces []string
+		for ns := range selected {
+			namespaces = append(namespaces, ns)
+		}
+		sort.Strings(namespaces)
+		namespacesChanged := !reflect.DeepEqual(tektonInstallation.Status.PipelineNamespaces, namespaces)
+		tektonInstallation.Status.PipelineNamespaces = namespaces
+		conditionsChanged := false
+		if tektonInstallation.Spec.PipelineNamespaces != nil {
+			tektonInstallation.Status.Conditions, conditionsChanged = condition.AddOrUpdateStatusConditions(tektonInstallation.Status.Conditions, PipelineNamespacesProvisioned())
+		} else {
+			tektonInstallation.Status.Conditions, conditionsChanged = removeCondition(tektonInstallation.Status.Conditions, v1alpha1.PipelineNamespacesReady)
+		}
+		if !namespacesChanged && !conditionsChanged {
+			// Nothing changed
+			return nil
+		}
+		return r.client.Status().Update(context.TODO(), tektonInstallation)
+	}
+}
+
+func removeCondition(conditions []toolchainapiv1alpha1.Condition, conditionType toolchainapiv1alpha1.ConditionType) ([]toolchainapiv1alpha1.Condition, bool) {
+	var result []toolchainapiv1alpha1.Condition
+	for _, c := range conditions {
+		if c.Type != conditionType {
+			result = append(result, c)
+		}
+	}
+	return result, len(result) != len(conditions)
+}
+
+// referencedSecrets returns the secrets referenced in the spec of the given TektonInstallation, which are copied into the pipeline
+// namespaces, so that the copies are updated when the secrets change
+func referencedSecrets(tektonInstallation *v1alpha1.TektonInstallation) []types.NamespacedName {
+	spec := tektonInstallation.Spec.PipelineNamespaces
+	if spec == nil {
+		return nil
+	}
+	refs := make([]types.NamespacedName, 0, len(spec.Secrets))
+	for _, ref := range spec.Secrets {
+		refs = append(refs, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name})
+	}
+	return refs
+}
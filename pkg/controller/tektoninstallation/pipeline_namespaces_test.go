@@ -0,0 +1,401 @@
+package tektoninstallation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+	. "github.com/codeready-toolchain/toolchain-operator/test/assert"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	config "github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPipelineNamespaces(t *testing.T) {
+
+	t.Run("should provision the selected namespaces", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationWithPipelineNamespaces(v1alpha1.SecretReference{Namespace: "toolchain-operator", Name: "registry-credentials"})
+		source := newSecret("toolchain-operator", "registry-credentials", "token")
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()), installedTektonConfig(), source,
+			newNamespace("team-a", true), newNamespace("team-b", true), newNamespace("team-c", false))
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+		var watched []string
+		r.watchReferences = func(_ *v1alpha1.TektonInstallation, namespaces ...string) error {
+			watched = namespaces
+			return nil
+		}
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions(InstallationSucceeded(), PipelineNamespacesProvisioned()).
+			HasPipelineNamespaces("team-a", "team-b")
+		assert.Equal(t, []string{"team-a", "team-b"}, watched)
+		for _, ns := range []string{"team-a", "team-b"} {
+			sa := &corev1.ServiceAccount{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: PipelineServiceAccountName}, sa))
+			assert.Equal(t, []corev1.ObjectReference{{Name: "registry-credentials"}}, sa.Secrets)
+			require.Len(t, sa.OwnerReferences, 1)
+			assert.Equal(t, InstallationName, sa.OwnerReferences[0].Name)
+
+			rb := &rbacv1.RoleBinding{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: PipelineRoleBindingName}, rb))
+			assert.Equal(t, NewPipelineRoleBinding(tektonInstallation, ns).Subjects, rb.Subjects)
+			assert.Equal(t, "edit", rb.RoleRef.Name)
+
+			secret := &corev1.Secret{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "registry-credentials"}, secret))
+			assert.Equal(t, source.Data, secret.Data)
+			assert.Equal(t, source.Type, secret.Type)
+		}
+		assertNoPipelineObjects(t, cl, "team-c")
+	})
+
+	t.Run("should label the pipeline objects with the name of the installation", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationWithPipelineNamespaces(v1alpha1.SecretReference{Namespace: "toolchain-operator", Name: "registry-credentials"})
+		tektonInstallation.Name = "team-pipelines"
+		cl, r := configureClient(t, tektonInstallation, newSecret("toolchain-operator", "registry-credentials", "token"), newNamespace("team-a", true))
+
+		// when
+		err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+		// then
+		require.NoError(t, err)
+		expected := toolchain.LabelsWithOwner("team-pipelines")
+		sa := &corev1.ServiceAccount{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: PipelineServiceAccountName}, sa))
+		assert.Equal(t, expected, sa.Labels)
+		rb := &rbacv1.RoleBinding{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: PipelineRoleBindingName}, rb))
+		assert.Equal(t, expected, rb.Labels)
+		secret := &corev1.Secret{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: "registry-credentials"}, secret))
+		assert.Equal(t, expected, secret.Labels)
+
+		t.Run("should clean up the namespace which is not selected anymore", func(t *testing.T) {
+			// given
+			ns := &corev1.Namespace{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "team-a"}, ns))
+			ns.Labels = nil
+			require.NoError(t, cl.Update(context.TODO(), ns))
+
+			// when
+			err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+			// then
+			require.NoError(t, err)
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: PipelineRoleBindingName}, &rbacv1.RoleBinding{})
+			assert.True(t, apierrors.IsNotFound(err))
+			err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: "registry-credentials"}, &corev1.Secret{})
+			assert.True(t, apierrors.IsNotFound(err))
+		})
+	})
+
+	t.Run("should set the common labels on the pipeline objects", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationWithPipelineNamespaces(v1alpha1.SecretReference{Namespace: "toolchain-operator", Name: "registry-credentials"})
+		tektonInstallation.Spec.CommonMetadata = &v1alpha1.CommonMetadata{Labels: map[string]string{"cost-center": "1234"}}
+		copied := NewPipelineSecret(tektonInstallation, "team-a", newSecret("toolchain-operator", "registry-credentials", "token"))
+		cl, r := configureClient(t, tektonInstallation, newSecret("toolchain-operator", "registry-credentials", "token"), copied, newNamespace("team-a", true))
+
+		// when
+		err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+		// then
+		require.NoError(t, err)
+		expected := toolchain.LabelsWithOwner(InstallationName)
+		expected["cost-center"] = "1234"
+		secret := &corev1.Secret{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: "registry-credentials"}, secret))
+		assert.Equal(t, expected, secret.Labels)
+		sa := &corev1.ServiceAccount{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: PipelineServiceAccountName}, sa))
+		assert.Equal(t, expected, sa.Labels)
+		rb := &rbacv1.RoleBinding{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: PipelineRoleBindingName}, rb))
+		assert.Equal(t, expected, rb.Labels)
+	})
+
+	t.Run("should update the copied secret when the source changed", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationWithPipelineNamespaces(v1alpha1.SecretReference{Namespace: "toolchain-operator", Name: "registry-credentials"})
+		copied := NewPipelineSecret(tektonInstallation, "team-a", newSecret("toolchain-operator", "registry-credentials", "old-token"))
+		cl, r := configureClient(t, tektonInstallation, newSecret("toolchain-operator", "registry-credentials", "new-token"), copied, newNamespace("team-a", true))
+
+		// when
+		err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+		// then
+		require.NoError(t, err)
+		secret := &corev1.Secret{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: "registry-credentials"}, secret))
+		assert.Equal(t, []byte("new-token"), secret.Data["token"])
+	})
+
+	t.Run("should add the secret references to an existing ServiceAccount", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationWithPipelineNamespaces(v1alpha1.SecretReference{Namespace: "toolchain-operator", Name: "registry-credentials"})
+		existing := &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: PipelineServiceAccountName},
+			Secrets:    []corev1.ObjectReference{{Name: "pipeline-dockercfg"}},
+		}
+		cl, r := configureClient(t, tektonInstallation, newSecret("toolchain-operator", "registry-credentials", "token"), existing, newNamespace("team-a", true))
+
+		// when
+		err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+		// then
+		require.NoError(t, err)
+		sa := &corev1.ServiceAccount{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: PipelineServiceAccountName}, sa))
+		assert.Equal(t, []corev1.ObjectReference{{Name: "pipeline-dockercfg"}, {Name: "registry-credentials"}}, sa.Secrets)
+		assert.Empty(t, sa.OwnerReferences)
+
+		t.Run("should remove the secret reference when the namespace is not selected anymore", func(t *testing.T) {
+			// given
+			ns := &corev1.Namespace{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "team-a"}, ns))
+			ns.Labels = nil
+			require.NoError(t, cl.Update(context.TODO(), ns))
+
+			// when
+			err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+			// then
+			require.NoError(t, err)
+			sa := &corev1.ServiceAccount{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: PipelineServiceAccountName}, sa))
+			assert.Equal(t, []corev1.ObjectReference{{Name: "pipeline-dockercfg"}}, sa.Secrets)
+			assertNoPipelineObjects(t, cl, "team-a")
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasPipelineNamespaces()
+		})
+	})
+
+	t.Run("should recreate the RoleBinding when its role changed", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationWithPipelineNamespaces()
+		existing := NewPipelineRoleBinding(tektonInstallation, "team-a")
+		existing.RoleRef.Name = "admin"
+		cl, r := configureClient(t, tektonInstallation, existing, newNamespace("team-a", true))
+
+		// when
+		err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+		// then
+		require.NoError(t, err)
+		rb := &rbacv1.RoleBinding{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: PipelineRoleBindingName}, rb))
+		assert.Equal(t, "edit", rb.RoleRef.Name)
+		assert.Equal(t, NewPipelineRoleBinding(tektonInstallation, "team-a").Subjects, rb.Subjects)
+		require.Len(t, rb.OwnerReferences, 1)
+	})
+
+	t.Run("should clean up the namespaces which are not selected anymore", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationWithPipelineNamespaces()
+		cl, r := configureClient(t, tektonInstallation, newNamespace("team-a", false),
+			NewPipelineServiceAccount(tektonInstallation, "team-a"), NewPipelineRoleBinding(tektonInstallation, "team-a"), NewPipelineSecret(tektonInstallation, "team-a", newSecret("toolchain-operator", "registry-credentials", "token")))
+
+		// when
+		err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+		// then
+		require.NoError(t, err)
+		assertNoPipelineObjects(t, cl, "team-a")
+		sa := &corev1.ServiceAccount{}
+		err = cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: PipelineServiceAccountName}, sa)
+		assert.True(t, apierrors.IsNotFound(err))
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions(PipelineNamespacesProvisioned()).
+			HasPipelineNamespaces()
+	})
+
+	t.Run("should clean up the namespaces when the spec section is removed", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonInstallation.Status.Conditions = append(tektonInstallation.Status.Conditions, PipelineNamespacesProvisioned())
+		tektonInstallation.Status.PipelineNamespaces = []string{"team-a"}
+		cl, r := configureClient(t, tektonInstallation, newNamespace("team-a", true), NewPipelineRoleBinding(tektonInstallation, "team-a"))
+		watched := []string{"team-a"}
+		r.watchReferences = func(_ *v1alpha1.TektonInstallation, namespaces ...string) error {
+			watched = namespaces
+			return nil
+		}
+
+		// when
+		err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, watched)
+		assertNoPipelineObjects(t, cl, "team-a")
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions().
+			HasPipelineNamespaces()
+	})
+
+	t.Run("failures", func(t *testing.T) {
+
+		t.Run("empty selector", func(t *testing.T) {
+			// given
+			tektonInstallation := newInstallationWithPipelineNamespaces()
+			tektonInstallation.Spec.PipelineNamespaces.Selector = metav1.LabelSelector{}
+			cl, r := configureClient(t, tektonInstallation, newNamespace("team-a", true))
+
+			// when
+			err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+			// then
+			require.EqualError(t, err, "failed to select the pipeline namespaces: the namespace selector must not be empty")
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasConditions(PipelineNamespacesProvisioningFailed("the namespace selector must not be empty"))
+			assertNoPipelineObjects(t, cl, "team-a")
+		})
+
+		t.Run("missing secret", func(t *testing.T) {
+			// given
+			tektonInstallation := newInstallationWithPipelineNamespaces(v1alpha1.SecretReference{Namespace: "toolchain-operator", Name: "unknown"})
+			cl, r := configureClient(t, tektonInstallation, newNamespace("team-a", true))
+
+			// when
+			err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to get the pipeline secrets: unable to get the secret toolchain-operator/unknown")
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasConditions(PipelineNamespacesProvisioningFailed(`unable to get the secret toolchain-operator/unknown: secrets "unknown" not found`))
+		})
+
+		t.Run("secret not managed by the operator", func(t *testing.T) {
+			// given
+			tektonInstallation := newInstallationWithPipelineNamespaces(v1alpha1.SecretReference{Namespace: "toolchain-operator", Name: "registry-credentials"})
+			cl, r := configureClient(t, tektonInstallation, newSecret("toolchain-operator", "registry-credentials", "token"),
+				newSecret("team-a", "registry-credentials", "other"), newNamespace("team-a", true))
+
+			// when
+			err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+			// then
+			require.EqualError(t, err, "failed to provision the pipeline namespace team-a: secret team-a/registry-credentials already exists and is not managed by the operator")
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasConditions(PipelineNamespacesProvisioningFailed("secret team-a/registry-credentials already exists and is not managed by the operator"))
+		})
+
+		t.Run("RoleBinding not managed by the operator", func(t *testing.T) {
+			// given
+			tektonInstallation := newInstallationWithPipelineNamespaces()
+			foreign := NewPipelineRoleBinding(tektonInstallation, "team-a")
+			foreign.Labels = nil
+			foreign.Subjects = []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "developer"}}
+			cl, r := configureClient(t, tektonInstallation, foreign, newNamespace("team-a", true))
+
+			// when
+			err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+			// then
+			require.EqualError(t, err, "failed to provision the pipeline namespace team-a: RoleBinding team-a/pipeline-edit already exists and is not managed by the operator")
+			rb := &rbacv1.RoleBinding{}
+			require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: PipelineRoleBindingName}, rb))
+			assert.Equal(t, foreign.Subjects, rb.Subjects)
+		})
+
+		t.Run("create RoleBinding fails", func(t *testing.T) {
+			// given
+			tektonInstallation := newInstallationWithPipelineNamespaces()
+			cl, r := configureClient(t, tektonInstallation, newNamespace("team-a", true))
+			cl.MockCreate = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+				if _, ok := obj.(*rbacv1.RoleBinding); ok {
+					return errors.New("unable to create RoleBinding")
+				}
+				return cl.Client.Create(ctx, obj, opts...)
+			}
+
+			// when
+			err := r.ensurePipelineNamespaces(log, tektonInstallation)
+
+			// then
+			require.EqualError(t, err, "failed to provision the pipeline namespace team-a: unable to create RoleBinding")
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasConditions(PipelineNamespacesProvisioningFailed("unable to create RoleBinding"))
+		})
+	})
+}
+
+func TestReferencedSecrets(t *testing.T) {
+	// given
+	tektonInstallation := newInstallationWithPipelineNamespaces(
+		v1alpha1.SecretReference{Namespace: "toolchain-operator", Name: "registry-credentials"},
+		v1alpha1.SecretReference{Namespace: "team-a", Name: "git-credentials"})
+
+	// when
+	refs := referencedSecrets(tektonInstallation)
+
+	// then
+	assert.Equal(t, []types.NamespacedName{
+		{Namespace: "toolchain-operator", Name: "registry-credentials"},
+		{Namespace: "team-a", Name: "git-credentials"},
+	}, refs)
+	assert.Empty(t, referencedSecrets(NewInstallation()))
+}
+
+func newInstallationWithPipelineNamespaces(secrets ...v1alpha1.SecretReference) *v1alpha1.TektonInstallation {
+	tektonInstallation := NewInstallation()
+	tektonInstallation.Spec.PipelineNamespaces = &v1alpha1.PipelineNamespaces{
+		Selector: metav1.LabelSelector{MatchLabels: map[string]string{"pipelines": "enabled"}},
+		Secrets:  secrets,
+	}
+	return tektonInstallation
+}
+
+func installedTektonConfig() *config.Config {
+	return newTektonConfig(config.InstalledStatus)
+}
+
+func newNamespace(name string, selected bool) *corev1.Namespace {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+	}
+	if selected {
+		ns.Labels = map[string]string{"pipelines": "enabled"}
+	}
+	return ns
+}
+
+func newSecret(ns, name, token string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{"token": []byte(token)},
+	}
+}
+
+func assertNoPipelineObjects(t *testing.T, cl client.Client, ns string) {
+	rb := &rbacv1.RoleBinding{}
+	err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: PipelineRoleBindingName}, rb)
+	assert.True(t, apierrors.IsNotFound(err), "RoleBinding found in namespace %s", ns)
+	secrets := &corev1.SecretList{}
+	require.NoError(t, cl.List(context.TODO(), secrets, client.InNamespace(ns)))
+	for _, secret := range secrets.Items {
+		assert.NotEqual(t, InstallationName, secret.Labels[toolchain.OwnerLabelKey], "secret %s found in namespace %s", secret.Name, ns)
+	}
+}
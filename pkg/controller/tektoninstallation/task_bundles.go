@@ -0,0 +1,313 @@
+package tektoninstallation
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/codeready-toolchain/toolchain-common/pkg/condition"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+
+	"github.com/go-logr/logr"
+	errs "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// TaskBundleLabelKey the key of the label which contains the name of the bundle of a task applied by the operator
+	TaskBundleLabelKey = "toolchain.openshift.dev/task-bundle"
+	// TaskBundleVersionAnnotationKey the key of the annotation which contains the version of the bundle of a task applied by the operator.
+	// The same annotation can be set on a ConfigMap to give the version of its bundle, otherwise the version is a hash of its content
+	TaskBundleVersionAnnotationKey = "toolchain.openshift.dev/task-bundle-version"
+
+	clusterTaskKind = "ClusterTask"
+	taskKind        = "Task"
+)
+
+// tektonGroupVersion the group and version used to list the tasks applied by the operator
+var tektonGroupVersion = schema.GroupVersion{Group: "tekton.dev", Version: "v1beta1"}
+
+type taskBundle struct {
+	name    string
+	version string
+	tasks   []*unstructured.Unstructured
+}
+
+// ensureTaskBundles applies the tasks of the bundles referenced in the TektonInstallation spec, updates them when the version
+// of their bundle changed and deletes the tasks which are not part of any bundle anymore
+func (r *ReconcileTektonInstallation) ensureTaskBundles(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation) error {
+	var bundles []taskBundle
+	for _, spec := range tektonInstallation.Spec.TaskBundles {
+		bundle, err := r.loadTaskBundle(tektonInstallation, spec)
+		if err != nil {
+			return r.wrapErrorWithStatusUpdate(logger, tektonInstallation, r.setStatusTasksSyncFailed, err, "failed to load the task bundle %s", spec.Name)
+		}
+		bundles = append(bundles, bundle)
+	}
+
+	// the bundle of each task applied, indexed by kind, namespace and name
+	applied := map[string]string{}
+	for _, bundle := range bundles {
+		for _, task := range bundle.tasks {
+			for _, t := range withTaskNamespaces(task, tektonInstallation.Status.PipelineNamespaces) {
+				key := taskKey(t)
+				if other, found := applied[key]; found {
+					err := fmt.Errorf("%s %s is defined in the bundles %s and %s", t.GetKind(), taskName(t), other, bundle.name)
+					return r.wrapErrorWithStatusUpdate(logger, tektonInstallation, r.setStatusTasksSyncFailed, err, "failed to apply the task bundle %s", bundle.name)
+				}
+				if err := r.applyTask(logger, tektonInstallation, bundle, t); err != nil {
+					return r.wrapErrorWithStatusUpdate(logger, tektonInstallation, r.setStatusTasksSyncFailed, err, "failed to apply the task bundle %s", bundle.name)
+				}
+				applied[key] = bundle.name
+			}
+		}
+	}
+
+	if err := r.pruneTasks(logger, tektonInstallation, applied); err != nil {
+		return r.wrapErrorWithStatusUpdate(logger, tektonInstallation, r.setStatusTasksSyncFailed, err, "failed to prune the tasks which are not part of any bundle")
+	}
+	return r.statusUpdate(logger, tektonInstallation, r.setStatusTasksSynced(bundles), "")
+}
+
+// loadTaskBundle loads the manifests of the tasks of the given bundle, either from the operator or from a ConfigMap
+func (r *ReconcileTektonInstallation) loadTaskBundle(tektonInstallation *v1alpha1.TektonInstallation, spec v1alpha1.TaskBundle) (taskBundle, error) {
+	bundle := taskBundle{name: spec.Name}
+	var manifests map[string]string
+	switch {
+	case spec.Embedded != "" && spec.ConfigMap != nil:
+		return bundle, fmt.Errorf("only one of 'embedded' and 'configMap' must be set")
+	case spec.Embedded != "":
+		embedded, found := embeddedTaskBundles[spec.Embedded]
+		if !found {
+			return bundle, fmt.Errorf("unknown embedded task bundle '%s'", spec.Embedded)
+		}
+		bundle.version = embedded.version
+		manifests = embedded.manifests
+	case spec.ConfigMap != nil:
+		cm := &corev1.ConfigMap{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: spec.ConfigMap.Namespace, Name: spec.ConfigMap.Name}, cm); err != nil {
+			return bundle, errs.Wrapf(err, "unable to get the ConfigMap %s/%s", spec.ConfigMap.Namespace, spec.ConfigMap.Name)
+		}
+		bundle.version = cm.Annotations[TaskBundleVersionAnnotationKey]
+		if bundle.version == "" {
+			bundle.version = hashOf(cm.Data)
+		}
+		manifests = cm.Data
+	default:
+		return bundle, fmt.Errorf("one of 'embedded' and 'configMap' must be set")
+	}
+
+	keys := make([]string, 0, len(manifests))
+	for key := range manifests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		tasks, err := decodeTasks(manifests[key])
+		if err != nil {
+			return bundle, errs.Wrapf(err, "invalid manifest '%s'", key)
+		}
+		for _, task := range tasks {
+			labels := task.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			for k, v := range toolchain.LabelsWithOwner(tektonInstallation.Name) {
+				labels[k] = v
+			}
+			labels[TaskBundleLabelKey] = bundle.name
+			task.SetLabels(labels)
+			annotations := task.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[TaskBundleVersionAnnotationKey] = bundle.version
+			task.SetAnnotations(annotations)
+		}
+		bundle.tasks = append(bundle.tasks, tasks...)
+	}
+	return bundle, nil
+}
+
+// decodeTasks decodes the Tasks and ClusterTasks of the given YAML (or JSON) manifest, which may contain several documents
+func decodeTasks(manifest string) ([]*unstructured.Unstructured, error) {
+	var tasks []*unstructured.Unstructured
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	for {
+		task := &unstructured.Unstructured{}
+		if err := decoder.Decode(&task.Object); err != nil {
+			if err == io.EOF {
+				return tasks, nil
+			}
+			return nil, err
+		}
+		if len(task.Object) == 0 {
+			// empty document
+			continue
+		}
+		gvk := task.GroupVersionKind()
+		if gvk.Group != tektonGroupVersion.Group || (gvk.Kind != clusterTaskKind && gvk.Kind != taskKind) {
+			return nil, fmt.Errorf("only the Tasks and ClusterTasks of the %s group are supported but found '%s' of '%s'", tektonGroupVersion.Group, gvk.Kind, gvk.GroupVersion())
+		}
+		if task.GetName() == "" {
+			return nil, fmt.Errorf("the %s has no name", gvk.Kind)
+		}
+		if gvk.Kind == clusterTaskKind && task.GetNamespace() != "" {
+			return nil, fmt.Errorf("the ClusterTask %s must not have a namespace", task.GetName())
+		}
+		tasks = append(tasks, task)
+	}
+}
+
+// withTaskNamespaces returns the given task if it is a ClusterTask or a Task with a namespace, or a copy of the given
+// Task in each of the pipeline namespaces otherwise
+func withTaskNamespaces(task *unstructured.Unstructured, pipelineNamespaces []string) []*unstructured.Unstructured {
+	if task.GetKind() == clusterTaskKind || task.GetNamespace() != "" {
+		return []*unstructured.Unstructured{task}
+	}
+	tasks := make([]*unstructured.Unstructured, 0, len(pipelineNamespaces))
+	for _, ns := range pipelineNamespaces {
+		t := task.DeepCopy()
+		t.SetNamespace(ns)
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
+
+func (r *ReconcileTektonInstallation) applyTask(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation, bundle taskBundle, task *unstructured.Unstructured) error {
+	toolchain.SetCommonMetadata(task, tektonInstallation.Spec.CommonMetadata)
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(task.GroupVersionKind())
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: task.GetNamespace(), Name: task.GetName()}, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if err := controllerutil.SetControllerReference(tektonInstallation, task, r.scheme); err != nil {
+			return err
+		}
+		logger.Info("Creating the task", "Kind", task.GetKind(), "Task.Namespace", task.GetNamespace(), "Task.Name", task.GetName(), "Bundle", bundle.name)
+		return r.client.Create(context.TODO(), task)
+	}
+	if existing.GetLabels()[toolchain.OwnerLabelKey] != tektonInstallation.Name {
+		return fmt.Errorf("%s %s already exists and is not managed by the operator", task.GetKind(), taskName(task))
+	}
+	if existing.GetLabels()[TaskBundleLabelKey] == bundle.name && existing.GetAnnotations()[TaskBundleVersionAnnotationKey] == bundle.version &&
+		!toolchain.SetCommonMetadata(existing.DeepCopy(), tektonInstallation.Spec.CommonMetadata) {
+		// already up-to-date
+		return nil
+	}
+	if err := controllerutil.SetControllerReference(tektonInstallation, task, r.scheme); err != nil {
+		return err
+	}
+	logger.Info("Updating the task", "Kind", task.GetKind(), "Task.Namespace", task.GetNamespace(), "Task.Name", task.GetName(), "Bundle", bundle.name, "Version", bundle.version)
+	task.SetResourceVersion(existing.GetResourceVersion())
+	return r.client.Update(context.TODO(), task)
+}
+
+// pruneTasks deletes the Tasks and ClusterTasks applied by the operator which are not part of any bundle anymore
+func (r *ReconcileTektonInstallation) pruneTasks(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation, applied map[string]string) error {
+	for _, kind := range []string{clusterTaskKind, taskKind} {
+		tasks := &unstructured.UnstructuredList{}
+		tasks.SetGroupVersionKind(tektonGroupVersion.WithKind(kind + "List"))
+		if err := r.client.List(context.TODO(), tasks, client.MatchingLabels(toolchain.LabelsWithOwner(tektonInstallation.Name))); err != nil {
+			if meta.IsNoMatchError(err) {
+				// the Tekton resource types do not exist, hence there is nothing to prune
+				continue
+			}
+			return err
+		}
+		for i := range tasks.Items {
+			task := &tasks.Items[i]
+			if _, found := task.GetLabels()[TaskBundleLabelKey]; !found {
+				continue
+			}
+			if _, found := applied[taskKey(task)]; found {
+				continue
+			}
+			logger.Info("Deleting the task", "Kind", task.GetKind(), "Task.Namespace", task.GetNamespace(), "Task.Name", task.GetName())
+			if err := r.client.Delete(context.TODO(), task); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func taskKey(task *unstructured.Unstructured) string {
+	return task.GetKind() + "/" + taskName(task)
+}
+
+func taskName(task *unstructured.Unstructured) string {
+	if task.GetNamespace() == "" {
+		return task.GetName()
+	}
+	return task.GetNamespace() + "/" + task.GetName()
+}
+
+// hashOf returns a short hash of the given data, which does not depend on the order of the keys
+func hashOf(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s=%s\n", key, data[key])
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[:16]
+}
+
+func (r *ReconcileTektonInstallation) setStatusTasksSyncFailed(tektonInstallation *v1alpha1.TektonInstallation, message string) error {
+	return r.updateStatusConditions(tektonInstallation, TasksSyncFailed(message))
+}
+
+func (r *ReconcileTektonInstallation) setStatusTasksSynced(bundles []taskBundle) func(*v1alpha1.TektonInstallation, string) error {
+	return func(tektonInstallation *v1alpha1.TektonInstallation, _ string) error {
+		var bundlesStatus []v1alpha1.TaskBundleStatus
+		for _, bundle := range bundles {
+			bundlesStatus = append(bundlesStatus, v1alpha1.TaskBundleStatus{Name: bundle.name, Version: bundle.version})
+		}
+		bundlesChanged := !reflect.DeepEqual(tektonInstallation.Status.TaskBundles, bundlesStatus)
+		tektonInstallation.Status.TaskBundles = bundlesStatus
+		conditionsChanged := false
+		if len(tektonInstallation.Spec.TaskBundles) > 0 {
+			tektonInstallation.Status.Conditions, conditionsChanged = condition.AddOrUpdateStatusConditions(tektonInstallation.Status.Conditions, TasksSynced())
+		} else {
+			tektonInstallation.Status.Conditions, conditionsChanged = removeCondition(tektonInstallation.Status.Conditions, v1alpha1.TasksReady)
+		}
+		if !bundlesChanged && !conditionsChanged {
+			// Nothing changed
+			return nil
+		}
+		return r.client.Status().Update(context.TODO(), tektonInstallation)
+	}
+}
+
+// referencedConfigMaps returns the ConfigMaps referenced by the task bundles and by the trusted CA bundle in the spec of the given
+// TektonInstallation
+func referencedConfigMaps(tektonInstallation *v1alpha1.TektonInstallation) []types.NamespacedName {
+	var refs []types.NamespacedName
+	if bundle := tektonInstallation.Spec.TrustedCABundle; bundle != nil && bundle.ConfigMap != nil {
+		refs = append(refs, types.NamespacedName{Namespace: bundle.ConfigMap.Namespace, Name: bundle.ConfigMap.Name})
+	}
+	for _, bundle := range tektonInstallation.Spec.TaskBundles {
+		if bundle.ConfigMap != nil {
+			refs = append(refs, types.NamespacedName{Namespace: bundle.ConfigMap.Namespace, Name: bundle.ConfigMap.Name})
+		}
+	}
+	return refs
+}
@@ -0,0 +1,297 @@
+package tektoninstallation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
+	. "github.com/codeready-toolchain/toolchain-operator/test/assert"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	lintClusterTask = `
+apiVersion: tekton.dev/v1beta1
+kind: ClusterTask
+metadata:
+  name: lint
+spec:
+  steps:
+  - name: lint
+    image: golangci/golangci-lint:v1.27.0
+`
+	testTask = `
+apiVersion: tekton.dev/v1beta1
+kind: Task
+metadata:
+  name: unit-tests
+spec:
+  steps:
+  - name: test
+    image: golang:1.13
+---
+apiVersion: tekton.dev/v1beta1
+kind: Task
+metadata:
+  name: e2e-tests
+  namespace: ci
+spec:
+  steps:
+  - name: test
+    image: golang:1.13
+`
+)
+
+func TestTaskBundles(t *testing.T) {
+
+	t.Run("should apply the embedded bundle", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationWithTaskBundles(v1alpha1.TaskBundle{Name: "standard", Embedded: DefaultTaskBundleName})
+		cl, r := configureClient(t, tektonInstallation)
+
+		// when
+		err := r.ensureTaskBundles(log, tektonInstallation)
+
+		// then
+		require.NoError(t, err)
+		for _, name := range []string{"toolchain-git-clone", "toolchain-buildah", "toolchain-maven"} {
+			task := assertTask(t, cl, clusterTaskKind, "", name)
+			assert.Equal(t, "standard", task.GetLabels()[TaskBundleLabelKey])
+			assert.Equal(t, InstallationName, task.GetLabels()[toolchain.OwnerLabelKey])
+			assert.Equal(t, DefaultTaskBundleVersion, task.GetAnnotations()[TaskBundleVersionAnnotationKey])
+			require.Len(t, task.GetOwnerReferences(), 1)
+			assert.Equal(t, InstallationName, task.GetOwnerReferences()[0].Name)
+		}
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions(TasksSynced()).
+			HasTaskBundles(v1alpha1.TaskBundleStatus{Name: "standard", Version: DefaultTaskBundleVersion})
+	})
+
+	t.Run("should apply, update and prune the bundle of a ConfigMap", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationWithTaskBundles(v1alpha1.TaskBundle{
+			Name:      "team",
+			ConfigMap: &v1alpha1.ConfigMapReference{Namespace: "toolchain-operator", Name: "team-tasks"},
+		})
+		tektonInstallation.Status.PipelineNamespaces = []string{"team-a", "team-b"}
+		cm := newTaskBundleConfigMap(map[string]string{"lint": lintClusterTask, "tests": testTask})
+		cl, r := configureClient(t, tektonInstallation, cm)
+
+		// when
+		err := r.ensureTaskBundles(log, tektonInstallation)
+
+		// then
+		require.NoError(t, err)
+		version := hashOf(cm.Data)
+		assert.Equal(t, version, assertTask(t, cl, clusterTaskKind, "", "lint").GetAnnotations()[TaskBundleVersionAnnotationKey])
+		assertTask(t, cl, taskKind, "team-a", "unit-tests")
+		assertTask(t, cl, taskKind, "team-b", "unit-tests")
+		assertTask(t, cl, taskKind, "ci", "e2e-tests")
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions(TasksSynced()).
+			HasTaskBundles(v1alpha1.TaskBundleStatus{Name: "team", Version: version})
+
+		t.Run("should update the tasks when the ConfigMap changed", func(t *testing.T) {
+			// given
+			cm.Data["lint"] = lintClusterTask + "    args: [\"run\"]\n"
+			require.NoError(t, cl.Update(context.TODO(), cm))
+
+			// when
+			err := r.ensureTaskBundles(log, tektonInstallation)
+
+			// then
+			require.NoError(t, err)
+			version := hashOf(cm.Data)
+			lint := assertTask(t, cl, clusterTaskKind, "", "lint")
+			assert.Equal(t, version, lint.GetAnnotations()[TaskBundleVersionAnnotationKey])
+			steps, _, err := unstructured.NestedSlice(lint.Object, "spec", "steps")
+			require.NoError(t, err)
+			require.Len(t, steps, 1)
+			assert.Equal(t, []interface{}{"run"}, steps[0].(map[string]interface{})["args"])
+			assert.Equal(t, version, assertTask(t, cl, taskKind, "team-a", "unit-tests").GetAnnotations()[TaskBundleVersionAnnotationKey])
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasTaskBundles(v1alpha1.TaskBundleStatus{Name: "team", Version: version})
+		})
+
+		t.Run("should prune the tasks removed from the ConfigMap and the namespaces", func(t *testing.T) {
+			// given
+			delete(cm.Data, "lint")
+			require.NoError(t, cl.Update(context.TODO(), cm))
+			tektonInstallation.Status.PipelineNamespaces = []string{"team-a"}
+
+			// when
+			err := r.ensureTaskBundles(log, tektonInstallation)
+
+			// then
+			require.NoError(t, err)
+			assertNoTask(t, cl, clusterTaskKind, "", "lint")
+			assertTask(t, cl, taskKind, "team-a", "unit-tests")
+			assertNoTask(t, cl, taskKind, "team-b", "unit-tests")
+			assertTask(t, cl, taskKind, "ci", "e2e-tests")
+		})
+
+		t.Run("should prune all the tasks when no bundle is referenced anymore", func(t *testing.T) {
+			// given
+			tektonInstallation.Spec.TaskBundles = nil
+
+			// when
+			err := r.ensureTaskBundles(log, tektonInstallation)
+
+			// then
+			require.NoError(t, err)
+			assertNoTask(t, cl, taskKind, "team-a", "unit-tests")
+			assertNoTask(t, cl, taskKind, "ci", "e2e-tests")
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasConditions().
+				HasTaskBundles()
+		})
+	})
+
+	t.Run("should use the version of the ConfigMap annotation", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationWithTaskBundles(v1alpha1.TaskBundle{
+			Name:      "team",
+			ConfigMap: &v1alpha1.ConfigMapReference{Namespace: "toolchain-operator", Name: "team-tasks"},
+		})
+		cm := newTaskBundleConfigMap(map[string]string{"lint": lintClusterTask})
+		cm.Annotations = map[string]string{TaskBundleVersionAnnotationKey: "1.2.0"}
+		cl, r := configureClient(t, tektonInstallation, cm)
+
+		// when
+		err := r.ensureTaskBundles(log, tektonInstallation)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.0", assertTask(t, cl, clusterTaskKind, "", "lint").GetAnnotations()[TaskBundleVersionAnnotationKey])
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasTaskBundles(v1alpha1.TaskBundleStatus{Name: "team", Version: "1.2.0"})
+	})
+
+	t.Run("failures", func(t *testing.T) {
+
+		configMapRef := &v1alpha1.ConfigMapReference{Namespace: "toolchain-operator", Name: "team-tasks"}
+
+		for name, tc := range map[string]struct {
+			bundles     []v1alpha1.TaskBundle
+			objs        []*corev1.ConfigMap
+			expectedErr string
+		}{
+			"unknown embedded bundle": {
+				bundles:     []v1alpha1.TaskBundle{{Name: "standard", Embedded: "unknown"}},
+				expectedErr: "unknown embedded task bundle 'unknown'",
+			},
+			"embedded bundle and ConfigMap": {
+				bundles:     []v1alpha1.TaskBundle{{Name: "standard", Embedded: DefaultTaskBundleName, ConfigMap: configMapRef}},
+				expectedErr: "only one of 'embedded' and 'configMap' must be set",
+			},
+			"no source": {
+				bundles:     []v1alpha1.TaskBundle{{Name: "standard"}},
+				expectedErr: "one of 'embedded' and 'configMap' must be set",
+			},
+			"missing ConfigMap": {
+				bundles:     []v1alpha1.TaskBundle{{Name: "team", ConfigMap: configMapRef}},
+				expectedErr: `unable to get the ConfigMap toolchain-operator/team-tasks: configmaps "team-tasks" not found`,
+			},
+			"unsupported kind": {
+				bundles:     []v1alpha1.TaskBundle{{Name: "team", ConfigMap: configMapRef}},
+				objs:        []*corev1.ConfigMap{newTaskBundleConfigMap(map[string]string{"pod": "apiVersion: v1\nkind: Pod\nmetadata:\n  name: test\n"})},
+				expectedErr: "invalid manifest 'pod': only the Tasks and ClusterTasks of the tekton.dev group are supported but found 'Pod' of 'v1'",
+			},
+			"ClusterTask with a namespace": {
+				bundles:     []v1alpha1.TaskBundle{{Name: "team", ConfigMap: configMapRef}},
+				objs:        []*corev1.ConfigMap{newTaskBundleConfigMap(map[string]string{"lint": "apiVersion: tekton.dev/v1beta1\nkind: ClusterTask\nmetadata:\n  name: lint\n  namespace: ci\n"})},
+				expectedErr: "invalid manifest 'lint': the ClusterTask lint must not have a namespace",
+			},
+			"task in two bundles": {
+				bundles:     []v1alpha1.TaskBundle{{Name: "team", ConfigMap: configMapRef}, {Name: "other", ConfigMap: configMapRef}},
+				objs:        []*corev1.ConfigMap{newTaskBundleConfigMap(map[string]string{"lint": lintClusterTask})},
+				expectedErr: "ClusterTask lint is defined in the bundles team and other",
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				// given
+				tektonInstallation := newInstallationWithTaskBundles(tc.bundles...)
+				cl, r := configureClient(t, tektonInstallation)
+				for _, obj := range tc.objs {
+					require.NoError(t, cl.Create(context.TODO(), obj))
+				}
+
+				// when
+				err := r.ensureTaskBundles(log, tektonInstallation)
+
+				// then
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+					HasConditions(TasksSyncFailed(tc.expectedErr))
+			})
+		}
+
+		t.Run("task not managed by the operator", func(t *testing.T) {
+			// given
+			tektonInstallation := newInstallationWithTaskBundles(v1alpha1.TaskBundle{Name: "standard", Embedded: DefaultTaskBundleName})
+			existing := &unstructured.Unstructured{}
+			existing.SetGroupVersionKind(tektonGroupVersion.WithKind(clusterTaskKind))
+			existing.SetName("toolchain-buildah")
+			cl, r := configureClient(t, tektonInstallation, existing)
+
+			// when
+			err := r.ensureTaskBundles(log, tektonInstallation)
+
+			// then
+			require.EqualError(t, err, "failed to apply the task bundle standard: ClusterTask toolchain-buildah already exists and is not managed by the operator")
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasConditions(TasksSyncFailed("ClusterTask toolchain-buildah already exists and is not managed by the operator"))
+		})
+	})
+}
+
+func TestReferencedConfigMaps(t *testing.T) {
+	// given
+	tektonInstallation := newInstallationWithTaskBundles(v1alpha1.TaskBundle{
+		Name:      "team",
+		ConfigMap: &v1alpha1.ConfigMapReference{Namespace: "toolchain-operator", Name: "team-tasks"},
+	}, v1alpha1.TaskBundle{Name: "standard", Embedded: DefaultTaskBundleName})
+
+	// when
+	refs := referencedConfigMaps(tektonInstallation)
+
+	// then
+	assert.Equal(t, []types.NamespacedName{{Namespace: "toolchain-operator", Name: "team-tasks"}}, refs)
+}
+
+func newInstallationWithTaskBundles(bundles ...v1alpha1.TaskBundle) *v1alpha1.TektonInstallation {
+	tektonInstallation := NewInstallation()
+	tektonInstallation.Spec.TaskBundles = bundles
+	return tektonInstallation
+}
+
+func newTaskBundleConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "toolchain-operator", Name: "team-tasks"},
+		Data:       data,
+	}
+}
+
+func assertTask(t *testing.T, cl client.Client, kind, ns, name string) *unstructured.Unstructured {
+	task := &unstructured.Unstructured{}
+	task.SetGroupVersionKind(tektonGroupVersion.WithKind(kind))
+	require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: name}, task))
+	return task
+}
+
+func assertNoTask(t *testing.T, cl client.Client, kind, ns, name string) {
+	task := &unstructured.Unstructured{}
+	task.SetGroupVersionKind(tektonGroupVersion.WithKind(kind))
+	err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: name}, task)
+	assert.True(t, apierrors.IsNotFound(err), "%s %s/%s found", kind, ns, name)
+}
@@ -3,23 +3,26 @@ package tektoninstallation
 import (
 	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/catalogsource"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
 	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	// InstallationName the name of the TektonInstallation resource (cluster-scoped)
 	InstallationName = "toolchain-tekton-installation"
-	// SubscriptionNamespace the namespace of the TekTon Subscription resource
-	SubscriptionNamespace = "openshift-operators"
 	// SubscriptionName the name for of TekTon Subscription resource
 	SubscriptionName = "openshift-pipelines-operator-rh"
-	// StartingCSV keeps the CSV version the installation should start with
-	StartingCSV = "openshift-pipelines-operator.v1.0.1"
 	// TektonConfigName the name of the TektonConfig resource
 	TektonConfigName = "cluster"
+	// PipelineServiceAccountName the name of the ServiceAccount used by the pipelines
+	PipelineServiceAccountName = "pipeline"
+	// PipelineRoleBindingName the name of the RoleBinding which grants the pipeline ServiceAccount the edit role
+	PipelineRoleBindingName = "pipeline-edit"
 )
 
 // NewInstallation returns a new TektonInstallation resource
@@ -31,8 +34,8 @@ func NewInstallation() *v1alpha1.TektonInstallation {
 	}
 }
 
-// NewSubscription for openshift-pipeline operator
-func NewSubscription(ns string) *olmv1alpha1.Subscription {
+// NewSubscription for openshift-pipeline operator, with the configured channel and starting CSV
+func NewSubscription(config *configuration.Config, ns string) *olmv1alpha1.Subscription {
 	return &olmv1alpha1.Subscription{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      SubscriptionName,
@@ -40,15 +43,71 @@ func NewSubscription(ns string) *olmv1alpha1.Subscription {
 			Labels:    toolchain.Labels(),
 		},
 		Spec: &olmv1alpha1.SubscriptionSpec{
-			Channel:                "ocp-4.4",
+			Channel:                config.GetTektonChannel(),
 			Package:                SubscriptionName,
-			StartingCSV:            StartingCSV,
-			CatalogSource:          "redhat-operators",
-			CatalogSourceNamespace: "openshift-marketplace",
+			StartingCSV:            config.GetTektonStartingCSV(),
+			CatalogSource:          catalogsource.DefaultName,
+			CatalogSourceNamespace: catalogsource.DefaultNamespace,
 		},
 	}
 }
 
+// ManagedCatalogSourceName returns the name of the CatalogSource which is managed by the operator for the given TektonInstallation
+func ManagedCatalogSourceName(tektonInstallation *v1alpha1.TektonInstallation) string {
+	return "pipelines-" + tektonInstallation.Name
+}
+
+// NewPipelineServiceAccount returns a new pipeline ServiceAccount in the given namespace, which references the given secrets
+func NewPipelineServiceAccount(tektonInstallation *v1alpha1.TektonInstallation, ns string, secrets ...string) *corev1.ServiceAccount {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PipelineServiceAccountName,
+			Namespace: ns,
+			Labels:    toolchain.LabelsWithOwner(tektonInstallation.Name),
+		},
+	}
+	for _, secret := range secrets {
+		sa.Secrets = append(sa.Secrets, corev1.ObjectReference{Name: secret})
+	}
+	return sa
+}
+
+// NewPipelineRoleBinding returns a new RoleBinding which grants the edit role to the pipeline ServiceAccount of the given namespace
+func NewPipelineRoleBinding(tektonInstallation *v1alpha1.TektonInstallation, ns string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PipelineRoleBindingName,
+			Namespace: ns,
+			Labels:    toolchain.LabelsWithOwner(tektonInstallation.Name),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "edit",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      PipelineServiceAccountName,
+				Namespace: ns,
+			},
+		},
+	}
+}
+
+// NewPipelineSecret returns a copy of the given secret in the given namespace
+func NewPipelineSecret(tektonInstallation *v1alpha1.TektonInstallation, ns string, source *corev1.Secret) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      source.Name,
+			Namespace: ns,
+			Labels:    toolchain.LabelsWithOwner(tektonInstallation.Name),
+		},
+		Type: source.Type,
+		Data: source.Data,
+	}
+}
+
 // InstallationSucceeded returns a status condition for the case where the Tekton installation succeeded
 func InstallationSucceeded() toolchainv1alpha1.Condition {
 	return toolchainv1alpha1.Condition{
@@ -78,11 +137,70 @@ func InstallationFailed(message string) toolchainv1alpha1.Condition {
 	}
 }
 
+// Conflict returns a status condition for the case where OpenShift Pipelines is already installed by another TektonInstallation
+func Conflict(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.TektonReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1alpha1.ConflictReason,
+		Message: message,
+	}
+}
+
+// CatalogSourceNotReady returns a status condition for the case where the mirror CatalogSource of the TektonInstallation is not ready
+func CatalogSourceNotReady(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.TektonReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1alpha1.CatalogSourceNotReadyReason,
+		Message: message,
+	}
+}
+
 // Unknown returns a status condition for the case where the Tekton installation status is unknown
-func Unknown() toolchainv1alpha1.Condition {
+func Unknown(message string) toolchainv1alpha1.Condition {
 	return toolchainv1alpha1.Condition{
-		Type:   v1alpha1.TektonReady,
-		Status: corev1.ConditionFalse,
-		Reason: v1alpha1.UnknownReason,
+		Type:    v1alpha1.TektonReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1alpha1.UnknownReason,
+		Message: message,
+	}
+}
+
+// PipelineNamespacesProvisioned returns a status condition for the case where the pipeline namespaces were provisioned
+func PipelineNamespacesProvisioned() toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:   v1alpha1.PipelineNamespacesReady,
+		Status: corev1.ConditionTrue,
+		Reason: v1alpha1.ProvisionedReason,
+	}
+}
+
+// PipelineNamespacesProvisioningFailed returns a status condition for the case where the pipeline namespaces could not be provisioned
+func PipelineNamespacesProvisioningFailed(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.PipelineNamespacesReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1alpha1.FailedToProvisionReason,
+		Message: message,
+	}
+}
+
+// TasksSynced returns a status condition for the case where the task bundles were applied
+func TasksSynced() toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:   v1alpha1.TasksReady,
+		Status: corev1.ConditionTrue,
+		Reason: v1alpha1.SyncedReason,
+	}
+}
+
+// TasksSyncFailed returns a status condition for the case where the task bundles could not be applied
+func TasksSyncFailed(message string) toolchainv1alpha1.Condition {
+	return toolchainv1alpha1.Condition{
+		Type:    v1alpha1.TasksReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1alpha1.FailedToSyncReason,
+		Message: message,
 	}
 }
@@ -0,0 +1,84 @@
+package tektoninstallation
+
+import (
+	"context"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+
+	"github.com/go-logr/logr"
+	config "github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FieldManager the name of the manager of the fields that the operator sets on the TektonConfig
+const FieldManager = "toolchain-operator"
+
+// NewTektonConfigParameters returns the TektonConfig to apply with the given parameters. Only the fields of the parameters
+// which are set are part of the returned object, so that the operator owns none of the other fields of the TektonConfig.
+func NewTektonConfigParameters(params *v1alpha1.TektonConfigParameters) *unstructured.Unstructured {
+	tektonCfg := &unstructured.Unstructured{}
+	tektonCfg.SetGroupVersionKind(config.SchemeGroupVersion.WithKind("Config"))
+	tektonCfg.SetName(TektonConfigName)
+	if params == nil {
+		return tektonCfg
+	}
+	spec := map[string]interface{}{}
+	if params.TargetNamespace != "" {
+		spec["targetNamespace"] = params.TargetNamespace
+	}
+	if params.Pruner != nil {
+		pruner := map[string]interface{}{}
+		if len(params.Pruner.Resources) > 0 {
+			resources := make([]interface{}, 0, len(params.Pruner.Resources))
+			for _, resource := range params.Pruner.Resources {
+				resources = append(resources, resource)
+			}
+			pruner["resources"] = resources
+		}
+		if params.Pruner.Keep != nil {
+			pruner["keep"] = int64(*params.Pruner.Keep)
+		}
+		if params.Pruner.Schedule != "" {
+			pruner["schedule"] = params.Pruner.Schedule
+		}
+		spec["pruner"] = pruner
+	}
+	if len(params.AddonParams) > 0 {
+		addonParams := make([]interface{}, 0, len(params.AddonParams))
+		for _, param := range params.AddonParams {
+			addonParams = append(addonParams, map[string]interface{}{
+				"name":  param.Name,
+				"value": param.Value,
+			})
+		}
+		spec["addon"] = map[string]interface{}{
+			"params": addonParams,
+		}
+	}
+	if len(spec) > 0 {
+		tektonCfg.Object["spec"] = spec
+	}
+	return tektonCfg
+}
+
+// ensureTektonConfigParameters applies the parameters of the TektonInstallation to the TektonConfig with a server-side apply.
+// The fields which were set by the operator but which are not part of the parameters anymore are thus removed by the server.
+func (r *ReconcileTektonInstallation) ensureTektonConfigParameters(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation, tektonCfg *config.Config) error {
+	if tektonInstallation.Spec.Config == nil && !hasFieldManager(tektonCfg) {
+		// nothing to apply and nothing to remove
+		return nil
+	}
+	logger.Info("Applying the parameters to the TektonConfig", "TektonConfig.Name", tektonCfg.Name)
+	return r.client.Patch(context.TODO(), NewTektonConfigParameters(tektonInstallation.Spec.Config), client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// hasFieldManager returns true if the operator manages some fields of the given TektonConfig
+func hasFieldManager(tektonCfg *config.Config) bool {
+	for _, managedFields := range tektonCfg.ManagedFields {
+		if managedFields.Manager == FieldManager {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package tektoninstallation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	. "github.com/codeready-toolchain/toolchain-operator/test/assert"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	config "github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestNewTektonConfigParameters(t *testing.T) {
+
+	t.Run("all parameters", func(t *testing.T) {
+		// given
+		keep := int32(5)
+		params := &v1alpha1.TektonConfigParameters{
+			TargetNamespace: "openshift-pipelines",
+			Pruner: &v1alpha1.TektonPruner{
+				Resources: []string{"taskrun", "pipelinerun"},
+				Keep:      &keep,
+				Schedule:  "0 8 * * *",
+			},
+			AddonParams: []v1alpha1.TektonParam{{Name: "clusterTasks", Value: "true"}, {Name: "pipelineTemplates", Value: "false"}},
+		}
+
+		// when
+		tektonCfg := NewTektonConfigParameters(params)
+
+		// then
+		assert.Equal(t, config.SchemeGroupVersion.WithKind("Config"), tektonCfg.GroupVersionKind())
+		assert.Equal(t, TektonConfigName, tektonCfg.GetName())
+		assert.Equal(t, map[string]interface{}{
+			"targetNamespace": "openshift-pipelines",
+			"pruner": map[string]interface{}{
+				"resources": []interface{}{"taskrun", "pipelinerun"},
+				"keep":      int64(5),
+				"schedule":  "0 8 * * *",
+			},
+			"addon": map[string]interface{}{
+				"params": []interface{}{
+					map[string]interface{}{"name": "clusterTasks", "value": "true"},
+					map[string]interface{}{"name": "pipelineTemplates", "value": "false"},
+				},
+			},
+		}, tektonCfg.Object["spec"])
+	})
+
+	t.Run("only the fields which are set", func(t *testing.T) {
+		// when
+		tektonCfg := NewTektonConfigParameters(&v1alpha1.TektonConfigParameters{
+			Pruner: &v1alpha1.TektonPruner{Schedule: "0 8 * * *"},
+		})
+
+		// then
+		assert.Equal(t, map[string]interface{}{
+			"pruner": map[string]interface{}{
+				"schedule": "0 8 * * *",
+			},
+		}, tektonCfg.Object["spec"])
+	})
+
+	t.Run("no parameters", func(t *testing.T) {
+		// when
+		tektonCfg := NewTektonConfigParameters(nil)
+
+		// then
+		assert.NotContains(t, tektonCfg.Object, "spec")
+	})
+}
+
+func TestEnsureTektonConfigParameters(t *testing.T) {
+
+	t.Run("should apply the parameters with the operator as field manager", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		keep := int32(10)
+		tektonInstallation.Spec.Config = &v1alpha1.TektonConfigParameters{
+			TargetNamespace: "openshift-pipelines",
+			Pruner:          &v1alpha1.TektonPruner{Resources: []string{"pipelinerun"}, Keep: &keep},
+			AddonParams:     []v1alpha1.TektonParam{{Name: "pipelineTemplates", Value: "true"}},
+		}
+		tektonConfig := newTektonConfig(config.InstalledStatus)
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()), tektonConfig)
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+		var patches []appliedPatch
+		cl.MockPatch = recordPatches(cl.Client, &patches)
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, patches, 1)
+		assert.Equal(t, types.ApplyPatchType, patches[0].patchType)
+		assert.Equal(t, FieldManager, patches[0].options.FieldManager)
+		require.NotNil(t, patches[0].options.Force)
+		assert.True(t, *patches[0].options.Force)
+		assert.JSONEq(t, `{"apiVersion":"operator.tekton.dev/v1alpha1","kind":"Config","metadata":{"name":"cluster"},"spec":{"targetNamespace":"openshift-pipelines",`+
+			`"pruner":{"resources":["pipelinerun"],"keep":10},"addon":{"params":[{"name":"pipelineTemplates","value":"true"}]}}}`, string(patches[0].data))
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions(InstallationSucceeded())
+	})
+
+	t.Run("should release the fields when the parameters are removed", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonConfig := newTektonConfig(config.InstalledStatus)
+		tektonConfig.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply}}
+		cl, r := configureClient(t, tektonInstallation, tektonConfig)
+		var patches []appliedPatch
+		cl.MockPatch = recordPatches(cl.Client, &patches)
+
+		// when
+		err := r.ensureTektonConfigParameters(log, tektonInstallation, tektonConfig)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, patches, 1)
+		assert.JSONEq(t, `{"apiVersion":"operator.tekton.dev/v1alpha1","kind":"Config","metadata":{"name":"cluster"}}`, string(patches[0].data))
+	})
+
+	t.Run("should not apply anything when there are no parameters", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonConfig := newTektonConfig(config.InstalledStatus)
+		cl, r := configureClient(t, tektonInstallation, tektonConfig)
+		var patches []appliedPatch
+		cl.MockPatch = recordPatches(cl.Client, &patches)
+
+		// when
+		err := r.ensureTektonConfigParameters(log, tektonInstallation, tektonConfig)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, patches)
+	})
+
+	t.Run("should fail when the parameters cannot be applied", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonInstallation.Spec.Config = &v1alpha1.TektonConfigParameters{TargetNamespace: "openshift-pipelines"}
+		tektonConfig := newTektonConfig(config.InstalledStatus)
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()), tektonConfig)
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+		cl.MockPatch = func(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+			return errors.New("unable to apply")
+		}
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.EqualError(t, err, "failed to apply the parameters to the TektonConfig: unable to apply")
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions(InstallationFailed("unable to apply"))
+	})
+}
+
+type appliedPatch struct {
+	patchType types.PatchType
+	data      []byte
+	options   client.PatchOptions
+}
+
+// recordPatches returns a mock of the Patch func which records the server-side apply patches, since they are not supported
+// by the fake client. The other patches are delegated to the given client.
+func recordPatches(cl client.Client, patches *[]appliedPatch) func(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return func(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+		if patch.Type() != types.ApplyPatchType {
+			return cl.Patch(ctx, obj, patch, opts...)
+		}
+		data, err := patch.Data(obj)
+		if err != nil {
+			return err
+		}
+		options := client.PatchOptions{}
+		options.ApplyOptions(opts)
+		*patches = append(*patches, appliedPatch{patchType: patch.Type(), data: data, options: options})
+		return nil
+	}
+}
@@ -2,6 +2,7 @@ package tektoninstallation
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -9,18 +10,30 @@ import (
 	"github.com/codeready-toolchain/toolchain-common/pkg/condition"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
 	toolchainv1alpha1 "github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/cabundle"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/catalogsource"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/health"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/proxy"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/references"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/subscription"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
 
 	"github.com/go-logr/logr"
 	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	errs "github.com/pkg/errors"
 	config "github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -33,20 +46,22 @@ var log = logf.Log.WithName("controller_tektoninstallation")
 
 // Add creates a new TektonInstallation Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
-func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+func Add(mgr manager.Manager, operatorConfig *configuration.Config) error {
+	return add(mgr, newReconciler(mgr, operatorConfig))
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) *ReconcileTektonInstallation {
+func newReconciler(mgr manager.Manager, operatorConfig *configuration.Config) *ReconcileTektonInstallation {
 	log.Info("Adding new TektonInstallation reconciler")
-	return &ReconcileTektonInstallation{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	// the Secrets and ConfigMaps are read from the API server, since they are not in the cache of the manager
+	cl := references.NewClient(mgr.GetClient(), mgr.GetAPIReader(), &corev1.Secret{}, &corev1.SecretList{}, &corev1.ConfigMap{}, &corev1.ConfigMapList{})
+	return &ReconcileTektonInstallation{client: cl, scheme: mgr.GetScheme(), config: operatorConfig}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r *ReconcileTektonInstallation) error {
 	// Create a new controller
-	c, err := controller.New("tektoninstallation-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("tektoninstallation-controller", mgr, controller.Options{Reconciler: health.DefaultStatus.TrackReconciles("tektoninstallation-controller", r)})
 	if err != nil {
 		return err
 	}
@@ -57,6 +72,16 @@ func add(mgr manager.Manager, r *ReconcileTektonInstallation) error {
 		return err
 	}
 
+	// the other TektonInstallations are reconciled when a TektonInstallation is deleted, since one of them may become the active one
+	log.Info("configuring watcher on the deletion of TektonInstallations")
+	if err := c.Watch(&source.Kind{Type: &v1alpha1.TektonInstallation{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: mapToOtherInstallations(mgr.GetClient())}, predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}); err != nil {
+		return err
+	}
+
 	// Watch for changes to secondary resource
 	log.Info("configuring watcher on Tekton Subscriptions")
 	enqueueRequestForOwner := &handler.EnqueueRequestForOwner{
@@ -68,8 +93,51 @@ func add(mgr manager.Manager, r *ReconcileTektonInstallation) error {
 		return err
 	}
 
+	log.Info("configuring watcher on the managed CatalogSources")
+	if err := c.Watch(&source.Kind{Type: &olmv1alpha1.CatalogSource{}}, enqueueRequestForOwner); err != nil {
+		return err
+	}
+
+	log.Info("configuring watchers on the pipeline namespaces")
+	// the namespaces which start or stop matching the selector are provisioned or cleaned up
+	if err := c.Watch(&source.Kind{Type: &corev1.Namespace{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: mapToActiveInstallation(mgr.GetClient())}); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &corev1.ServiceAccount{}}, enqueueRequestForOwner); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &rbacv1.RoleBinding{}}, enqueueRequestForOwner); err != nil {
+		return err
+	}
+	// the Secrets and ConfigMaps are only watched in the namespaces of the objects referenced by the active TektonInstallation, in
+	// the pipeline namespaces and in the target namespace, instead of caching all the Secrets and ConfigMaps of the cluster
+	secrets, err := references.NewWatcher(mgr, c, &corev1.Secret{}, &toolchainv1alpha1.TektonInstallation{})
+	if err != nil {
+		return err
+	}
+
+	log.Info("configuring watchers on the ConfigMaps of the task bundles and of the trusted CA bundle")
+	configMaps, err := references.NewWatcher(mgr, c, &corev1.ConfigMap{}, &toolchainv1alpha1.TektonInstallation{})
+	if err != nil {
+		return err
+	}
+	r.watchReferences = func(tektonInstallation *toolchainv1alpha1.TektonInstallation, pipelineNamespaces ...string) error {
+		// the namespaces which are not given anymore are not watched anymore, hence the target namespace is always given
+		namespaces := append([]string{tektonInstallation.Status.TargetNamespace}, pipelineNamespaces...)
+		if err := secrets.Watch(tektonInstallation.Name, namespaces, referencedSecrets(tektonInstallation)...); err != nil {
+			return err
+		}
+		return configMaps.Watch(tektonInstallation.Name, namespaces, referencedConfigMaps(tektonInstallation)...)
+	}
+
+	log.Info("configuring watcher on the cluster Proxy")
+	if err := proxy.Watch(mgr, c, &handler.EnqueueRequestsFromMapFunc{ToRequests: mapToActiveInstallation(mgr.GetClient())}); err != nil {
+		return err
+	}
+
 	r.watchTektonConfig = func() error {
-		return c.Watch(&source.Kind{Type: &config.Config{}}, &handler.EnqueueRequestForObject{})
+		// the TektonConfig is created by the OpenShift Pipelines operator, so it has no owner reference to the TektonInstallation
+		return c.Watch(&source.Kind{Type: &config.Config{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: mapToActiveInstallation(mgr.GetClient())})
 	}
 
 	log.Info("TektonInstallation reconciler successfully added")
@@ -85,8 +153,13 @@ type ReconcileTektonInstallation struct {
 	// that reads objects from the cache and writes to the apiserver
 	client            client.Client
 	scheme            *runtime.Scheme
+	config            *configuration.Config
 	watchTektonConfig func() error
-	mu                sync.Mutex
+	// watchReferences watches the Secrets and ConfigMaps referenced by the given TektonInstallation, its target namespace and the given
+	// pipeline namespaces
+	// of the objects that it owns
+	watchReferences func(tektonInstallation *toolchainv1alpha1.TektonInstallation, namespaces ...string) error
+	mu              sync.Mutex
 }
 
 // Reconcile reads that state of the config for a TektonInstallation object and makes changes based on the state read
@@ -96,25 +169,53 @@ func (r *ReconcileTektonInstallation) Reconcile(request reconcile.Request) (reco
 	reqLogger.Info("Reconciling TektonInstallation")
 	// Fetch the TektonInstallation instance
 	tektonInstallation := &toolchainv1alpha1.TektonInstallation{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: InstallationName}, tektonInstallation); err != nil {
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: request.Name}, tektonInstallation); err != nil {
 		if errors.IsNotFound(err) {
 			reqLogger.Info("TektonInstallation not found")
-			return reconcile.Result{}, nil
+			// forget the objects referenced by the deleted TektonInstallation
+			deleted := &toolchainv1alpha1.TektonInstallation{ObjectMeta: metav1.ObjectMeta{Name: request.Name}}
+			return reconcile.Result{}, r.watchReferences(deleted)
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
-	if created, err := r.ensureTektonSubscription(reqLogger, tektonInstallation, SubscriptionNamespace); err != nil {
-		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonSubscriptionFailed, err, "failed to create tekton subscription in namespace %s", SubscriptionNamespace)
+	if active, err := getActiveInstallation(r.client); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationFailed, err, "failed to get the active TektonInstallation")
+	} else if active != nil && active.Name != tektonInstallation.Name {
+		reqLogger.Info("OpenShift Pipelines is already installed by another TektonInstallation", "active", active.Name)
+		// no need to requeue, this TektonInstallation is reconciled when the other TektonInstallation is deleted
+		return reconcile.Result{},
+			r.statusUpdate(reqLogger, tektonInstallation, r.setStatusTektonConflict, fmt.Sprintf("OpenShift Pipelines is already installed by the TektonInstallation '%s'", active.Name))
+	}
+
+	if err := r.watchReferences(tektonInstallation, tektonInstallation.Status.PipelineNamespaces...); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationFailed, err, "failed to watch the Secrets and ConfigMaps referenced by the TektonInstallation")
+	}
+
+	subscriptionNamespace := r.config.GetTektonSubscriptionNamespace()
+	if err := catalogsource.Ensure(reqLogger, r.client, r.scheme, tektonInstallation, ManagedCatalogSourceName(tektonInstallation), tektonInstallation.Spec.CatalogSource, tektonInstallation.Spec.CommonMetadata); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationFailed, err, "failed to create the CatalogSource of OpenShift Pipelines")
+	}
+
+	if notReady, err := r.checkCatalogSource(tektonInstallation, subscriptionNamespace); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationFailed, err, "failed to check the CatalogSource of OpenShift Pipelines")
+	} else if notReady != "" {
+		reqLogger.Info("CatalogSource is not ready", "message", notReady)
+		// requeue until the CatalogSource is ready
+		return reconcile.Result{Requeue: true, RequeueAfter: r.config.GetTektonRequeueAfter()}, r.statusUpdate(reqLogger, tektonInstallation, r.setStatusCatalogSourceNotReady, notReady)
+	}
+
+	if created, err := r.ensureTektonSubscription(reqLogger, tektonInstallation, subscriptionNamespace); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonSubscriptionFailed, err, "failed to create tekton subscription in namespace %s", subscriptionNamespace)
 	} else if created {
-		return reconcile.Result{}, r.statusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstalling, "created tekton subscription")
+		return reconcile.Result{RequeueAfter: r.remainingInstallTime(tektonInstallation)}, r.statusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstalling, "created tekton subscription")
 	}
 
 	if requeue, err := r.ensureWatchTektonConfig(); err != nil {
 		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationFailed, err, "failed to start watching TektonConfig CRD")
 	} else if requeue {
-		return reconcile.Result{Requeue: true, RequeueAfter: 3 * time.Second}, nil
+		return reconcile.Result{Requeue: true, RequeueAfter: r.config.GetTektonRequeueAfter()}, nil
 	}
 
 	tektonCfg := &config.Config{}
@@ -126,36 +227,111 @@ func (r *ReconcileTektonInstallation) Reconcile(request reconcile.Request) (reco
 		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationFailed, err, "failed to get TektonConfig")
 	}
 
+	if err := r.ensureTektonConfigParameters(reqLogger, tektonInstallation, tektonCfg); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationFailed, err, "failed to apply the parameters to the TektonConfig")
+	}
+
+	if err := r.updateVersions(reqLogger, tektonInstallation, tektonCfg, subscriptionNamespace); err != nil {
+		return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationFailed, err, "failed to get the versions of the Tekton components")
+	}
+
 	code, details := getTektonConfigStatus(tektonCfg)
 	switch code {
 	case config.InstalledStatus:
 		reqLogger.Info("done with Tekton installation")
-		return reconcile.Result{}, r.statusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationSucceeded, "")
-	case config.InstallingStatus:
-		return reconcile.Result{}, r.statusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstalling, details)
+		if err := r.statusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationSucceeded, ""); err != nil {
+			return reconcile.Result{}, err
+		}
+		if targetNamespace := tektonInstallation.Status.TargetNamespace; targetNamespace != "" {
+			if err := r.watchReferences(tektonInstallation, tektonInstallation.Status.PipelineNamespaces...); err != nil {
+				return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationFailed, err, "failed to watch the namespace %s", targetNamespace)
+			}
+			if err := cabundle.Ensure(reqLogger, r.client, r.scheme, tektonInstallation, targetNamespace, tektonInstallation.Spec.TrustedCABundle, tektonInstallation.Spec.CommonMetadata); err != nil {
+				return reconcile.Result{}, r.wrapErrorWithStatusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationFailed, err, "failed to provision the trusted CA bundle in namespace %s", targetNamespace)
+			}
+		}
+		if err := r.ensurePipelineNamespaces(reqLogger, tektonInstallation); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.ensureTaskBundles(reqLogger, tektonInstallation)
+	case config.InstallingStatus, deployingStatus, validatingStatus:
+		// requeue to fail the installation if it does not complete within the timeout
+		return reconcile.Result{RequeueAfter: r.remainingInstallTime(tektonInstallation)}, r.statusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstalling, details)
 	case config.ErrorStatus:
-		return reconcile.Result{}, r.statusUpdate(reqLogger, tektonInstallation, r.setStatusTektonInstallationFailed, details)
+		return reconcile.Result{}, r.statusUpdate(reqLogger, tektonInstallation, r.setStatusTektonConfigError, details)
 	default:
-		return reconcile.Result{}, r.statusUpdate(reqLogger, tektonInstallation, r.setStatusTektonUnknown, "")
+		// the TektonConfig may not have been updated yet by the OpenShift Pipelines operator
+		return reconcile.Result{RequeueAfter: r.remainingInstallTime(tektonInstallation)}, r.statusUpdate(reqLogger, tektonInstallation, r.setStatusTektonUnknown, details)
+	}
+}
+
+// checkCatalogSource checks that the managed or mirror CatalogSource of the given TektonInstallation is ready, unless the Subscription already uses it.
+// Returns a message which describes why the CatalogSource is not ready, or an empty string if there is nothing to wait for.
+func (r *ReconcileTektonInstallation) checkCatalogSource(tektonInstallation *v1alpha1.TektonInstallation, ns string) (string, error) {
+	catalogSource := getCatalogSource(tektonInstallation)
+	if catalogSource == nil {
+		return "", nil
+	}
+	sub := &olmv1alpha1.Subscription{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: SubscriptionName}, sub); err != nil {
+		if !errors.IsNotFound(err) {
+			return "", err
+		}
+	} else if !catalogsource.SetSubscriptionSource(sub, *catalogSource) {
+		return "", nil
 	}
+	return catalogsource.CheckReady(r.client, *catalogSource)
+}
+
+// getCatalogSource returns the CatalogSource from which the OpenShift Pipelines operator is installed: the CatalogSource managed
+// by the operator, or the mirror CatalogSource. Returns nil if the TektonInstallation has none of them.
+func getCatalogSource(tektonInstallation *v1alpha1.TektonInstallation) *v1alpha1.CatalogSourceReference {
+	if tektonInstallation.Spec.CatalogSource != nil {
+		return &v1alpha1.CatalogSourceReference{Namespace: catalogsource.DefaultNamespace, Name: ManagedCatalogSourceName(tektonInstallation)}
+	}
+	if tektonInstallation.Spec.AirGap == nil {
+		return nil
+	}
+	return tektonInstallation.Spec.AirGap.CatalogSource
 }
 
 func (r *ReconcileTektonInstallation) ensureTektonSubscription(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation, ns string) (bool, error) {
+	clusterProxy, err := proxy.GetClusterProxy(r.client)
+	if err != nil {
+		return false, err
+	}
+	catalogSource := catalogsource.OrDefault(getCatalogSource(tektonInstallation))
 	sub := &olmv1alpha1.Subscription{}
-	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: SubscriptionName}, sub)
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: SubscriptionName}, sub)
 	if err != nil && errors.IsNotFound(err) {
-		tektonSub := NewSubscription(ns)
+		tektonSub := NewSubscription(r.config, ns)
+		catalogsource.SetSubscriptionSource(tektonSub, catalogSource)
 		logger.Info("Creating subscription for tekton", "Subscription.Namespace", ns, "Subscription.Name", tektonSub.Name)
 		if err := controllerutil.SetControllerReference(tektonInstallation, tektonSub, r.scheme); err != nil {
 			return false, err
 		}
+		subscription.SetConfig(tektonSub, tektonInstallation.Spec.OperatorConfig, clusterProxy)
+		toolchain.SetCommonMetadata(tektonSub, tektonInstallation.Spec.CommonMetadata)
 		if err := r.client.Create(context.TODO(), tektonSub); err != nil {
 			return false, err
 		}
 		return true, nil
+	} else if err != nil {
+		return false, err
 	}
 
-	return false, err
+	changed := catalogsource.SetSubscriptionSource(sub, catalogSource)
+	if subscription.SetConfig(sub, tektonInstallation.Spec.OperatorConfig, clusterProxy) {
+		changed = true
+	}
+	if toolchain.SetCommonMetadata(sub, tektonInstallation.Spec.CommonMetadata) {
+		changed = true
+	}
+	if changed {
+		logger.Info("Updating the subscription for tekton", "Subscription.Namespace", ns, "Subscription.Name", sub.Name)
+		return false, r.client.Update(context.TODO(), sub)
+	}
+	return false, nil
 }
 
 func (r *ReconcileTektonInstallation) ensureWatchTektonConfig() (bool, error) {
@@ -184,30 +360,147 @@ func (r *ReconcileTektonInstallation) ensureWatchTektonConfig() (bool, error) {
 	return false, nil
 }
 
+// updateVersions updates the versions of Tekton Pipelines and of the OpenShift Pipelines operator, and the target namespace
+// of the Tekton components in the status of the TektonInstallation
+func (r *ReconcileTektonInstallation) updateVersions(logger logr.Logger, tektonInstallation *v1alpha1.TektonInstallation, tektonCfg *config.Config, subscriptionNamespace string) error {
+	operatorVersion, err := r.getOperatorVersion(subscriptionNamespace)
+	if err != nil {
+		return err
+	}
+	pipelinesVersion := getTektonPipelinesVersion(tektonCfg)
+	status := &tektonInstallation.Status
+	if status.PipelinesVersion == pipelinesVersion && status.OperatorVersion == operatorVersion && status.TargetNamespace == tektonCfg.Spec.TargetNamespace {
+		// Nothing changed
+		return nil
+	}
+	logger.Info("Updating the versions of the Tekton components", "PipelinesVersion", pipelinesVersion, "OperatorVersion", operatorVersion)
+	status.PipelinesVersion = pipelinesVersion
+	status.OperatorVersion = operatorVersion
+	status.TargetNamespace = tektonCfg.Spec.TargetNamespace
+	return r.client.Status().Update(context.TODO(), tektonInstallation)
+}
+
+// getOperatorVersion returns the version of the CSV installed by the Tekton subscription, or an empty string if there is none yet
+func (r *ReconcileTektonInstallation) getOperatorVersion(subscriptionNamespace string) (string, error) {
+	sub := &olmv1alpha1.Subscription{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: subscriptionNamespace, Name: SubscriptionName}, sub); err != nil {
+		return "", err
+	}
+	if sub.Status.InstalledCSV == "" {
+		return "", nil
+	}
+	csv := &olmv1alpha1.ClusterServiceVersion{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: subscriptionNamespace, Name: sub.Status.InstalledCSV}, csv); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return csv.Spec.Version.String(), nil
+}
+
+// getTektonPipelinesVersion returns the version of the most recent installation of Tekton Pipelines
+func getTektonPipelinesVersion(tektonCfg *config.Config) string {
+	// the conditions are sorted in reverse chronological order
+	for _, condition := range tektonCfg.Status.Conditions {
+		if condition.Code == config.InstalledStatus {
+			return condition.Version
+		}
+	}
+	return ""
+}
+
+// codes of the TektonConfig conditions which are not declared in the API of the Tekton operator, but which are set
+// by some versions of the OpenShift Pipelines operator while the installation is in progress
+const (
+	deployingStatus  config.InstallStatus = "deploying"
+	validatingStatus config.InstallStatus = "validating"
+	unknownStatus    config.InstallStatus = "unknown"
+)
+
+// getTektonConfigStatus returns the code and the details of the most recent TektonConfig condition with a known code.
+// If there is no such condition, then the code is `unknown` and the details describe the most recent condition, if any.
 func getTektonConfigStatus(tektonCfg *config.Config) (config.InstallStatus, string) {
-	for _, conditions := range tektonCfg.Status.Conditions {
-		code := conditions.Code
-		if code == config.InstalledStatus || code == config.InstallingStatus || code == config.ErrorStatus {
-			return code, conditions.Details
+	for _, condition := range tektonCfg.Status.Conditions {
+		switch condition.Code {
+		case config.InstalledStatus, config.ErrorStatus:
+			return condition.Code, condition.Details
+		case config.InstallingStatus, deployingStatus, validatingStatus:
+			if condition.Details == "" {
+				return condition.Code, fmt.Sprintf("TektonConfig is %s", condition.Code)
+			}
+			return condition.Code, condition.Details
 		}
 	}
-	return "unknown", ""
+	if len(tektonCfg.Status.Conditions) == 0 {
+		return unknownStatus, "TektonConfig has no status yet"
+	}
+	return unknownStatus, fmt.Sprintf("unknown status of TektonConfig: '%s'", tektonCfg.Status.Conditions[0].Code)
+}
+
+// remainingInstallTime returns the time left before the installation of OpenShift Pipelines times out, or 0 if it already timed out
+func (r *ReconcileTektonInstallation) remainingInstallTime(tektonInstallation *v1alpha1.TektonInstallation) time.Duration {
+	if tektonInstallation.Status.InstallStartTime == nil {
+		return r.config.GetTektonInstallTimeout()
+	}
+	if remaining := r.config.GetTektonInstallTimeout() - time.Since(tektonInstallation.Status.InstallStartTime.Time); remaining > 0 {
+		return remaining
+	}
+	return 0
 }
 
 func (r *ReconcileTektonInstallation) setStatusTektonInstallationSucceeded(tektonInstallation *v1alpha1.TektonInstallation, _ string) error {
-	return r.updateStatusConditions(tektonInstallation, InstallationSucceeded())
+	return r.updateStatus(tektonInstallation, resetInstallStartTime(tektonInstallation), InstallationSucceeded())
 }
 
 func (r *ReconcileTektonInstallation) setStatusTektonInstalling(tektonInstallation *v1alpha1.TektonInstallation, message string) error {
-	return r.updateStatusConditions(tektonInstallation, Installing(message))
+	return r.setStatusTektonInProgress(tektonInstallation, Installing(message))
 }
 
-func (r *ReconcileTektonInstallation) setStatusTektonInstallationFailed(tektonInstallation *v1alpha1.TektonInstallation, message string) error {
-	return r.updateStatusConditions(tektonInstallation, InstallationFailed(message))
+func (r *ReconcileTektonInstallation) setStatusTektonUnknown(tektonInstallation *v1alpha1.TektonInstallation, message string) error {
+	return r.setStatusTektonInProgress(tektonInstallation, Unknown(message))
+}
+
+// setStatusTektonInProgress sets the given condition of an installation in progress, unless the installation did not complete
+// within the configured timeout, in which case the installation is considered as failed
+func (r *ReconcileTektonInstallation) setStatusTektonInProgress(tektonInstallation *v1alpha1.TektonInstallation, inProgress toolchainapiv1alpha1.Condition) error {
+	started := tektonInstallation.Status.InstallStartTime == nil
+	if started {
+		now := metav1.Now()
+		tektonInstallation.Status.InstallStartTime = &now
+	}
+	if r.remainingInstallTime(tektonInstallation) <= 0 {
+		inProgress = InstallationFailed(fmt.Sprintf("the installation did not complete within %s: %s", r.config.GetTektonInstallTimeout(), inProgress.Message))
+	}
+	return r.updateStatus(tektonInstallation, started, inProgress)
 }
 
-func (r *ReconcileTektonInstallation) setStatusTektonUnknown(tektonInstallation *v1alpha1.TektonInstallation, _ string) error {
-	return r.updateStatusConditions(tektonInstallation, Unknown())
+// setStatusTektonConfigError sets the failed condition when the OpenShift Pipelines operator reported an error in the TektonConfig
+func (r *ReconcileTektonInstallation) setStatusTektonConfigError(tektonInstallation *v1alpha1.TektonInstallation, message string) error {
+	return r.updateStatus(tektonInstallation, resetInstallStartTime(tektonInstallation), InstallationFailed(message))
+}
+
+// setStatusCatalogSourceNotReady sets the failed condition when the mirror CatalogSource is not ready
+func (r *ReconcileTektonInstallation) setStatusCatalogSourceNotReady(tektonInstallation *v1alpha1.TektonInstallation, message string) error {
+	return r.updateStatusConditions(tektonInstallation, CatalogSourceNotReady(message))
+}
+
+// setStatusTektonConflict sets the failed condition when OpenShift Pipelines is already installed by another TektonInstallation
+func (r *ReconcileTektonInstallation) setStatusTektonConflict(tektonInstallation *v1alpha1.TektonInstallation, message string) error {
+	return r.updateStatusConditions(tektonInstallation, Conflict(message))
+}
+
+// resetInstallStartTime resets the start time of the installation and returns true if it was set
+func resetInstallStartTime(tektonInstallation *v1alpha1.TektonInstallation) bool {
+	if tektonInstallation.Status.InstallStartTime == nil {
+		return false
+	}
+	tektonInstallation.Status.InstallStartTime = nil
+	return true
+}
+
+func (r *ReconcileTektonInstallation) setStatusTektonInstallationFailed(tektonInstallation *v1alpha1.TektonInstallation, message string) error {
+	return r.updateStatusConditions(tektonInstallation, InstallationFailed(message))
 }
 
 func (r *ReconcileTektonInstallation) setStatusTektonSubscriptionFailed(tektonInstallation *v1alpha1.TektonInstallation, message string) error {
@@ -223,9 +516,14 @@ func (r *ReconcileTektonInstallation) statusUpdate(logger logr.Logger, tektonIns
 }
 
 func (r *ReconcileTektonInstallation) updateStatusConditions(tektonInstallation *v1alpha1.TektonInstallation, newConditions ...toolchainapiv1alpha1.Condition) error {
+	return r.updateStatus(tektonInstallation, false, newConditions...)
+}
+
+// updateStatus updates the status of the given TektonInstallation if the conditions changed, or if the other fields of the status changed
+func (r *ReconcileTektonInstallation) updateStatus(tektonInstallation *v1alpha1.TektonInstallation, changed bool, newConditions ...toolchainapiv1alpha1.Condition) error {
 	var updated bool
 	tektonInstallation.Status.Conditions, updated = condition.AddOrUpdateStatusConditions(tektonInstallation.Status.Conditions, newConditions...)
-	if !updated {
+	if !updated && !changed {
 		// Nothing changed
 		return nil
 	}
@@ -2,17 +2,22 @@ package tektoninstallation
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
 	"time"
 
+	toolchainapiv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/codeready-toolchain/toolchain-operator/pkg/apis"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/cabundle"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/proxy"
 	"github.com/codeready-toolchain/toolchain-operator/test"
 	. "github.com/codeready-toolchain/toolchain-operator/test/assert"
 
@@ -20,6 +25,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	config "github.com/tektoncd/operator/pkg/apis/operator/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -33,7 +41,7 @@ func TestTektonInstallationController(t *testing.T) {
 
 	t.Run("should reconcile with tekton installation", func(t *testing.T) {
 		// given
-		tektonSub := NewSubscription(SubscriptionNamespace)
+		tektonSub := NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace())
 		tektonInstallation := NewInstallation()
 		tektonConfig := newTektonConfig("applied-addons", "validated-pipeline")
 		cl, r := configureClient(t, tektonInstallation, tektonConfig)
@@ -54,24 +62,47 @@ func TestTektonInstallationController(t *testing.T) {
 				HasSpec(tektonSub.Spec)
 		})
 
-		t.Run("should not requeue", func(t *testing.T) {
+		t.Run("should requeue until the installation timeout when the status is unknown", func(t *testing.T) {
 			// when
 			result, err := r.Reconcile(request)
 
 			// then
 			require.NoError(t, err)
 
-			assert.False(t, result.Requeue)
+			assert.True(t, result.RequeueAfter > 0)
+			assert.True(t, result.RequeueAfter <= cfg.GetTektonInstallTimeout())
 			AssertThatSubscription(t, tektonSub.Namespace, tektonSub.Name, cl).
 				Exists().
 				HasSpec(tektonSub.Spec)
 
 			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
-				HasConditions(Unknown())
+				HasConditions(Unknown("unknown status of TektonConfig: 'applied-addons'"))
 		})
 
 	})
 
+	t.Run("should forget the references of a deleted installation", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonInstallation.Spec.TrustedCABundle = &v1alpha1.TrustedCABundle{ConfigMap: &v1alpha1.ConfigMapReference{Namespace: "config", Name: "custom-ca"}}
+		_, r := configureClient(t)
+		var watched []*v1alpha1.TektonInstallation
+		r.watchReferences = func(tektonInstallation *v1alpha1.TektonInstallation, _ ...string) error {
+			watched = append(watched, tektonInstallation)
+			return nil
+		}
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, watched, 1)
+		assert.Equal(t, tektonInstallation.Name, watched[0].Name)
+		assert.Empty(t, referencedSecrets(watched[0]))
+		assert.Empty(t, referencedConfigMaps(watched[0]))
+	})
+
 	// reconciling on tektonconfig resource watcher
 	t.Run("tektonconfig watcher", func(t *testing.T) {
 
@@ -80,7 +111,7 @@ func TestTektonInstallationController(t *testing.T) {
 			tektonInstallation := NewInstallation()
 			tektonConfig := newTektonConfig("applied-addons", config.InstalledStatus, "validated-pipeline")
 			cl, r := configureClient(t, tektonInstallation,
-				NewSubscription(SubscriptionNamespace),
+				NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()),
 				tektonConfig)
 			r.watchTektonConfig = func() error {
 				return nil
@@ -101,7 +132,7 @@ func TestTektonInstallationController(t *testing.T) {
 			tektonInstallation := NewInstallation()
 			tektonConfig := newTektonConfig(config.InstallingStatus)
 			cl, r := configureClient(t, tektonInstallation,
-				NewSubscription(SubscriptionNamespace),
+				NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()),
 				tektonConfig)
 			r.watchTektonConfig = func() error {
 				return nil
@@ -113,7 +144,7 @@ func TestTektonInstallationController(t *testing.T) {
 
 			// then
 			require.NoError(t, err)
-			AssertThatSubscription(t, SubscriptionNamespace, SubscriptionName, cl).Exists()
+			AssertThatSubscription(t, cfg.GetTektonSubscriptionNamespace(), SubscriptionName, cl).Exists()
 			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
 				HasConditions(Installing("tektoninstallation test"))
 		})
@@ -123,7 +154,7 @@ func TestTektonInstallationController(t *testing.T) {
 			tektonInstallation := NewInstallation()
 			tektonConfig := newTektonConfig(config.ErrorStatus)
 			cl, r := configureClient(t, tektonInstallation,
-				NewSubscription(SubscriptionNamespace),
+				NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()),
 				tektonConfig)
 			r.watchTektonConfig = func() error {
 				return nil
@@ -135,7 +166,7 @@ func TestTektonInstallationController(t *testing.T) {
 
 			// then
 			require.NoError(t, err)
-			AssertThatSubscription(t, SubscriptionNamespace, SubscriptionName, cl).Exists()
+			AssertThatSubscription(t, cfg.GetTektonSubscriptionNamespace(), SubscriptionName, cl).Exists()
 			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
 				HasConditions(InstallationFailed("tektoninstallation test"))
 		})
@@ -145,7 +176,7 @@ func TestTektonInstallationController(t *testing.T) {
 			tektonInstallation := NewInstallation()
 			tektonConfig := newTektonConfig("applied-addons")
 			cl, r := configureClient(t, tektonInstallation,
-				NewSubscription(SubscriptionNamespace),
+				NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()),
 				tektonConfig)
 			r.watchTektonConfig = func() error {
 				return nil
@@ -157,16 +188,296 @@ func TestTektonInstallationController(t *testing.T) {
 
 			// then
 			require.NoError(t, err)
-			AssertThatSubscription(t, SubscriptionNamespace, SubscriptionName, cl).Exists()
+			AssertThatSubscription(t, cfg.GetTektonSubscriptionNamespace(), SubscriptionName, cl).Exists()
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasConditions(Unknown("unknown status of TektonConfig: 'applied-addons'"))
+		})
+	})
+}
+
+// TestTektonInstallationStateMachine documents the status of the TektonInstallation for each status of the TektonConfig,
+// given by the code of its most recent condition with a known code
+func TestTektonInstallationStateMachine(t *testing.T) {
+
+	type state struct {
+		codes           []config.InstallStatus
+		expectedCond    toolchainapiv1alpha1.Condition
+		expectedRequeue bool
+	}
+	states := map[string]state{
+		"installed": {
+			codes:        []config.InstallStatus{config.InstalledStatus},
+			expectedCond: InstallationSucceeded(),
+		},
+		"installed after an error": {
+			codes:        []config.InstallStatus{config.InstalledStatus, config.ErrorStatus},
+			expectedCond: InstallationSucceeded(),
+		},
+		"installing": {
+			codes:           []config.InstallStatus{config.InstallingStatus},
+			expectedCond:    Installing("tektoninstallation test"),
+			expectedRequeue: true,
+		},
+		"deploying": {
+			codes:           []config.InstallStatus{"deploying"},
+			expectedCond:    Installing("tektoninstallation test"),
+			expectedRequeue: true,
+		},
+		"validating": {
+			codes:           []config.InstallStatus{"validating", config.InstalledStatus},
+			expectedCond:    Installing("tektoninstallation test"),
+			expectedRequeue: true,
+		},
+		"error": {
+			codes:        []config.InstallStatus{config.ErrorStatus, config.InstalledStatus},
+			expectedCond: InstallationFailed("tektoninstallation test"),
+		},
+		"unknown code followed by a known code": {
+			codes:        []config.InstallStatus{"applied-addons", config.InstalledStatus},
+			expectedCond: InstallationSucceeded(),
+		},
+		"unknown code": {
+			codes:           []config.InstallStatus{"applied-addons", "validated-pipeline"},
+			expectedCond:    Unknown("unknown status of TektonConfig: 'applied-addons'"),
+			expectedRequeue: true,
+		},
+		"no status": {
+			codes:           nil,
+			expectedCond:    Unknown("TektonConfig has no status yet"),
+			expectedRequeue: true,
+		},
+	}
+
+	for name, s := range states {
+		t.Run(name, func(t *testing.T) {
+			// given
+			tektonInstallation := NewInstallation()
+			cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()), newTektonConfig(s.codes...))
+			r.watchTektonConfig = func() error {
+				return nil
+			}
+
+			// when
+			result, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, s.expectedRequeue, result.RequeueAfter > 0)
 			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
-				HasConditions(Unknown())
+				HasConditions(s.expectedCond)
 		})
+	}
+
+	t.Run("in progress without details", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonConfig := newTektonConfig("deploying")
+		tektonConfig.Status.Conditions[0].Details = ""
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()), tektonConfig)
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions(Installing("TektonConfig is deploying"))
+	})
+}
+
+func TestTektonInstallationTimeout(t *testing.T) {
+
+	newInstallationStartedAt := func(startTime time.Time) *v1alpha1.TektonInstallation {
+		tektonInstallation := NewInstallation()
+		tektonInstallation.Status.InstallStartTime = &metav1.Time{Time: startTime}
+		return tektonInstallation
+	}
+
+	t.Run("should start the installation timer", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()), newTektonConfig(config.InstallingStatus))
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, cfg.GetTektonInstallTimeout(), result.RequeueAfter)
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasInstallStartTime().
+			HasConditions(Installing("tektoninstallation test"))
+	})
+
+	t.Run("should keep the installation timer when the status was already set", func(t *testing.T) {
+		// given
+		startTime := time.Now().Add(-5 * time.Minute)
+		tektonInstallation := newInstallationStartedAt(startTime)
+		tektonInstallation.Status.Conditions = []toolchainapiv1alpha1.Condition{Installing("tektoninstallation test")}
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()), newTektonConfig("validating"))
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, result.RequeueAfter <= cfg.GetTektonInstallTimeout()-5*time.Minute)
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasInstallStartTime().
+			HasConditions(Installing("tektoninstallation test"))
+	})
+
+	for _, code := range []config.InstallStatus{config.InstallingStatus, "deploying", "applied-addons"} {
+		t.Run(fmt.Sprintf("should fail when the installation does not complete within the timeout with status '%s'", code), func(t *testing.T) {
+			// given
+			tektonInstallation := newInstallationStartedAt(time.Now().Add(-cfg.GetTektonInstallTimeout()))
+			tektonConfig := newTektonConfig(code)
+			tektonConfig.Status.Conditions[0].Details = "still in progress"
+			cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()), tektonConfig)
+			r.watchTektonConfig = func() error {
+				return nil
+			}
+
+			// when
+			result, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, reconcile.Result{}, result)
+			cond := Installing("still in progress")
+			if code == "applied-addons" {
+				cond = Unknown("unknown status of TektonConfig: 'applied-addons'")
+			}
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasInstallStartTime().
+				HasConditions(InstallationFailed(fmt.Sprintf("the installation did not complete within %s: %s", cfg.GetTektonInstallTimeout(), cond.Message)))
+		})
+	}
+
+	t.Run("should succeed and reset the timer once installed after the timeout", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationStartedAt(time.Now().Add(-2 * cfg.GetTektonInstallTimeout()))
+		tektonInstallation.Status.Conditions = []toolchainapiv1alpha1.Condition{InstallationFailed("the installation did not complete")}
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()), newTektonConfig(config.InstalledStatus))
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasNoInstallStartTime().
+			HasConditions(InstallationSucceeded())
+	})
+
+	t.Run("should reset the timer when the TektonConfig reports an error", func(t *testing.T) {
+		// given
+		tektonInstallation := newInstallationStartedAt(time.Now().Add(-time.Minute))
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()), newTektonConfig(config.ErrorStatus))
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, reconcile.Result{}, result)
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasNoInstallStartTime().
+			HasConditions(InstallationFailed("tektoninstallation test"))
+	})
+}
+
+func TestTektonVersions(t *testing.T) {
+
+	t.Run("should report the versions of the installed components", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonConfig := newTektonConfig(config.InstalledStatus)
+		tektonConfig.Spec.TargetNamespace = "openshift-pipelines"
+		tektonConfig.Status.Conditions[0].Version = "v0.11.3"
+		sub := NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace())
+		sub.Status.InstalledCSV = cfg.GetTektonStartingCSV()
+		csv := newCSV(t, sub.Namespace, sub.Status.InstalledCSV, "1.0.1")
+		cl, r := configureClient(t, tektonInstallation, sub, csv, tektonConfig)
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasVersions("v0.11.3", "1.0.1", "openshift-pipelines").
+			HasConditions(InstallationSucceeded())
+	})
+
+	t.Run("should not report the operator version when the CSV is not installed yet", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonConfig := newTektonConfig(config.InstallingStatus)
+		sub := NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace())
+		sub.Status.InstalledCSV = cfg.GetTektonStartingCSV()
+		cl, r := configureClient(t, tektonInstallation, sub, tektonConfig)
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasVersions("", "", "")
+	})
+
+	t.Run("should fail when the CSV cannot be read", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonConfig := newTektonConfig(config.InstalledStatus)
+		sub := NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace())
+		sub.Status.InstalledCSV = cfg.GetTektonStartingCSV()
+		cl, r := configureClient(t, tektonInstallation, sub, tektonConfig)
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+		cl.MockGet = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+			if _, ok := obj.(*olmv1alpha1.ClusterServiceVersion); ok {
+				return errors.New("unable to get CSV")
+			}
+			return cl.Client.Get(ctx, key, obj)
+		}
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.EqualError(t, err, "failed to get the versions of the Tekton components: unable to get CSV")
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions(InstallationFailed("unable to get CSV"))
 	})
 }
 
 func TestFailingStatusForTektonInstallation(t *testing.T) {
 	// given
-	tektonSub := NewSubscription(SubscriptionNamespace)
+	tektonSub := NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace())
 
 	tektonInstallation := NewInstallation()
 	cl, r := configureClient(t, tektonInstallation)
@@ -202,7 +513,7 @@ func TestCreateSubscriptionForTekton(t *testing.T) {
 		tektonSubNs := generateName("tekton-op")
 		tektonInstallation := NewInstallation()
 		cl, r := configureClient(t, tektonInstallation)
-		tektonSub := NewSubscription(tektonSubNs)
+		tektonSub := NewSubscription(cfg, tektonSubNs)
 
 		// when
 		created, err := r.ensureTektonSubscription(testLogger, tektonInstallation, tektonSubNs)
@@ -225,7 +536,7 @@ func TestCreateSubscriptionForTekton(t *testing.T) {
 		cl.MockCreate = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
 			return errors.New(errMsg)
 		}
-		tektonSub := NewSubscription(tektonSubNs)
+		tektonSub := NewSubscription(cfg, tektonSubNs)
 
 		// when
 		created, err := r.ensureTektonSubscription(testLogger, tektonInstallation, tektonSubNs)
@@ -242,7 +553,7 @@ func TestCreateSubscriptionForTekton(t *testing.T) {
 		// given
 		tektonSubNs := generateName("tekton-op")
 		tektonInstallation := NewInstallation()
-		tektonSub := NewSubscription(tektonSubNs)
+		tektonSub := NewSubscription(cfg, tektonSubNs)
 		cl, r := configureClient(t, tektonInstallation, tektonSub)
 
 		// when
@@ -258,6 +569,257 @@ func TestCreateSubscriptionForTekton(t *testing.T) {
 	})
 }
 
+func TestClusterProxyForTekton(t *testing.T) {
+
+	t.Run("should create the subscription with the proxy env vars", func(t *testing.T) {
+		// given
+		tektonSubNs := generateName("tekton-op")
+		tektonInstallation := NewInstallation()
+		cl, r := configureClient(t, tektonInstallation, test.NewClusterProxy("http://proxy:3128", "", ""))
+
+		// when
+		created, err := r.ensureTektonSubscription(log, tektonInstallation, tektonSubNs)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, created)
+		expected := NewSubscription(cfg, tektonSubNs)
+		expected.Spec.Config.Env = []corev1.EnvVar{{Name: proxy.HTTPProxyEnvVar, Value: "http://proxy:3128"}}
+		AssertThatSubscription(t, tektonSubNs, SubscriptionName, cl).
+			HasSpec(expected.Spec)
+	})
+
+	t.Run("should update the proxy env vars of the existing subscription", func(t *testing.T) {
+		// given
+		tektonSubNs := generateName("tekton-op")
+		tektonInstallation := NewInstallation()
+		tektonSub := NewSubscription(cfg, tektonSubNs)
+		tektonSub.Spec.Config.Env = []corev1.EnvVar{{Name: proxy.HTTPProxyEnvVar, Value: "http://old-proxy:3128"}}
+		cl, r := configureClient(t, tektonInstallation, tektonSub, test.NewClusterProxy("", "http://proxy:3128", "localhost"))
+
+		// when
+		created, err := r.ensureTektonSubscription(log, tektonInstallation, tektonSubNs)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, created)
+		expected := NewSubscription(cfg, tektonSubNs)
+		expected.Spec.Config.Env = []corev1.EnvVar{
+			{Name: proxy.HTTPSProxyEnvVar, Value: "http://proxy:3128"},
+			{Name: proxy.NoProxyEnvVar, Value: "localhost"},
+		}
+		AssertThatSubscription(t, tektonSubNs, SubscriptionName, cl).
+			HasSpec(expected.Spec)
+	})
+}
+
+func TestAirGapForTekton(t *testing.T) {
+
+	mirror := &v1alpha1.CatalogSourceReference{Namespace: "openshift-marketplace", Name: "mirror"}
+
+	t.Run("should wait for the mirror CatalogSource before creating the subscription", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonInstallation.Spec.AirGap = &v1alpha1.TektonAirGap{CatalogSource: mirror}
+		cl, r := configureClient(t, tektonInstallation)
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		AssertThatSubscription(t, cfg.GetTektonSubscriptionNamespace(), SubscriptionName, cl).DoesNotExist()
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions(CatalogSourceNotReady("CatalogSource 'openshift-marketplace/mirror' not found"))
+
+		t.Run("should create the subscription once the mirror CatalogSource is ready", func(t *testing.T) {
+			// given
+			require.NoError(t, cl.Create(context.TODO(), test.NewCatalogSource(mirror.Namespace, mirror.Name, "READY")))
+
+			// when
+			_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+			// then
+			require.NoError(t, err)
+			AssertThatSubscription(t, cfg.GetTektonSubscriptionNamespace(), SubscriptionName, cl).
+				HasCatalogSource("openshift-marketplace", "mirror")
+			AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+				HasConditions(Installing("created tekton subscription"))
+		})
+	})
+
+	t.Run("should switch the existing subscription to the mirror CatalogSource", func(t *testing.T) {
+		// given
+		tektonSubNs := generateName("tekton-op")
+		tektonInstallation := NewInstallation()
+		tektonInstallation.Spec.AirGap = &v1alpha1.TektonAirGap{CatalogSource: mirror}
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, tektonSubNs))
+
+		// when
+		created, err := r.ensureTektonSubscription(log, tektonInstallation, tektonSubNs)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, created)
+		AssertThatSubscription(t, tektonSubNs, SubscriptionName, cl).
+			HasCatalogSource("openshift-marketplace", "mirror")
+	})
+}
+
+func TestManagedCatalogSourceForTekton(t *testing.T) {
+
+	t.Run("should create the CatalogSource and wait until it is ready", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonInstallation.Spec.AirGap = &v1alpha1.TektonAirGap{CatalogSource: &v1alpha1.CatalogSourceReference{Namespace: "openshift-marketplace", Name: "mirror"}}
+		tektonInstallation.Spec.CatalogSource = &v1alpha1.ManagedCatalogSource{Image: "quay.io/qe/pipelines-index:nightly", DisplayName: "Pipelines nightly"}
+		cl, r := configureClient(t, tektonInstallation)
+
+		// when
+		result, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, result.Requeue)
+		catalogSource := &olmv1alpha1.CatalogSource{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "openshift-marketplace", Name: "pipelines-toolchain-tekton-installation"}, catalogSource))
+		assert.Equal(t, "Pipelines nightly", catalogSource.Spec.DisplayName)
+		AssertThatSubscription(t, cfg.GetTektonSubscriptionNamespace(), SubscriptionName, cl).DoesNotExist()
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions(CatalogSourceNotReady("CatalogSource 'openshift-marketplace/pipelines-toolchain-tekton-installation' is not ready (state: '')"))
+	})
+
+	t.Run("should switch back to the default CatalogSource once the managed one is removed", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		sub := NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace())
+		sub.Spec.CatalogSource = ManagedCatalogSourceName(tektonInstallation)
+		cl, r := configureClient(t, tektonInstallation, sub)
+
+		// when
+		created, err := r.ensureTektonSubscription(log, tektonInstallation, sub.Namespace)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, created)
+		AssertThatSubscription(t, sub.Namespace, SubscriptionName, cl).
+			HasCatalogSource("openshift-marketplace", "redhat-operators")
+	})
+}
+
+func TestOperatorConfigForTekton(t *testing.T) {
+
+	t.Run("should update the config of the existing subscription", func(t *testing.T) {
+		// given
+		tektonSubNs := generateName("tekton-op")
+		tektonInstallation := NewInstallation()
+		tektonInstallation.Spec.OperatorConfig = &v1alpha1.OperatorConfig{
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			},
+			Env: []corev1.EnvVar{{Name: proxy.HTTPProxyEnvVar, Value: "http://other-proxy:3128"}},
+		}
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, tektonSubNs), test.NewClusterProxy("http://proxy:3128", "", ""))
+
+		// when
+		created, err := r.ensureTektonSubscription(log, tektonInstallation, tektonSubNs)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, created)
+		expected := NewSubscription(cfg, tektonSubNs)
+		expected.Spec.Config = olmv1alpha1.SubscriptionConfig{
+			Resources: *tektonInstallation.Spec.OperatorConfig.Resources,
+			Env:       []corev1.EnvVar{{Name: proxy.HTTPProxyEnvVar, Value: "http://other-proxy:3128"}}, // the env var of the operator config takes precedence
+		}
+		AssertThatSubscription(t, tektonSubNs, SubscriptionName, cl).
+			HasSpec(expected.Spec)
+	})
+}
+
+func TestCommonMetadataForTekton(t *testing.T) {
+
+	t.Run("should set and then remove the common labels and annotations of the subscription", func(t *testing.T) {
+		// given
+		tektonSubNs := generateName("tekton-op")
+		tektonInstallation := NewInstallation()
+		tektonInstallation.Spec.CommonMetadata = &v1alpha1.CommonMetadata{
+			Labels:      map[string]string{"app.kubernetes.io/part-of": "toolchain"},
+			Annotations: map[string]string{"cost-center": "1234"},
+		}
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, tektonSubNs))
+
+		// when
+		created, err := r.ensureTektonSubscription(log, tektonInstallation, tektonSubNs)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, created)
+		expectedLabels := toolchain.Labels()
+		expectedLabels["app.kubernetes.io/part-of"] = "toolchain"
+		AssertThatSubscription(t, tektonSubNs, SubscriptionName, cl).
+			HasLabels(expectedLabels).
+			HasAnnotations(map[string]string{
+				"cost-center":                            "1234",
+				toolchain.CommonLabelsAnnotationKey:      "app.kubernetes.io/part-of",
+				toolchain.CommonAnnotationsAnnotationKey: "cost-center",
+			})
+
+		t.Run("when removed from the spec", func(t *testing.T) {
+			// given
+			tektonInstallation.Spec.CommonMetadata = nil
+
+			// when
+			_, err := r.ensureTektonSubscription(log, tektonInstallation, tektonSubNs)
+
+			// then
+			require.NoError(t, err)
+			AssertThatSubscription(t, tektonSubNs, SubscriptionName, cl).
+				HasLabels(toolchain.Labels()).
+				HasAnnotations(nil)
+		})
+	})
+}
+
+func TestTrustedCABundleForTekton(t *testing.T) {
+
+	t.Run("should provision the trusted CA bundle in the target namespace", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonInstallation.Spec.TrustedCABundle = &v1alpha1.TrustedCABundle{InjectClusterBundle: true}
+		tektonConfig := newTektonConfig(config.InstalledStatus)
+		tektonConfig.Spec.TargetNamespace = "openshift-pipelines"
+		cl, r := configureClient(t, tektonInstallation, NewSubscription(cfg, cfg.GetTektonSubscriptionNamespace()), tektonConfig)
+		r.watchTektonConfig = func() error {
+			return nil
+		}
+
+		// when
+		_, err := r.Reconcile(newReconcileRequest(tektonInstallation))
+
+		// then
+		require.NoError(t, err)
+		cm := &corev1.ConfigMap{}
+		require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "openshift-pipelines", Name: cabundle.ConfigMapName}, cm))
+		assert.Equal(t, "true", cm.Labels[cabundle.InjectLabelKey])
+		AssertThatTektonInstallation(t, tektonInstallation.Namespace, tektonInstallation.Name, cl).
+			HasConditions(InstallationSucceeded())
+	})
+
+	t.Run("should reference the source ConfigMap", func(t *testing.T) {
+		// given
+		tektonInstallation := NewInstallation()
+		tektonInstallation.Spec.TrustedCABundle = &v1alpha1.TrustedCABundle{ConfigMap: &v1alpha1.ConfigMapReference{Namespace: "config", Name: "custom-ca"}}
+
+		// when
+		refs := referencedConfigMaps(tektonInstallation)
+
+		// then
+		assert.Equal(t, []types.NamespacedName{{Namespace: "config", Name: "custom-ca"}}, refs)
+	})
+}
+
 func TestEnsureWatchTektonCluster(t *testing.T) {
 
 	t.Run("add watch ok", func(t *testing.T) {
@@ -311,10 +873,14 @@ func TestEnsureWatchTektonCluster(t *testing.T) {
 	})
 }
 
+// cfg the operator configuration with the default values
+var cfg = configuration.NewConfig()
+
 func configureClient(t *testing.T, initObjs ...runtime.Object) (*test.FakeClient, *ReconcileTektonInstallation) {
 	s := apiScheme(t)
 	cl := test.NewFakeClient(t, initObjs...)
-	reconcileTektonInstallation := &ReconcileTektonInstallation{scheme: s, client: cl}
+	reconcileTektonInstallation := &ReconcileTektonInstallation{scheme: s, client: cl, config: cfg}
+	reconcileTektonInstallation.watchReferences = func(*v1alpha1.TektonInstallation, ...string) error { return nil }
 	return cl, reconcileTektonInstallation
 }
 
@@ -322,6 +888,11 @@ func apiScheme(t *testing.T) *runtime.Scheme {
 	s := scheme.Scheme
 	err := apis.AddToScheme(s)
 	require.NoError(t, err)
+	// the Tekton tasks are handled as unstructured objects, but the fake client needs their types to be registered
+	for _, kind := range []string{clusterTaskKind, taskKind} {
+		s.AddKnownTypeWithName(tektonGroupVersion.WithKind(kind), &unstructured.Unstructured{})
+		s.AddKnownTypeWithName(tektonGroupVersion.WithKind(kind+"List"), &unstructured.UnstructuredList{})
+	}
 	return s
 }
 
@@ -335,6 +906,18 @@ func generateName(prefix string) string {
 	return fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano())
 }
 
+// newCSV returns a new ClusterServiceVersion with the given version
+func newCSV(t *testing.T, ns, name, version string) *olmv1alpha1.ClusterServiceVersion {
+	csv := &olmv1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+		},
+	}
+	require.NoError(t, json.Unmarshal([]byte(`"`+version+`"`), &csv.Spec.Version))
+	return csv
+}
+
 // newTektonConfig returns a new TektonConfig with the given conditions
 func newTektonConfig(conditions ...config.InstallStatus) *config.Config {
 	var codes []config.ConfigCondition
@@ -0,0 +1,42 @@
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+const (
+	// the paths on which the manager serves the probes
+	readinessEndpoint = "/readyz"
+	livenessEndpoint  = "/healthz"
+)
+
+// ServeStandby serves the probes with the given listener while the replica waits for the leader-for-life election, ie, before the
+// manager (which serves the probes once created) exists. Hence, the liveness probe does not restart a standby replica, and the
+// readiness probe fails since the cache is not synced. The returned func stops serving the probes and closes the listener, so that
+// the manager can listen on the same address.
+func (s *Status) ServeStandby(listener net.Listener) func() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(readinessEndpoint, probeHandler(s.Ready))
+	mux.HandleFunc(livenessEndpoint, probeHandler(s.Alive))
+	server := &http.Server{Handler: mux}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error(err, "unable to serve the probes of the standby replica")
+		}
+	}()
+	return func() error {
+		return server.Shutdown(context.Background())
+	}
+}
+
+func probeHandler(check func(*http.Request) error) http.HandlerFunc {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		if err := check(req); err != nil {
+			http.Error(resp, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = resp.Write([]byte("ok"))
+	}
+}
@@ -0,0 +1,51 @@
+package health
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeStandby(t *testing.T) {
+	// given
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	status := NewStatus(DefaultMaxReconcileDuration)
+
+	// when
+	stop := status.ServeStandby(listener)
+
+	// then
+	url := "http://" + listener.Addr().String()
+	t.Run("should be alive", func(t *testing.T) {
+		resp, err := http.Get(url + livenessEndpoint)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("should not be ready", func(t *testing.T) {
+		resp, err := http.Get(url + readinessEndpoint)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "cache is not synced yet")
+	})
+
+	t.Run("should release the address once stopped", func(t *testing.T) {
+		// when
+		err := stop()
+
+		// then
+		require.NoError(t, err)
+		relistened, err := net.Listen("tcp", listener.Addr().String())
+		require.NoError(t, err)
+		require.NoError(t, relistened.Close())
+	})
+}
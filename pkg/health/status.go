@@ -0,0 +1,211 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/metrics"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DefaultMaxReconcileDuration the duration after which a reconcile loop that is still in progress is considered as stuck
+	DefaultMaxReconcileDuration = 5 * time.Minute
+	// installationResourcesCheckInterval the interval at which the replicas check that the installation resources exist
+	installationResourcesCheckInterval = 5 * time.Second
+)
+
+var log = logf.Log.WithName("health")
+
+// DefaultStatus the status of the operator used by the probes and updated by the controllers
+var DefaultStatus = NewStatus(DefaultMaxReconcileDuration)
+
+// Status keeps track of the state of the operator which is reported by the readiness and liveness probes:
+//   - the operator is ready when the cache is synced and when the installation resources exist. The leadership is not required,
+//     otherwise the new replica of a rolling update would never be ready while the old one holds the leadership (it is exposed
+//     as a metric instead)
+//   - the operator is alive as long as no reconcile loop is in progress for longer than the configured max duration
+type Status struct {
+	mu                           sync.RWMutex
+	cacheSynced                  bool
+	installationResourcesCreated bool
+	inProgress                   map[string]time.Time
+	maxReconcileDuration         time.Duration
+	now                          func() time.Time
+}
+
+// NewStatus returns a new Status which considers a reconcile loop as stuck after the given duration
+func NewStatus(maxReconcileDuration time.Duration) *Status {
+	return &Status{
+		inProgress:           map[string]time.Time{},
+		maxReconcileDuration: maxReconcileDuration,
+		now:                  time.Now,
+	}
+}
+
+// SetMaxReconcileDuration sets the duration after which a reconcile loop that is still in progress is considered as stuck
+func (s *Status) SetMaxReconcileDuration(maxReconcileDuration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxReconcileDuration = maxReconcileDuration
+}
+
+// MarkInstallationResourcesCreated records that the CheInstallation and TektonInstallation resources were created (or exist)
+func (s *Status) MarkInstallationResourcesCreated() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.installationResourcesCreated = true
+}
+
+// AddToManager adds the runnables to the manager which record that the cache was synced, that the installation resources
+// exist according to the given check (since they may have been created by the leader on another replica) and that this
+// replica became the leader
+func (s *Status) AddToManager(mgr manager.Manager, installationResourcesExist func() (bool, error)) error {
+	if err := mgr.Add(&cacheSyncedRunnable{status: s}); err != nil {
+		return err
+	}
+	if err := mgr.Add(&installationResourcesRunnable{status: s, exist: installationResourcesExist, interval: installationResourcesCheckInterval}); err != nil {
+		return err
+	}
+	return mgr.Add(&leaderRunnable{})
+}
+
+// Ready is the readiness check of the operator
+func (s *Status) Ready(_ *http.Request) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var notReady []string
+	if !s.cacheSynced {
+		notReady = append(notReady, "cache is not synced yet")
+	}
+	if !s.installationResourcesCreated {
+		notReady = append(notReady, "installation resources are not created yet")
+	}
+	if len(notReady) > 0 {
+		return errors.New(strings.Join(notReady, ", "))
+	}
+	return nil
+}
+
+// Alive is the liveness check of the operator. It fails if a reconcile loop is in progress for longer than
+// the max reconcile duration
+func (s *Status) Alive(_ *http.Request) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var stuck []string
+	for key, start := range s.inProgress {
+		if elapsed := s.now().Sub(start); elapsed > s.maxReconcileDuration {
+			stuck = append(stuck, fmt.Sprintf("%s (since %s)", key, elapsed.Round(time.Second)))
+		}
+	}
+	if len(stuck) > 0 {
+		sort.Strings(stuck)
+		return errors.Errorf("reconcile loop stuck for more than %s: %s", s.maxReconcileDuration, strings.Join(stuck, ", "))
+	}
+	return nil
+}
+
+// TrackReconciles wraps the given reconciler so that the liveness check can detect a stuck reconcile loop
+func (s *Status) TrackReconciles(controllerName string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &trackedReconciler{
+		controllerName: controllerName,
+		reconciler:     r,
+		status:         s,
+	}
+}
+
+func (s *Status) reconcileStarted(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inProgress[key] = s.now()
+}
+
+func (s *Status) reconcileDone(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inProgress, key)
+}
+
+type trackedReconciler struct {
+	controllerName string
+	reconciler     reconcile.Reconciler
+	status         *Status
+}
+
+// Reconcile delegates to the wrapped reconciler and records the time during which the reconcile loop is in progress
+func (t *trackedReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// the installation resources are cluster-scoped, hence the trimmed separator when there is no namespace
+	key := fmt.Sprintf("%s/%s", t.controllerName, strings.TrimPrefix(request.String(), "/"))
+	t.status.reconcileStarted(key)
+	defer t.status.reconcileDone(key)
+	return t.reconciler.Reconcile(request)
+}
+
+// cacheSyncedRunnable is started by the manager on all replicas once the cache is synced
+type cacheSyncedRunnable struct {
+	status *Status
+}
+
+func (r *cacheSyncedRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *cacheSyncedRunnable) Start(_ <-chan struct{}) error {
+	r.status.mu.Lock()
+	defer r.status.mu.Unlock()
+	r.status.cacheSynced = true
+	return nil
+}
+
+// installationResourcesRunnable is started by the manager on all replicas once the cache is synced, and checks at the given
+// interval that the installation resources exist, until they do
+type installationResourcesRunnable struct {
+	status   *Status
+	exist    func() (bool, error)
+	interval time.Duration
+}
+
+func (r *installationResourcesRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *installationResourcesRunnable) Start(stop <-chan struct{}) error {
+	err := wait.PollImmediateUntil(r.interval, func() (bool, error) {
+		exist, err := r.exist()
+		if err != nil {
+			// not fatal: checked again at the next interval
+			log.Error(err, "unable to check that the installation resources exist")
+			return false, nil
+		}
+		return exist, nil
+	}, stop)
+	if err == wait.ErrWaitTimeout {
+		// stopped before the installation resources exist
+		return nil
+	} else if err != nil {
+		return err
+	}
+	r.status.MarkInstallationResourcesCreated()
+	return nil
+}
+
+// leaderRunnable is started by the manager once the cache is synced and this replica is the leader
+type leaderRunnable struct{}
+
+func (r *leaderRunnable) NeedLeaderElection() bool {
+	return true
+}
+
+func (r *leaderRunnable) Start(_ <-chan struct{}) error {
+	metrics.Leader.Set(1)
+	return nil
+}
@@ -0,0 +1,163 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReady(t *testing.T) {
+
+	t.Run("should not be ready at startup", func(t *testing.T) {
+		// given
+		status := NewStatus(DefaultMaxReconcileDuration)
+
+		// when
+		err := status.Ready(nil)
+
+		// then
+		assert.EqualError(t, err, "cache is not synced yet, installation resources are not created yet")
+	})
+
+	t.Run("should not be ready until the installation resources are created", func(t *testing.T) {
+		// given
+		status := NewStatus(DefaultMaxReconcileDuration)
+		require.NoError(t, (&cacheSyncedRunnable{status: status}).Start(nil))
+
+		// when
+		err := status.Ready(nil)
+
+		// then
+		assert.EqualError(t, err, "installation resources are not created yet")
+	})
+
+	t.Run("should be ready without being the leader", func(t *testing.T) {
+		// given
+		status := NewStatus(DefaultMaxReconcileDuration)
+		require.NoError(t, (&cacheSyncedRunnable{status: status}).Start(nil))
+		status.MarkInstallationResourcesCreated()
+
+		// when
+		err := status.Ready(nil)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should be ready once the installation resources created by the leader exist", func(t *testing.T) {
+		// given
+		status := NewStatus(DefaultMaxReconcileDuration)
+		require.NoError(t, (&cacheSyncedRunnable{status: status}).Start(nil))
+		checks := 0
+		runnable := &installationResourcesRunnable{status: status, interval: time.Millisecond, exist: func() (bool, error) {
+			checks++
+			if checks == 1 {
+				return false, errors.New("mock error")
+			}
+			return checks == 3, nil
+		}}
+
+		// when
+		err := runnable.Start(make(chan struct{}))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, checks)
+		require.NoError(t, status.Ready(nil))
+	})
+
+	t.Run("should stop checking that the installation resources exist when the manager stops", func(t *testing.T) {
+		// given
+		status := NewStatus(DefaultMaxReconcileDuration)
+		stop := make(chan struct{})
+		close(stop)
+		runnable := &installationResourcesRunnable{status: status, interval: time.Millisecond, exist: func() (bool, error) {
+			return false, nil
+		}}
+
+		// when
+		err := runnable.Start(stop)
+
+		// then
+		require.NoError(t, err)
+		assert.EqualError(t, status.Ready(nil), "cache is not synced yet, installation resources are not created yet")
+	})
+
+	t.Run("only the leader runnable needs leader election", func(t *testing.T) {
+		assert.False(t, (&cacheSyncedRunnable{}).NeedLeaderElection())
+		assert.False(t, (&installationResourcesRunnable{}).NeedLeaderElection())
+		assert.True(t, (&leaderRunnable{}).NeedLeaderElection())
+	})
+}
+
+func TestAlive(t *testing.T) {
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "toolchain-workspaces-installation"}}
+
+	t.Run("should be alive when no reconcile is in progress", func(t *testing.T) {
+		// given
+		status := NewStatus(time.Minute)
+		r := status.TrackReconciles("test-controller", reconcilerFunc(func(reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, errors.New("reconcile failed")
+		}))
+
+		// when
+		_, err := r.Reconcile(request)
+
+		// then
+		require.EqualError(t, err, "reconcile failed")
+		require.NoError(t, status.Alive(nil))
+		assert.Empty(t, status.inProgress)
+	})
+
+	t.Run("should be alive while a reconcile is in progress for less than the max duration", func(t *testing.T) {
+		// given
+		status := NewStatus(time.Minute)
+		r := status.TrackReconciles("test-controller", reconcilerFunc(func(reconcile.Request) (reconcile.Result, error) {
+			// then
+			assert.NoError(t, status.Alive(nil))
+			return reconcile.Result{}, nil
+		}))
+
+		// when
+		_, err := r.Reconcile(request)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should not be alive when a reconcile is stuck", func(t *testing.T) {
+		// given
+		status := NewStatus(time.Minute)
+		now := time.Now()
+		r := status.TrackReconciles("test-controller", reconcilerFunc(func(reconcile.Request) (reconcile.Result, error) {
+			status.now = func() time.Time {
+				return now.Add(2 * time.Minute)
+			}
+			// then
+			assert.EqualError(t, status.Alive(nil), "reconcile loop stuck for more than 1m0s: test-controller/toolchain-workspaces-installation (since 2m0s)")
+			return reconcile.Result{}, nil
+		}))
+		status.now = func() time.Time {
+			return now
+		}
+
+		// when
+		_, err := r.Reconcile(request)
+
+		// then
+		require.NoError(t, err)
+		assert.NoError(t, status.Alive(nil))
+	})
+}
+
+type reconcilerFunc func(reconcile.Request) (reconcile.Result, error)
+
+func (f reconcilerFunc) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	return f(request)
+}
@@ -1,13 +1,18 @@
 package pkg
 
 import (
-	applyCl "github.com/codeready-toolchain/toolchain-common/pkg/client"
+	"context"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/controller/cheinstallation"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/controller/tektoninstallation"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -16,33 +21,64 @@ const (
 	codereadyToolchainPackageName      = "codeready-toolchain-operator"
 )
 
-// CreateInstallationResources creates both CheInstallation and TektonInstallation resources. If they already exist then it ignores it.
+// CreateInstallationResources creates the CheInstallation and TektonInstallation resources of the enabled controllers.
+// If they already exist then it ignores it.
 // Before the actual creation it also tries to get the Subscription that was created for codeready-toolchain-operator.
 // If such a subscription is found, then it sets it as ownerReference for the Installation resources. The reason is
 // that we need to remove(uninstall) the Che and Tekton operators when the codeready-toolchain-operator is being uninstalled,
 // which means their respective Subscriptions are also removed. Thanks to the garbage collector it will ensure that both
 // Che and Tekton operators will be uninstalled too.
-func CreateInstallationResources(cl client.Client, scheme *runtime.Scheme, log logr.Logger) error {
-	tektonInstallation := tektoninstallation.NewInstallation()
-	cheInstallation := cheinstallation.NewInstallation()
-
-	applyClient := applyCl.NewApplyClient(cl, scheme)
-
+func CreateInstallationResources(cl client.Client, config *configuration.Config, log logr.Logger) error {
 	// we cannot set the owner reference for the *Installation resources because fo this issue: https://issues.redhat.com/browse/CRT-454
 
 	// create the TektonInstallation resource, stop if something wrong happened
-	log.Info("Creating the Tekton installation resource")
-	if _, err := applyClient.CreateOrUpdateObject(tektonInstallation, true, nil); err != nil {
-		return errors.Wrap(err, "Failed to create the 'TektonInstallation' custom resource")
+	if config.IsControllerEnabled(configuration.TektonInstallationController) {
+		log.Info("Creating the Tekton installation resource")
+		if err := createIfMissing(cl, tektoninstallation.NewInstallation()); err != nil {
+			return errors.Wrap(err, "Failed to create the 'TektonInstallation' custom resource")
+		}
+		log.Info("Tekton Installation resource created")
 	}
-	log.Info("Tekton Installation resource created")
 
 	// create the CheInstallation resource, stop if something wrong happened
-	log.Info("Creating the Che installation resource")
-	if _, err := applyClient.CreateOrUpdateObject(cheInstallation, true, nil); err != nil {
-		return errors.Wrap(err, "Failed to create the 'CheInstallation' custom resource")
+	if config.IsControllerEnabled(configuration.CheInstallationController) {
+		log.Info("Creating the Che installation resource")
+		if err := createIfMissing(cl, cheinstallation.NewInstallation(config)); err != nil {
+			return errors.Wrap(err, "Failed to create the 'CheInstallation' custom resource")
+		}
+		log.Info("Che Installation resource created")
 	}
-	log.Info("Che Installation resource created")
 
 	return nil
 }
+
+// createIfMissing creates the given installation resource, unless it already exists: its spec holds the settings of the users,
+// which must not be reset each time a replica becomes the leader
+func createIfMissing(cl client.Client, installation runtime.Object) error {
+	if err := cl.Create(context.TODO(), installation); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// InstallationResourcesExist returns true if the CheInstallation and TektonInstallation resources of the enabled controllers exist.
+// The resources are created by the leader, which may be another replica (eg: during a rolling update).
+func InstallationResourcesExist(cl client.Reader, config *configuration.Config) (bool, error) {
+	var installations []runtime.Object
+	if config.IsControllerEnabled(configuration.TektonInstallationController) {
+		installations = append(installations, tektoninstallation.NewInstallation())
+	}
+	if config.IsControllerEnabled(configuration.CheInstallationController) {
+		installations = append(installations, cheinstallation.NewInstallation(config))
+	}
+	for _, installation := range installations {
+		name := installation.(metav1.Object).GetName()
+		if err := cl.Get(context.TODO(), types.NamespacedName{Name: name}, installation); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -1,16 +1,22 @@
 package pkg_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/codeready-toolchain/toolchain-operator/pkg"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/apis"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/controller/cheinstallation"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/controller/tektoninstallation"
 	"github.com/codeready-toolchain/toolchain-operator/test"
 	"github.com/codeready-toolchain/toolchain-operator/test/assert"
 
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
@@ -20,6 +26,7 @@ func TestCreateInstallationResources(t *testing.T) {
 	s := scheme.Scheme
 	err := apis.AddToScheme(s)
 	require.NoError(t, err)
+	config := configuration.NewConfig()
 
 	t.Run("when the CheInstallation or TektonInstallation resources are not present then it creates them", func(t *testing.T) {
 		// given
@@ -27,7 +34,7 @@ func TestCreateInstallationResources(t *testing.T) {
 		client := test.NewFakeClient(t)
 
 		// when
-		err = pkg.CreateInstallationResources(client, s, logf.Log)
+		err = pkg.CreateInstallationResources(client, config, logf.Log)
 
 		// then
 		require.NoError(t, err)
@@ -40,12 +47,12 @@ func TestCreateInstallationResources(t *testing.T) {
 	t.Run("when owner reference is not set then it doesn't add anything", func(t *testing.T) {
 		// given
 		tektonInstallation := tektoninstallation.NewInstallation()
-		cheInstallation := cheinstallation.NewInstallation()
+		cheInstallation := cheinstallation.NewInstallation(config)
 
 		client := test.NewFakeClient(t, tektonInstallation, cheInstallation)
 
 		// when
-		err = pkg.CreateInstallationResources(client, s, logf.Log)
+		err = pkg.CreateInstallationResources(client, config, logf.Log)
 
 		// then
 		require.NoError(t, err)
@@ -54,4 +61,84 @@ func TestCreateInstallationResources(t *testing.T) {
 		assert.AssertThatCheInstallation(t, "", cheinstallation.InstallationName, client).
 			HasNoOwnerRef()
 	})
+
+	t.Run("when the installation resources were modified then it keeps their spec", func(t *testing.T) {
+		// given
+		client := test.NewFakeClient(t)
+		err := pkg.CreateInstallationResources(client, config, logf.Log)
+		require.NoError(t, err)
+		tektonInstallation := &v1alpha1.TektonInstallation{}
+		require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: tektoninstallation.InstallationName}, tektonInstallation))
+		tektonInstallation.Spec.TaskBundles = []v1alpha1.TaskBundle{{Name: "standard", Embedded: tektoninstallation.DefaultTaskBundleName}}
+		require.NoError(t, client.Update(context.TODO(), tektonInstallation))
+		cheInstallation := &v1alpha1.CheInstallation{}
+		require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: cheinstallation.InstallationName}, cheInstallation))
+		cheInstallation.Spec.TLS = &v1alpha1.CheTLS{}
+		require.NoError(t, client.Update(context.TODO(), cheInstallation))
+
+		// when
+		err = pkg.CreateInstallationResources(client, config, logf.Log)
+
+		// then
+		require.NoError(t, err)
+		tektonInstallation = &v1alpha1.TektonInstallation{}
+		require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: tektoninstallation.InstallationName}, tektonInstallation))
+		require.Len(t, tektonInstallation.Spec.TaskBundles, 1)
+		cheInstallation = &v1alpha1.CheInstallation{}
+		require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: cheinstallation.InstallationName}, cheInstallation))
+		require.NotNil(t, cheInstallation.Spec.TLS)
+	})
+
+	t.Run("when a controller is disabled then it doesn't create its installation resource", func(t *testing.T) {
+		// given
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		configuration.AddFlags(flags)
+		err := flags.Parse([]string{"--controllers-enabled=" + configuration.TektonInstallationController})
+		require.NoError(t, err)
+		config, err := configuration.LoadConfig(flags)
+		require.NoError(t, err)
+		client := test.NewFakeClient(t)
+
+		// when
+		err = pkg.CreateInstallationResources(client, config, logf.Log)
+
+		// then
+		require.NoError(t, err)
+		assert.AssertThatTektonInstallation(t, "", tektoninstallation.InstallationName, client).
+			HasNoOwnerRef()
+		err = client.Get(context.TODO(), types.NamespacedName{Name: cheinstallation.InstallationName}, &v1alpha1.CheInstallation{})
+		require.True(t, errors.IsNotFound(err))
+	})
+}
+
+func TestInstallationResourcesExist(t *testing.T) {
+	// given
+	s := scheme.Scheme
+	err := apis.AddToScheme(s)
+	require.NoError(t, err)
+	config := configuration.NewConfig()
+
+	t.Run("should not exist until both installation resources are created", func(t *testing.T) {
+		// given
+		client := test.NewFakeClient(t, tektoninstallation.NewInstallation())
+
+		// when
+		exist, err := pkg.InstallationResourcesExist(client, config)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, exist)
+	})
+
+	t.Run("should exist when both installation resources are created", func(t *testing.T) {
+		// given
+		client := test.NewFakeClient(t, tektoninstallation.NewInstallation(), cheinstallation.NewInstallation(config))
+
+		// when
+		exist, err := pkg.InstallationResourcesExist(client, config)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, exist)
+	})
 }
@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+
+	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("metrics")
+
+const metricsPrefix = "toolchain_"
+
+// conditionStatuses the possible values of a condition status, in the same order as in kube-state-metrics
+var conditionStatuses = []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown}
+
+// installationCollector exposes kube-state-style metrics about the CheInstallation and TektonInstallation
+// resources. Contrary to the operator-sdk's kube-metrics, the resources are listed at the cluster scope,
+// which is required since both installation resources are cluster-scoped.
+type installationCollector struct {
+	client client.Reader
+
+	cheInfo            *prometheus.Desc
+	cheCreated         *prometheus.Desc
+	cheCondition       *prometheus.Desc
+	cheCertExpiry      *prometheus.Desc
+	tektonInfo         *prometheus.Desc
+	tektonCreated      *prometheus.Desc
+	tektonCondition    *prometheus.Desc
+	collectionFailures *prometheus.Desc
+}
+
+// blank assignment to verify that installationCollector implements prometheus.Collector
+var _ prometheus.Collector = &installationCollector{}
+
+// NewInstallationCollector returns a new prometheus.Collector which lists the CheInstallation and TektonInstallation
+// resources with the given client each time the metrics are scraped
+func NewInstallationCollector(cl client.Reader) prometheus.Collector {
+	return &installationCollector{
+		client: cl,
+		cheInfo: prometheus.NewDesc(metricsPrefix+"cheinstallation_info",
+			"Information about the CheInstallation.",
+			[]string{"cheinstallation", "namespace", "server_url"}, nil),
+		cheCreated: prometheus.NewDesc(metricsPrefix+"cheinstallation_created",
+			"Unix creation timestamp of the CheInstallation.",
+			[]string{"cheinstallation"}, nil),
+		cheCondition: prometheus.NewDesc(metricsPrefix+"cheinstallation_status_condition",
+			"The condition of the CheInstallation.",
+			[]string{"cheinstallation", "condition", "status"}, nil),
+		cheCertExpiry: prometheus.NewDesc(metricsPrefix+"cheinstallation_certificate_expiry",
+			"Unix expiry timestamp of the TLS certificate of the CheInstallation.",
+			[]string{"cheinstallation"}, nil),
+		tektonInfo: prometheus.NewDesc(metricsPrefix+"tektoninstallation_info",
+			"Information about the TektonInstallation.",
+			[]string{"tektoninstallation"}, nil),
+		tektonCreated: prometheus.NewDesc(metricsPrefix+"tektoninstallation_created",
+			"Unix creation timestamp of the TektonInstallation.",
+			[]string{"tektoninstallation"}, nil),
+		tektonCondition: prometheus.NewDesc(metricsPrefix+"tektoninstallation_status_condition",
+			"The condition of the TektonInstallation.",
+			[]string{"tektoninstallation", "condition", "status"}, nil),
+		collectionFailures: prometheus.NewDesc(metricsPrefix+"installation_metrics_collection_failed",
+			"Whether the last collection of the metrics for the given kind of installation failed.",
+			[]string{"kind"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *installationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.cheInfo
+	ch <- c.cheCreated
+	ch <- c.cheCondition
+	ch <- c.cheCertExpiry
+	ch <- c.tektonInfo
+	ch <- c.tektonCreated
+	ch <- c.tektonCondition
+	ch <- c.collectionFailures
+}
+
+// Collect implements prometheus.Collector
+func (c *installationCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectCheInstallations(ch)
+	c.collectTektonInstallations(ch)
+}
+
+func (c *installationCollector) collectCheInstallations(ch chan<- prometheus.Metric) {
+	cheInstallations := &v1alpha1.CheInstallationList{}
+	if err := c.client.List(context.TODO(), cheInstallations); err != nil {
+		log.Error(err, "unable to list the CheInstallations")
+		ch <- prometheus.MustNewConstMetric(c.collectionFailures, prometheus.GaugeValue, 1, "CheInstallation")
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.collectionFailures, prometheus.GaugeValue, 0, "CheInstallation")
+	for _, cheInstallation := range cheInstallations.Items {
+		name := cheInstallation.Name
+		ch <- prometheus.MustNewConstMetric(c.cheInfo, prometheus.GaugeValue, 1,
+			name, cheInstallation.Spec.CheOperatorSpec.Namespace, cheInstallation.Status.CheServerURL)
+		if !cheInstallation.CreationTimestamp.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.cheCreated, prometheus.GaugeValue, float64(cheInstallation.CreationTimestamp.Unix()), name)
+		}
+		if expiry := cheInstallation.Status.CertificateExpiry; expiry != nil {
+			ch <- prometheus.MustNewConstMetric(c.cheCertExpiry, prometheus.GaugeValue, float64(expiry.Unix()), name)
+		}
+		collectConditions(ch, c.cheCondition, name, cheInstallation.Status.Conditions)
+	}
+}
+
+func (c *installationCollector) collectTektonInstallations(ch chan<- prometheus.Metric) {
+	tektonInstallations := &v1alpha1.TektonInstallationList{}
+	if err := c.client.List(context.TODO(), tektonInstallations); err != nil {
+		log.Error(err, "unable to list the TektonInstallations")
+		ch <- prometheus.MustNewConstMetric(c.collectionFailures, prometheus.GaugeValue, 1, "TektonInstallation")
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.collectionFailures, prometheus.GaugeValue, 0, "TektonInstallation")
+	for _, tektonInstallation := range tektonInstallations.Items {
+		name := tektonInstallation.Name
+		ch <- prometheus.MustNewConstMetric(c.tektonInfo, prometheus.GaugeValue, 1, name)
+		if !tektonInstallation.CreationTimestamp.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.tektonCreated, prometheus.GaugeValue, float64(tektonInstallation.CreationTimestamp.Unix()), name)
+		}
+		collectConditions(ch, c.tektonCondition, name, tektonInstallation.Status.Conditions)
+	}
+}
+
+// collectConditions sends one sample per condition and per possible status, the sample of the actual status being
+// set to 1 and the others to 0 (same as the `kube_*_status_condition` metrics of kube-state-metrics)
+func collectConditions(ch chan<- prometheus.Metric, desc *prometheus.Desc, name string, conditions []toolchainv1alpha1.Condition) {
+	for _, cond := range conditions {
+		for _, status := range conditionStatuses {
+			value := 0.0
+			if cond.Status == status {
+				value = 1.0
+			}
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value,
+				name, string(cond.Type), strings.ToLower(string(status)))
+		}
+	}
+}
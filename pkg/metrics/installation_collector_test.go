@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/test"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestInstallationCollector(t *testing.T) {
+
+	creationTime := metav1.NewTime(time.Unix(1577836800, 0))
+
+	t.Run("should collect metrics of cluster-scoped installations", func(t *testing.T) {
+		// given
+		cheInstallation := &v1alpha1.CheInstallation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "che-installation",
+				CreationTimestamp: creationTime,
+			},
+			Spec: v1alpha1.CheInstallationSpec{
+				CheOperatorSpec: v1alpha1.CheOperator{
+					Namespace: "toolchain-workspaces",
+				},
+			},
+			Status: v1alpha1.CheInstallationStatus{
+				CheServerURL: "https://che.cluster",
+				Conditions: []toolchainv1alpha1.Condition{
+					{
+						Type:   v1alpha1.CheReady,
+						Status: corev1.ConditionTrue,
+						Reason: v1alpha1.InstalledReason,
+					},
+				},
+			},
+		}
+		tektonInstallation := &v1alpha1.TektonInstallation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "tekton-installation",
+				CreationTimestamp: creationTime,
+			},
+			Status: v1alpha1.TektonInstallationStatus{
+				Conditions: []toolchainv1alpha1.Condition{
+					{
+						Type:   v1alpha1.TektonReady,
+						Status: corev1.ConditionFalse,
+						Reason: v1alpha1.InstallingReason,
+					},
+				},
+			},
+		}
+		cl := test.NewFakeClient(t, cheInstallation, tektonInstallation)
+		collector := NewInstallationCollector(cl)
+
+		// when
+		err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP toolchain_cheinstallation_created Unix creation timestamp of the CheInstallation.
+# TYPE toolchain_cheinstallation_created gauge
+toolchain_cheinstallation_created{cheinstallation="che-installation"} 1.5778368e+09
+# HELP toolchain_cheinstallation_info Information about the CheInstallation.
+# TYPE toolchain_cheinstallation_info gauge
+toolchain_cheinstallation_info{cheinstallation="che-installation",namespace="toolchain-workspaces",server_url="https://che.cluster"} 1
+# HELP toolchain_cheinstallation_status_condition The condition of the CheInstallation.
+# TYPE toolchain_cheinstallation_status_condition gauge
+toolchain_cheinstallation_status_condition{cheinstallation="che-installation",condition="CheReady",status="false"} 0
+toolchain_cheinstallation_status_condition{cheinstallation="che-installation",condition="CheReady",status="true"} 1
+toolchain_cheinstallation_status_condition{cheinstallation="che-installation",condition="CheReady",status="unknown"} 0
+# HELP toolchain_installation_metrics_collection_failed Whether the last collection of the metrics for the given kind of installation failed.
+# TYPE toolchain_installation_metrics_collection_failed gauge
+toolchain_installation_metrics_collection_failed{kind="CheInstallation"} 0
+toolchain_installation_metrics_collection_failed{kind="TektonInstallation"} 0
+# HELP toolchain_tektoninstallation_created Unix creation timestamp of the TektonInstallation.
+# TYPE toolchain_tektoninstallation_created gauge
+toolchain_tektoninstallation_created{tektoninstallation="tekton-installation"} 1.5778368e+09
+# HELP toolchain_tektoninstallation_info Information about the TektonInstallation.
+# TYPE toolchain_tektoninstallation_info gauge
+toolchain_tektoninstallation_info{tektoninstallation="tekton-installation"} 1
+# HELP toolchain_tektoninstallation_status_condition The condition of the TektonInstallation.
+# TYPE toolchain_tektoninstallation_status_condition gauge
+toolchain_tektoninstallation_status_condition{condition="TektonReady",status="false",tektoninstallation="tekton-installation"} 1
+toolchain_tektoninstallation_status_condition{condition="TektonReady",status="true",tektoninstallation="tekton-installation"} 0
+toolchain_tektoninstallation_status_condition{condition="TektonReady",status="unknown",tektoninstallation="tekton-installation"} 0
+`))
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should collect the expiry of the TLS certificate", func(t *testing.T) {
+		// given
+		expiry := metav1.NewTime(time.Unix(1609459200, 0))
+		cheInstallation := &v1alpha1.CheInstallation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "che-installation",
+			},
+			Status: v1alpha1.CheInstallationStatus{
+				CertificateExpiry: &expiry,
+				Conditions: []toolchainv1alpha1.Condition{
+					{
+						Type:   v1alpha1.CertificateExpiringSoon,
+						Status: corev1.ConditionTrue,
+						Reason: v1alpha1.CertificateExpiringReason,
+					},
+				},
+			},
+		}
+		cl := test.NewFakeClient(t, cheInstallation)
+		collector := NewInstallationCollector(cl)
+
+		// when
+		err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP toolchain_cheinstallation_certificate_expiry Unix expiry timestamp of the TLS certificate of the CheInstallation.
+# TYPE toolchain_cheinstallation_certificate_expiry gauge
+toolchain_cheinstallation_certificate_expiry{cheinstallation="che-installation"} 1.6094592e+09
+# HELP toolchain_cheinstallation_status_condition The condition of the CheInstallation.
+# TYPE toolchain_cheinstallation_status_condition gauge
+toolchain_cheinstallation_status_condition{cheinstallation="che-installation",condition="CertificateExpiringSoon",status="false"} 0
+toolchain_cheinstallation_status_condition{cheinstallation="che-installation",condition="CertificateExpiringSoon",status="true"} 1
+toolchain_cheinstallation_status_condition{cheinstallation="che-installation",condition="CertificateExpiringSoon",status="unknown"} 0
+`), "toolchain_cheinstallation_certificate_expiry", "toolchain_cheinstallation_status_condition")
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should collect no installation metrics when there is no installation", func(t *testing.T) {
+		// given
+		cl := test.NewFakeClient(t)
+		collector := NewInstallationCollector(cl)
+
+		// when
+		count := testutil.CollectAndCount(collector)
+
+		// then
+		assert.Equal(t, 2, count) // only the collection failure metrics
+	})
+
+	t.Run("should report collection failure when listing failed", func(t *testing.T) {
+		// given
+		cl := test.NewFakeClient(t, &v1alpha1.TektonInstallation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "tekton-installation",
+			},
+		})
+		cl.MockList = func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+			if _, ok := list.(*v1alpha1.CheInstallationList); ok {
+				return errors.New("something went wrong while listing CheInstallations")
+			}
+			return cl.Client.List(ctx, list, opts...)
+		}
+		collector := NewInstallationCollector(cl)
+
+		// when
+		err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP toolchain_installation_metrics_collection_failed Whether the last collection of the metrics for the given kind of installation failed.
+# TYPE toolchain_installation_metrics_collection_failed gauge
+toolchain_installation_metrics_collection_failed{kind="CheInstallation"} 1
+toolchain_installation_metrics_collection_failed{kind="TektonInstallation"} 0
+# HELP toolchain_tektoninstallation_info Information about the TektonInstallation.
+# TYPE toolchain_tektoninstallation_info gauge
+toolchain_tektoninstallation_info{tektoninstallation="tekton-installation"} 1
+`))
+
+		// then
+		require.NoError(t, err)
+	})
+}
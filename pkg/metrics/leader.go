@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Leader is set to 1 once this replica of the operator became the leader. The leadership is exposed as a metric rather than
+// in the readiness probe, so that a replica which waits for the leadership (eg: during a rolling update) can still be ready.
+// It is registered in the registry of the controller-runtime, and hence served along with the metrics of the controllers.
+var Leader = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: metricsPrefix + "operator_leader",
+	Help: "Whether this replica of the operator is the leader.",
+})
+
+func init() {
+	crmetrics.Registry.MustRegister(Leader)
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// CRMetricsPath the path on which the custom resource metrics are served
+const CRMetricsPath = "/metrics"
+
+// CRMetricsServer serves the metrics about the installation custom resources.
+// It is meant to be added to the manager, so it's started only once the cache is synced
+type CRMetricsServer struct {
+	address  string
+	registry *prometheus.Registry
+}
+
+// blank assignments to verify that CRMetricsServer can be added to the manager and runs on all the replicas
+var _ manager.Runnable = &CRMetricsServer{}
+var _ manager.LeaderElectionRunnable = &CRMetricsServer{}
+
+// NewCRMetricsServer returns a new server which exposes the metrics of the installation custom resources
+// read with the given client on http://<host>:<port>/metrics
+func NewCRMetricsServer(cl client.Reader, host string, port int32) (*CRMetricsServer, error) {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(NewInstallationCollector(cl)); err != nil {
+		return nil, err
+	}
+	return &CRMetricsServer{
+		address:  fmt.Sprintf("%s:%d", host, port),
+		registry: registry,
+	}, nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. The metrics are served by all the replicas,
+// otherwise Prometheus would get a `connection refused` when scraping the pods that are not the leader.
+func (s *CRMetricsServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable. It blocks until the stop channel is closed.
+func (s *CRMetricsServer) Start(stop <-chan struct{}) error {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(CRMetricsPath, promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info("serving custom resource metrics", "address", s.address, "path", CRMetricsPath)
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(ctx)
+	case err := <-errCh:
+		return err
+	}
+}
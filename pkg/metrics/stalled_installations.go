@@ -0,0 +1,17 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// StalledInstallations counts the number of times an installation was detected as stalled, per kind and name of installation.
+// It is registered in the registry of the controller-runtime, and hence served along with the metrics of the controllers.
+var StalledInstallations = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: metricsPrefix + "installation_stalled_total",
+	Help: "Number of times the installation was detected as stalled.",
+}, []string{"kind", "name"})
+
+func init() {
+	crmetrics.Registry.MustRegister(StalledInstallations)
+}
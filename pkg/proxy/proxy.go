@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("proxy")
+
+const (
+	// ClusterProxyName the name of the cluster-wide Proxy
+	ClusterProxyName = "cluster"
+	// HTTPProxyEnvVar the name of the env var with the URL of the proxy for the HTTP requests
+	HTTPProxyEnvVar = "HTTP_PROXY"
+	// HTTPSProxyEnvVar the name of the env var with the URL of the proxy for the HTTPS requests
+	HTTPSProxyEnvVar = "HTTPS_PROXY"
+	// NoProxyEnvVar the name of the env var with the hosts for which the proxy must not be used
+	NoProxyEnvVar = "NO_PROXY"
+)
+
+// GetClusterProxy returns the cluster-wide Proxy, or nil if there is none (eg: when the cluster is not an OpenShift cluster)
+func GetClusterProxy(cl client.Reader) (*configv1.Proxy, error) {
+	proxy := &configv1.Proxy{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Name: ClusterProxyName}, proxy); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return proxy, nil
+}
+
+// Watch adds a watch on the cluster-wide Proxy to the given controller, if the Proxy resource type exists in the cluster
+func Watch(mgr manager.Manager, c controller.Controller, eventHandler handler.EventHandler) error {
+	gvk := configv1.GroupVersion.WithKind("Proxy")
+	if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			log.Info("Proxy resource type does not exist, the proxy settings will not be propagated")
+			return nil
+		}
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &configv1.Proxy{}}, eventHandler)
+}
+
+// Env returns the env vars with the settings of the given Proxy (only the settings which are set)
+func Env(proxy *configv1.Proxy) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for _, v := range []corev1.EnvVar{
+		{Name: HTTPProxyEnvVar, Value: proxy.Status.HTTPProxy},
+		{Name: HTTPSProxyEnvVar, Value: proxy.Status.HTTPSProxy},
+		{Name: NoProxyEnvVar, Value: proxy.Status.NoProxy},
+	} {
+		if v.Value != "" {
+			env = append(env, v)
+		}
+	}
+	return env
+}
+
+// Settings the settings of the proxy, as expected by the CheCluster
+type Settings struct {
+	// URL the protocol and the hostname of the proxy
+	URL string
+	// Port the port of the proxy
+	Port string
+	// User the name of the proxy user
+	User string
+	// Password the password of the proxy user
+	Password string
+	// NonProxyHosts the hosts for which the proxy must not be used, separated with `|`
+	NonProxyHosts string
+}
+
+// GetSettings returns the settings of the given Proxy. The proxy for the HTTPS requests is used if it is set, otherwise
+// the proxy for the HTTP requests is used. Returns empty settings if the given Proxy is nil (ie, when there is no cluster-wide Proxy).
+func GetSettings(proxy *configv1.Proxy) (Settings, error) {
+	settings := Settings{}
+	if proxy == nil {
+		return settings, nil
+	}
+	proxyURL := proxy.Status.HTTPSProxy
+	if proxyURL == "" {
+		proxyURL = proxy.Status.HTTPProxy
+	}
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return settings, err
+		}
+		settings.URL = u.Scheme + "://" + u.Hostname()
+		settings.Port = u.Port()
+		if u.User != nil {
+			settings.User = u.User.Username()
+			settings.Password, _ = u.User.Password()
+		}
+	}
+	if proxy.Status.NoProxy != "" {
+		settings.NonProxyHosts = strings.Join(strings.Split(proxy.Status.NoProxy, ","), "|")
+	}
+	return settings, nil
+}
@@ -0,0 +1,249 @@
+package references
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("references")
+
+// Watcher watches the objects of a given type (eg: the Secrets) which are referenced or owned by the installations. The objects are
+// watched with an informer per namespace of these objects, instead of the cluster-wide cache of the manager which would hold all the
+// objects of the cluster, and the informer of a namespace is stopped once no installation needs it anymore. The events on the referenced
+// objects are mapped to the installations which reference them, and the events on the owned objects to their controller.
+type Watcher struct {
+	watchNamespace func(namespace string, stop <-chan struct{}) error
+	mu             sync.RWMutex
+	references     map[types.NamespacedName]map[string]bool
+	nsMu           sync.Mutex
+	// namespaces the names of the installations which need each watched namespace
+	namespaces map[string]map[string]bool
+	// stops the channels which stop the informer of each watched namespace
+	stops map[string]chan struct{}
+}
+
+// blank assignment to verify that Watcher implements manager.Runnable
+var _ manager.Runnable = &Watcher{}
+
+// NewWatcher returns a Watcher of the objects of the given type, whose events are sent to the given controller. The installations
+// which reference or own the objects are of the given (cluster-scoped) owner type.
+func NewWatcher(mgr manager.Manager, c controller.Controller, objType, ownerType runtime.Object) (*Watcher, error) {
+	w := newWatcher()
+	// the events of the informers of all the namespaces go through a single source, so that the controller holds no reference
+	// to the informers of the namespaces which are not watched anymore
+	events := make(chan event.GenericEvent)
+	src := &source.Channel{Source: events}
+	if err := c.Watch(src, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(w.ToRequests)}); err != nil {
+		return nil, err
+	}
+	if err := c.Watch(src, &handler.EnqueueRequestForOwner{IsController: true, OwnerType: ownerType}); err != nil {
+		return nil, err
+	}
+	// stops the informers of the namespaces when the manager stops
+	if err := mgr.Add(w); err != nil {
+		return nil, err
+	}
+	w.watchNamespace = func(namespace string, stop <-chan struct{}) error {
+		nsCache, err := cache.New(mgr.GetConfig(), cache.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper(), Namespace: namespace})
+		if err != nil {
+			return err
+		}
+		informer, err := nsCache.GetInformer(objType)
+		if err != nil {
+			return err
+		}
+		send := func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			object, ok := obj.(runtime.Object)
+			if !ok {
+				return
+			}
+			objMeta, err := meta.Accessor(object)
+			if err != nil {
+				return
+			}
+			select {
+			case events <- event.GenericEvent{Meta: objMeta, Object: object}:
+			case <-stop:
+			}
+		}
+		informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    send,
+			UpdateFunc: func(_, obj interface{}) { send(obj) },
+			DeleteFunc: send,
+		})
+		go func() {
+			if err := nsCache.Start(stop); err != nil {
+				log.Error(err, "Unable to watch namespace", "Namespace", namespace)
+			}
+		}()
+		return nil
+	}
+	return w, nil
+}
+
+func newWatcher() *Watcher {
+	return &Watcher{
+		references: map[types.NamespacedName]map[string]bool{},
+		namespaces: map[string]map[string]bool{},
+		stops:      map[string]chan struct{}{},
+	}
+}
+
+// Start implements manager.Runnable: it waits until the manager stops, then stops the informers of all the watched namespaces
+func (w *Watcher) Start(stop <-chan struct{}) error {
+	<-stop
+	w.nsMu.Lock()
+	defer w.nsMu.Unlock()
+	for namespace, stopNamespace := range w.stops {
+		close(stopNamespace)
+		delete(w.stops, namespace)
+		delete(w.namespaces, namespace)
+	}
+	return nil
+}
+
+// Watch replaces the objects referenced by the given installation with the given ones, and makes sure that the namespaces of these
+// objects are watched, as well as the given namespaces of the objects owned by the installation. The namespaces which were watched
+// for the installation and which are not needed by any installation anymore are not watched anymore.
+func (w *Watcher) Watch(installation string, namespaces []string, refs ...types.NamespacedName) error {
+	w.mu.Lock()
+	w.removeReferences(installation)
+	needed := map[string]bool{}
+	for _, ns := range namespaces {
+		needed[ns] = true
+	}
+	for _, ref := range refs {
+		if w.references[ref] == nil {
+			w.references[ref] = map[string]bool{}
+		}
+		w.references[ref][installation] = true
+		needed[ref.Namespace] = true
+	}
+	w.mu.Unlock()
+	delete(needed, "")
+	return w.setNamespaces(installation, needed)
+}
+
+// Remove removes the objects referenced by the given installation (eg: when the installation is deleted), and stops watching the
+// namespaces which are not needed by any installation anymore
+func (w *Watcher) Remove(installation string) {
+	w.mu.Lock()
+	w.removeReferences(installation)
+	w.mu.Unlock()
+	// no namespace is started, hence no error
+	_ = w.setNamespaces(installation, nil)
+}
+
+func (w *Watcher) removeReferences(installation string) {
+	for ref, installations := range w.references {
+		delete(installations, installation)
+		if len(installations) == 0 {
+			delete(w.references, ref)
+		}
+	}
+}
+
+// setNamespaces sets the namespaces needed by the given installation, starts watching the namespaces which were not watched yet
+// and stops watching the namespaces which are not needed by any installation anymore
+func (w *Watcher) setNamespaces(installation string, needed map[string]bool) error {
+	w.nsMu.Lock()
+	defer w.nsMu.Unlock()
+	for namespace, installations := range w.namespaces {
+		if needed[namespace] || !installations[installation] {
+			continue
+		}
+		delete(installations, installation)
+		if len(installations) == 0 {
+			log.Info("Unwatching namespace", "Namespace", namespace)
+			close(w.stops[namespace])
+			delete(w.stops, namespace)
+			delete(w.namespaces, namespace)
+		}
+	}
+	names := make([]string, 0, len(needed))
+	for namespace := range needed {
+		names = append(names, namespace)
+	}
+	sort.Strings(names)
+	for _, namespace := range names {
+		if _, watched := w.stops[namespace]; !watched {
+			log.Info("Watching namespace", "Namespace", namespace)
+			stop := make(chan struct{})
+			if err := w.watchNamespace(namespace, stop); err != nil {
+				return err
+			}
+			w.stops[namespace] = stop
+			w.namespaces[namespace] = map[string]bool{}
+		}
+		w.namespaces[namespace][installation] = true
+	}
+	return nil
+}
+
+// ToRequests maps the events on the given object to requests on the installations which reference it. The events on the objects
+// which are not referenced are ignored.
+func (w *Watcher) ToRequests(obj handler.MapObject) []reconcile.Request {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	installations := w.references[types.NamespacedName{Namespace: obj.Meta.GetNamespace(), Name: obj.Meta.GetName()}]
+	names := make([]string, 0, len(installations))
+	for name := range installations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	requests := make([]reconcile.Request, 0, len(names))
+	for _, name := range names {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+	}
+	return requests
+}
+
+// NewClient returns a client which reads the objects of the given types (eg: the Secrets and the SecretLists) with the given reader,
+// such as the API reader of the manager, and the other objects with the given client. Reading these objects with the client of the
+// manager would start a cluster-wide informer on them, which is what the Watcher avoids.
+func NewClient(cl client.Client, reader client.Reader, objTypes ...runtime.Object) client.Client {
+	types := map[reflect.Type]bool{}
+	for _, objType := range objTypes {
+		types[reflect.TypeOf(objType)] = true
+	}
+	return &splitClient{Client: cl, reader: reader, types: types}
+}
+
+type splitClient struct {
+	client.Client
+	reader client.Reader
+	types  map[reflect.Type]bool
+}
+
+func (c *splitClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if c.types[reflect.TypeOf(obj)] {
+		return c.reader.Get(ctx, key, obj)
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *splitClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	if c.types[reflect.TypeOf(list)] {
+		return c.reader.List(ctx, list, opts...)
+	}
+	return c.Client.List(ctx, list, opts...)
+}
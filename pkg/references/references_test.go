@@ -0,0 +1,196 @@
+package references
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-operator/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestWatcher(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "creds"}}
+	other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "other"}}
+	requestsFor := func(w *Watcher, secret *corev1.Secret) []reconcile.Request {
+		return w.ToRequests(handler.MapObject{Meta: secret, Object: secret})
+	}
+
+	t.Run("should map the referenced objects and watch their namespaces once", func(t *testing.T) {
+		// given
+		w, watched := newTestWatcher(nil)
+
+		// when
+		err := w.Watch("second", []string{"owned"}, types.NamespacedName{Namespace: "ns1", Name: "creds"})
+		require.NoError(t, err)
+		err = w.Watch("first", nil, types.NamespacedName{Namespace: "ns1", Name: "creds"}, types.NamespacedName{Namespace: "ns2", Name: "creds"})
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "first"}}, {NamespacedName: types.NamespacedName{Name: "second"}}}, requestsFor(w, secret))
+		assert.Empty(t, requestsFor(w, other))
+		assert.Equal(t, []string{"ns1", "owned", "ns2"}, *watched)
+	})
+
+	t.Run("should replace the references of the installation", func(t *testing.T) {
+		// given
+		w, _ := newTestWatcher(nil)
+		require.NoError(t, w.Watch("first", nil, types.NamespacedName{Namespace: "ns1", Name: "creds"}))
+
+		// when
+		err := w.Watch("first", nil, types.NamespacedName{Namespace: "ns1", Name: "other"})
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, requestsFor(w, secret))
+		assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "first"}}}, requestsFor(w, other))
+	})
+
+	t.Run("should remove the references of the installation", func(t *testing.T) {
+		// given
+		w, _ := newTestWatcher(nil)
+		require.NoError(t, w.Watch("first", nil, types.NamespacedName{Namespace: "ns1", Name: "creds"}))
+		require.NoError(t, w.Watch("second", nil, types.NamespacedName{Namespace: "ns1", Name: "creds"}))
+
+		// when
+		w.Remove("first")
+
+		// then
+		assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "second"}}}, requestsFor(w, secret))
+		w.Remove("second")
+		assert.Empty(t, w.references)
+	})
+
+	t.Run("should watch the namespace again when it failed", func(t *testing.T) {
+		// given
+		w, watched := newTestWatcher(fmt.Errorf("mock error"))
+
+		// when
+		err := w.Watch("first", nil, types.NamespacedName{Namespace: "ns1", Name: "creds"})
+
+		// then
+		require.EqualError(t, err, "mock error")
+		assert.Empty(t, w.stops)
+		w.watchNamespace = func(namespace string, _ <-chan struct{}) error {
+			*watched = append(*watched, namespace)
+			return nil
+		}
+		require.NoError(t, w.Watch("first", nil, types.NamespacedName{Namespace: "ns1", Name: "creds"}))
+		assert.Equal(t, []string{"ns1"}, *watched)
+	})
+
+	t.Run("should stop watching the namespace which is not needed anymore", func(t *testing.T) {
+		// given
+		w, _ := newTestWatcher(nil)
+		stops := map[string]<-chan struct{}{}
+		w.watchNamespace = func(namespace string, stop <-chan struct{}) error {
+			stops[namespace] = stop
+			return nil
+		}
+		require.NoError(t, w.Watch("first", []string{"team-a", "team-b"}))
+		require.NoError(t, w.Watch("second", []string{"team-b"}))
+
+		t.Run("when the namespace is deselected", func(t *testing.T) {
+			// when
+			err := w.Watch("first", []string{"team-b"})
+
+			// then
+			require.NoError(t, err)
+			assertStopped(t, stops["team-a"])
+			assertNotStopped(t, stops["team-b"])
+			assert.Len(t, w.stops, 1)
+		})
+
+		t.Run("when an installation still needs the namespace", func(t *testing.T) {
+			// when
+			w.Remove("first")
+
+			// then
+			assertNotStopped(t, stops["team-b"])
+		})
+
+		t.Run("when no installation needs the namespace", func(t *testing.T) {
+			// when
+			w.Remove("second")
+
+			// then
+			assertStopped(t, stops["team-b"])
+			assert.Empty(t, w.stops)
+			assert.Empty(t, w.namespaces)
+		})
+	})
+
+	t.Run("should stop watching the namespaces when the manager stops", func(t *testing.T) {
+		// given
+		w, _ := newTestWatcher(nil)
+		var nsStop <-chan struct{}
+		w.watchNamespace = func(_ string, stop <-chan struct{}) error {
+			nsStop = stop
+			return nil
+		}
+		require.NoError(t, w.Watch("first", []string{"team-a"}))
+		stop := make(chan struct{})
+		close(stop)
+
+		// when
+		err := w.Start(stop)
+
+		// then
+		require.NoError(t, err)
+		assertStopped(t, nsStop)
+		assert.Empty(t, w.stops)
+	})
+}
+
+func TestNewClient(t *testing.T) {
+	// given
+	cached := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cached"}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "uncached"}}
+	cl := NewClient(test.NewFakeClient(t, cached), test.NewFakeClient(t, secret), &corev1.Secret{}, &corev1.SecretList{})
+
+	// then
+	require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "uncached"}, &corev1.Secret{}))
+	require.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "cached"}, &corev1.ConfigMap{}))
+	secrets := &corev1.SecretList{}
+	require.NoError(t, cl.List(context.TODO(), secrets))
+	assert.Len(t, secrets.Items, 1)
+	configMaps := &corev1.ConfigMapList{}
+	require.NoError(t, cl.List(context.TODO(), configMaps))
+	assert.Len(t, configMaps.Items, 1)
+}
+
+func newTestWatcher(err error) (*Watcher, *[]string) {
+	w := newWatcher()
+	watched := &[]string{}
+	w.watchNamespace = func(namespace string, _ <-chan struct{}) error {
+		if err != nil {
+			return err
+		}
+		*watched = append(*watched, namespace)
+		return nil
+	}
+	return w, watched
+}
+
+func assertStopped(t *testing.T, stop <-chan struct{}) {
+	select {
+	case <-stop:
+	default:
+		assert.Fail(t, "namespace is still watched")
+	}
+}
+
+func assertNotStopped(t *testing.T, stop <-chan struct{}) {
+	select {
+	case <-stop:
+		assert.Fail(t, "namespace is not watched anymore")
+	default:
+	}
+}
@@ -0,0 +1,76 @@
+package subscription
+
+import (
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/proxy"
+
+	configv1 "github.com/openshift/api/config/v1"
+	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// SetConfig sets the config of the given Subscription from the given operator config, along with the env vars of the given
+// cluster-wide Proxy (if any). The env vars of the operator config take precedence over the proxy env vars.
+// Returns true if the config of the Subscription changed.
+func SetConfig(sub *olmv1alpha1.Subscription, operatorConfig *v1alpha1.OperatorConfig, clusterProxy *configv1.Proxy) bool {
+	expected := NewConfig(operatorConfig)
+	if clusterProxy != nil {
+		for _, env := range proxy.Env(clusterProxy) {
+			if !hasEnv(expected.Env, env.Name) {
+				expected.Env = append(expected.Env, env)
+			}
+		}
+	}
+	// the quantities of the resources are compared semantically, since they may be serialized in another format by the API server
+	if equality.Semantic.DeepEqual(sub.Spec.Config, expected) {
+		return false
+	}
+	sub.Spec.Config = expected
+	return true
+}
+
+// NewConfig returns the Subscription config which matches the given operator config
+func NewConfig(operatorConfig *v1alpha1.OperatorConfig) olmv1alpha1.SubscriptionConfig {
+	config := olmv1alpha1.SubscriptionConfig{}
+	if operatorConfig == nil {
+		return config
+	}
+	if operatorConfig.Resources != nil {
+		config.Resources = *operatorConfig.Resources.DeepCopy()
+	}
+	config.NodeSelector = copyMap(operatorConfig.NodeSelector)
+	for _, toleration := range operatorConfig.Tolerations {
+		config.Tolerations = append(config.Tolerations, *toleration.DeepCopy())
+	}
+	for _, env := range operatorConfig.Env {
+		config.Env = append(config.Env, *env.DeepCopy())
+	}
+	for _, volume := range operatorConfig.Volumes {
+		config.Volumes = append(config.Volumes, *volume.DeepCopy())
+	}
+	for _, volumeMount := range operatorConfig.VolumeMounts {
+		config.VolumeMounts = append(config.VolumeMounts, *volumeMount.DeepCopy())
+	}
+	return config
+}
+
+func hasEnv(env []corev1.EnvVar, name string) bool {
+	for _, v := range env {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
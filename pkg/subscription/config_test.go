@@ -0,0 +1,120 @@
+package subscription
+
+import (
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/proxy"
+	"github.com/codeready-toolchain/toolchain-operator/test"
+
+	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestSetConfig(t *testing.T) {
+
+	operatorConfig := &v1alpha1.OperatorConfig{
+		Resources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+		},
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+		Tolerations:  []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+		Env:          []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+		Volumes:      []corev1.Volume{{Name: "certs", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "certs"}}}}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "certs", MountPath: "/etc/certs"}},
+	}
+
+	t.Run("should set the operator config", func(t *testing.T) {
+		// given
+		sub := &olmv1alpha1.Subscription{Spec: &olmv1alpha1.SubscriptionSpec{}}
+
+		// when
+		changed := SetConfig(sub, operatorConfig, nil)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, olmv1alpha1.SubscriptionConfig{
+			Resources:    *operatorConfig.Resources,
+			NodeSelector: operatorConfig.NodeSelector,
+			Tolerations:  operatorConfig.Tolerations,
+			Env:          operatorConfig.Env,
+			Volumes:      operatorConfig.Volumes,
+			VolumeMounts: operatorConfig.VolumeMounts,
+		}, sub.Spec.Config)
+
+		t.Run("should not change anything when the config is the same", func(t *testing.T) {
+			// when
+			changed := SetConfig(sub, operatorConfig, nil)
+
+			// then
+			assert.False(t, changed)
+		})
+
+		t.Run("should not change anything when the quantities are in another format", func(t *testing.T) {
+			// given
+			config := operatorConfig.DeepCopy()
+			config.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("0.5Gi")
+
+			// when
+			changed := SetConfig(sub, config, nil)
+
+			// then
+			assert.False(t, changed)
+		})
+
+		t.Run("should remove the settings which are removed from the operator config", func(t *testing.T) {
+			// when
+			changed := SetConfig(sub, &v1alpha1.OperatorConfig{Env: operatorConfig.Env}, nil)
+
+			// then
+			assert.True(t, changed)
+			assert.Equal(t, olmv1alpha1.SubscriptionConfig{Env: operatorConfig.Env}, sub.Spec.Config)
+		})
+	})
+
+	t.Run("should add the proxy env vars after the env vars of the operator config", func(t *testing.T) {
+		// given
+		sub := &olmv1alpha1.Subscription{Spec: &olmv1alpha1.SubscriptionSpec{}}
+		config := &v1alpha1.OperatorConfig{
+			Env: []corev1.EnvVar{
+				{Name: "LOG_LEVEL", Value: "debug"},
+				{Name: proxy.NoProxyEnvVar, Value: "localhost,.internal"},
+			},
+		}
+
+		// when
+		changed := SetConfig(sub, config, test.NewClusterProxy("http://proxy:3128", "", "localhost"))
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, []corev1.EnvVar{
+			{Name: "LOG_LEVEL", Value: "debug"},
+			{Name: proxy.NoProxyEnvVar, Value: "localhost,.internal"}, // the env var of the operator config takes precedence
+			{Name: proxy.HTTPProxyEnvVar, Value: "http://proxy:3128"},
+		}, sub.Spec.Config.Env)
+
+		t.Run("should remove the proxy env vars when the proxy settings are removed", func(t *testing.T) {
+			// when
+			changed := SetConfig(sub, config, test.NewClusterProxy("", "", ""))
+
+			// then
+			assert.True(t, changed)
+			assert.Equal(t, config.Env, sub.Spec.Config.Env)
+		})
+	})
+
+	t.Run("should clear the config without operator config nor proxy", func(t *testing.T) {
+		// given
+		sub := &olmv1alpha1.Subscription{Spec: &olmv1alpha1.SubscriptionSpec{}}
+		sub.Spec.Config.NodeSelector = map[string]string{"zone": "a"}
+
+		// when
+		changed := SetConfig(sub, nil, nil)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, olmv1alpha1.SubscriptionConfig{}, sub.Spec.Config)
+	})
+}
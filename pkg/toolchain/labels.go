@@ -1,7 +1,34 @@
 package toolchain
 
+const (
+	// ProviderLabelKey the key of the label set on all the resources created by the operator
+	ProviderLabelKey = "provider"
+	// ProviderLabelValue the value of the label set on all the resources created by the operator
+	ProviderLabelValue = "toolchain-operator"
+	// OwnerLabelKey the key of the label which holds the name of the resource that owns the labelled resource
+	OwnerLabelKey = "toolchain.openshift.dev/owner"
+	// CheUserNamespaceOwnerLabelKey the key of the label which holds the name of the CheUserNamespace that owns the labelled resource.
+	// The key is qualified with the kind of the owner, so that the resources of a CheUserNamespace are never mistaken for the resources
+	// of an installation with the same name (and vice versa)
+	CheUserNamespaceOwnerLabelKey = "toolchain.openshift.dev/cheusernamespace"
+)
+
 // Labels return a map with a single label key/value to use
 // when creating the installation resources (namespace, subscription, etc.)
 func Labels() map[string]string {
-	return map[string]string{"provider": "toolchain-operator"}
+	return map[string]string{ProviderLabelKey: ProviderLabelValue}
+}
+
+// LabelsWithOwner returns the toolchain labels along with the label of the given owner, to use
+// when creating resources which belong to another resource (eg: the namespace of a user)
+func LabelsWithOwner(owner string) map[string]string {
+	return LabelsWithOwnerKey(OwnerLabelKey, owner)
+}
+
+// LabelsWithOwnerKey returns the toolchain labels along with the label of the given owner under the given key
+// (eg: `CheUserNamespaceOwnerLabelKey`)
+func LabelsWithOwnerKey(key, owner string) map[string]string {
+	labels := Labels()
+	labels[key] = owner
+	return labels
 }
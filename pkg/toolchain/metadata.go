@@ -0,0 +1,96 @@
+package toolchain
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ReservedKeyPrefix the prefix of the labels and annotations of the operator, which can't be set by the common metadata
+	ReservedKeyPrefix = "toolchain.openshift.dev/"
+	// CommonLabelsAnnotationKey the key of the annotation which holds the keys of the common labels set on a resource,
+	// so that they can be removed from the resource once they are removed from the common metadata
+	CommonLabelsAnnotationKey = ReservedKeyPrefix + "common-labels"
+	// CommonAnnotationsAnnotationKey the key of the annotation which holds the keys of the common annotations set on a resource,
+	// so that they can be removed from the resource once they are removed from the common metadata
+	CommonAnnotationsAnnotationKey = ReservedKeyPrefix + "common-annotations"
+)
+
+// SetCommonMetadata sets the labels and annotations of the given common metadata on the given resource, and removes the ones which
+// were previously set but which are not part of the common metadata anymore (all of them if the common metadata is nil).
+// The labels and annotations of the operator are never overridden. Returns true if the labels or annotations of the resource changed.
+func SetCommonMetadata(obj metav1.Object, metadata *v1alpha1.CommonMetadata) bool {
+	if metadata == nil {
+		metadata = &v1alpha1.CommonMetadata{}
+	}
+	labels := copyMap(obj.GetLabels())
+	annotations := copyMap(obj.GetAnnotations())
+	labelsChanged := setCommon(labels, annotations, CommonLabelsAnnotationKey, metadata.Labels)
+	annotationsChanged := setCommon(annotations, annotations, CommonAnnotationsAnnotationKey, metadata.Annotations)
+	if !labelsChanged && !annotationsChanged {
+		return false
+	}
+	obj.SetLabels(nilIfEmpty(labels))
+	obj.SetAnnotations(nilIfEmpty(annotations))
+	return true
+}
+
+// setCommon sets the given common entries in the target map, removes the entries which were recorded in the given annotation but which
+// are not common entries anymore, and records the keys of the common entries in the annotation. Returns true if any map changed.
+func setCommon(target, annotations map[string]string, recordKey string, common map[string]string) bool {
+	changed := false
+	for _, key := range strings.Split(annotations[recordKey], ",") {
+		if _, found := common[key]; found || key == "" || isReserved(key) {
+			continue
+		}
+		if _, found := target[key]; found {
+			delete(target, key)
+			changed = true
+		}
+	}
+	keys := make([]string, 0, len(common))
+	for key, value := range common {
+		if isReserved(key) {
+			continue
+		}
+		keys = append(keys, key)
+		if current, found := target[key]; !found || current != value {
+			target[key] = value
+			changed = true
+		}
+	}
+	sort.Strings(keys)
+	record := strings.Join(keys, ",")
+	if annotations[recordKey] != record {
+		if record == "" {
+			delete(annotations, recordKey)
+		} else {
+			annotations[recordKey] = record
+		}
+		changed = true
+	}
+	return changed
+}
+
+func isReserved(key string) bool {
+	return key == ProviderLabelKey || strings.HasPrefix(key, ReservedKeyPrefix)
+}
+
+func copyMap(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func nilIfEmpty(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,104 @@
+package toolchain
+
+import (
+	"testing"
+
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetCommonMetadata(t *testing.T) {
+
+	metadata := &v1alpha1.CommonMetadata{
+		Labels:      map[string]string{"cost-center": "1234", "app.kubernetes.io/part-of": "toolchain"},
+		Annotations: map[string]string{"openshift.io/node-selector": "node-role.kubernetes.io/infra="},
+	}
+
+	t.Run("should set the common labels and annotations", func(t *testing.T) {
+		// given
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "workspaces", Labels: Labels()}}
+
+		// when
+		changed := SetCommonMetadata(ns, metadata)
+
+		// then
+		assert.True(t, changed)
+		assert.Equal(t, map[string]string{
+			ProviderLabelKey:            ProviderLabelValue,
+			"cost-center":               "1234",
+			"app.kubernetes.io/part-of": "toolchain",
+		}, ns.Labels)
+		assert.Equal(t, map[string]string{
+			"openshift.io/node-selector":   "node-role.kubernetes.io/infra=",
+			CommonLabelsAnnotationKey:      "app.kubernetes.io/part-of,cost-center",
+			CommonAnnotationsAnnotationKey: "openshift.io/node-selector",
+		}, ns.Annotations)
+
+		t.Run("should not change the resource when the common metadata did not change", func(t *testing.T) {
+			// when
+			changed := SetCommonMetadata(ns, metadata)
+
+			// then
+			assert.False(t, changed)
+		})
+
+		t.Run("should update and remove the common labels and annotations", func(t *testing.T) {
+			// given
+			ns.Labels["other"] = "value" // not set by the common metadata, hence kept
+			updated := &v1alpha1.CommonMetadata{Labels: map[string]string{"cost-center": "5678"}}
+
+			// when
+			changed := SetCommonMetadata(ns, updated)
+
+			// then
+			assert.True(t, changed)
+			assert.Equal(t, map[string]string{
+				ProviderLabelKey: ProviderLabelValue,
+				"cost-center":    "5678",
+				"other":          "value",
+			}, ns.Labels)
+			assert.Equal(t, map[string]string{CommonLabelsAnnotationKey: "cost-center"}, ns.Annotations)
+		})
+
+		t.Run("should remove all the common labels and annotations", func(t *testing.T) {
+			// when
+			changed := SetCommonMetadata(ns, nil)
+
+			// then
+			assert.True(t, changed)
+			assert.Equal(t, map[string]string{ProviderLabelKey: ProviderLabelValue, "other": "value"}, ns.Labels)
+			assert.Nil(t, ns.Annotations)
+		})
+	})
+
+	t.Run("should not override the labels of the operator", func(t *testing.T) {
+		// given
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "workspaces", Labels: LabelsWithOwner("installation")}}
+		reserved := &v1alpha1.CommonMetadata{
+			Labels: map[string]string{ProviderLabelKey: "other", OwnerLabelKey: "other", "cost-center": "1234"},
+		}
+
+		// when
+		SetCommonMetadata(ns, reserved)
+		SetCommonMetadata(ns, nil)
+
+		// then
+		assert.Equal(t, LabelsWithOwner("installation"), ns.Labels)
+	})
+
+	t.Run("should not change a resource without common metadata", func(t *testing.T) {
+		// given
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "workspaces", Labels: Labels()}}
+
+		// when
+		changed := SetCommonMetadata(ns, nil)
+
+		// then
+		assert.False(t, changed)
+		assert.Equal(t, Labels(), ns.Labels)
+		assert.Nil(t, ns.Annotations)
+	})
+}
@@ -202,6 +202,26 @@ func (a *ToolchainAwaitility) WaitForNamespace(name string, expectedPhase v1.Nam
 	})
 }
 
+// WaitForNamespaceRecreation waits until the namespace with the given name is deleted and then recreated (ie, until the namespace
+// is active again). The timeout covers the time after which the finalizers which block the deletion of the namespace are cleared.
+func (a *ToolchainAwaitility) WaitForNamespaceRecreation(name string) error {
+	return wait.Poll(RetryInterval, CheInstallationTimeout, func() (done bool, err error) {
+		ns := &v1.Namespace{}
+		if err := a.Client.Get(context.TODO(), types.NamespacedName{Name: name}, ns); err != nil {
+			if errors.IsNotFound(err) {
+				a.T.Logf("waiting for recreation of namespace '%s'", name)
+				return false, nil
+			}
+			return false, err
+		}
+		if ns.DeletionTimestamp != nil || ns.Status.Phase != v1.NamespaceActive {
+			a.T.Logf("waiting for deletion of namespace '%s'", name)
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
 // WaitForOperatorGroup waits until there is OperatorGroup available with the given name and namespace
 func (a *ToolchainAwaitility) WaitForOperatorGroup(ns string, labels map[string]string) error {
 	return wait.Poll(RetryInterval, Timeout, func() (done bool, err error) {
@@ -47,6 +47,18 @@ func (a *CheClusterAssertion) HasNoOwnerRef() *CheClusterAssertion {
 	return a
 }
 
+func (a *CheClusterAssertion) HasLabels(labels map[string]string) *CheClusterAssertion {
+	a.Exists()
+	assert.EqualValues(a.t, labels, a.cheCluster.Labels)
+	return a
+}
+
+func (a *CheClusterAssertion) HasAnnotations(annotations map[string]string) *CheClusterAssertion {
+	a.Exists()
+	assert.EqualValues(a.t, annotations, a.cheCluster.Annotations)
+	return a
+}
+
 func (a *CheClusterAssertion) HasRunningStatus(want string) *CheClusterAssertion {
 	a.Exists()
 	assert.Equal(a.t, want, a.cheCluster.Status.CheClusterRunning)
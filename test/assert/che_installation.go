@@ -86,6 +86,14 @@ func (a *CheInstallationAssertion) HasNoCondition() *CheInstallationAssertion {
 	return a
 }
 
+// HasPhase verifies that the Che installation has the expected phase in its status
+func (a *CheInstallationAssertion) HasPhase(want string) *CheInstallationAssertion {
+	err := a.loadCheInstallationAssertion()
+	require.NoError(a.t, err)
+	assert.Equal(a.t, want, a.cheInstallation.Status.Phase)
+	return a
+}
+
 func (a *CheInstallationAssertion) HasServerURL(want string) *CheInstallationAssertion {
 	err := a.loadCheInstallationAssertion()
 	require.NoError(a.t, err)
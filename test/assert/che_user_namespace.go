@@ -0,0 +1,70 @@
+package assert
+
+import (
+	"context"
+	"testing"
+
+	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CheUserNamespaceAssertion an assertion on the CheUserNamespace
+type CheUserNamespaceAssertion struct {
+	userNamespace  *v1alpha1.CheUserNamespace
+	client         client.Client
+	namespacedName types.NamespacedName
+	t              *testing.T
+}
+
+func (a *CheUserNamespaceAssertion) loadCheUserNamespaceAssertion() error {
+	un := &v1alpha1.CheUserNamespace{}
+	err := a.client.Get(context.TODO(), a.namespacedName, un)
+	a.userNamespace = un
+	return err
+}
+
+// AssertThatCheUserNamespace returns an assertion on the CheUserNamespace
+func AssertThatCheUserNamespace(t *testing.T, name string, client client.Client) *CheUserNamespaceAssertion {
+	return &CheUserNamespaceAssertion{
+		client:         client,
+		namespacedName: types.NamespacedName{Name: name},
+		t:              t,
+	}
+}
+
+// HasFinalizer verifies that the CheUserNamespace has the expected finalizer
+func (a *CheUserNamespaceAssertion) HasFinalizer(finalizer string) *CheUserNamespaceAssertion {
+	err := a.loadCheUserNamespaceAssertion()
+	require.NoError(a.t, err)
+	assert.Contains(a.t, a.userNamespace.ObjectMeta.GetFinalizers(), finalizer)
+	return a
+}
+
+// HasNoFinalizer verifies that the CheUserNamespace has no finalizer
+func (a *CheUserNamespaceAssertion) HasNoFinalizer() *CheUserNamespaceAssertion {
+	err := a.loadCheUserNamespaceAssertion()
+	require.NoError(a.t, err)
+	assert.Empty(a.t, a.userNamespace.ObjectMeta.GetFinalizers())
+	return a
+}
+
+// HasConditions verifies that the CheUserNamespace has the expected conditions
+func (a *CheUserNamespaceAssertion) HasConditions(expected ...toolchainv1alpha1.Condition) *CheUserNamespaceAssertion {
+	err := a.loadCheUserNamespaceAssertion()
+	require.NoError(a.t, err)
+	AssertConditionsMatch(a.t, a.userNamespace.Status.Conditions, expected...)
+	return a
+}
+
+// HasNamespace verifies that the CheUserNamespace has the expected namespace in its status
+func (a *CheUserNamespaceAssertion) HasNamespace(want string) *CheUserNamespaceAssertion {
+	err := a.loadCheUserNamespaceAssertion()
+	require.NoError(a.t, err)
+	assert.Equal(a.t, want, a.userNamespace.Status.Namespace)
+	return a
+}
@@ -65,3 +65,10 @@ func (a *NamespaceAssertion) HasLabels(labels map[string]string) *NamespaceAsser
 	assert.EqualValues(a.t, a.namespace.Labels, labels)
 	return a
 }
+
+func (a *NamespaceAssertion) HasAnnotations(annotations map[string]string) *NamespaceAssertion {
+	err := a.loadNamespaceAssertion()
+	require.NoError(a.t, err)
+	assert.EqualValues(a.t, annotations, a.namespace.Annotations)
+	return a
+}
@@ -72,3 +72,19 @@ func (a *OperatorGroupAssertion) HasSpec(ogSpec olmv1.OperatorGroupSpec) *Operat
 	assert.EqualValues(a.t, a.ogList[0].Spec, ogSpec)
 	return a
 }
+
+func (a *OperatorGroupAssertion) HasLabels(labels map[string]string) *OperatorGroupAssertion {
+	err := a.loadOperatorGroupAssertion()
+	require.NoError(a.t, err)
+	require.Len(a.t, a.ogList, 1)
+	assert.EqualValues(a.t, labels, a.ogList[0].Labels)
+	return a
+}
+
+func (a *OperatorGroupAssertion) HasAnnotations(annotations map[string]string) *OperatorGroupAssertion {
+	err := a.loadOperatorGroupAssertion()
+	require.NoError(a.t, err)
+	require.Len(a.t, a.ogList, 1)
+	assert.EqualValues(a.t, annotations, a.ogList[0].Annotations)
+	return a
+}
@@ -66,3 +66,25 @@ func (a *SubscriptionAssertion) HasSpec(subscriptionSpec *olmv1alpha1.Subscripti
 	assert.EqualValues(a.t, a.subscription.Spec, subscriptionSpec)
 	return a
 }
+
+func (a *SubscriptionAssertion) HasCatalogSource(ns, name string) *SubscriptionAssertion {
+	err := a.loadSubscriptionAssertion()
+	require.NoError(a.t, err)
+	assert.Equal(a.t, ns, a.subscription.Spec.CatalogSourceNamespace)
+	assert.Equal(a.t, name, a.subscription.Spec.CatalogSource)
+	return a
+}
+
+func (a *SubscriptionAssertion) HasLabels(labels map[string]string) *SubscriptionAssertion {
+	err := a.loadSubscriptionAssertion()
+	require.NoError(a.t, err)
+	assert.EqualValues(a.t, labels, a.subscription.Labels)
+	return a
+}
+
+func (a *SubscriptionAssertion) HasAnnotations(annotations map[string]string) *SubscriptionAssertion {
+	err := a.loadSubscriptionAssertion()
+	require.NoError(a.t, err)
+	assert.EqualValues(a.t, annotations, a.subscription.Annotations)
+	return a
+}
@@ -70,6 +70,48 @@ func (a *TektonInstallationAssertion) HasConditions(expected ...toolchainv1alpha
 	return a
 }
 
+// HasPipelineNamespaces verifies that the Tekton installation has the expected pipeline namespaces in its status
+func (a *TektonInstallationAssertion) HasPipelineNamespaces(expected ...string) *TektonInstallationAssertion {
+	err := a.loadTektonInstallationAssertion()
+	require.NoError(a.t, err)
+	assert.ElementsMatch(a.t, expected, a.tektonInstallation.Status.PipelineNamespaces)
+	return a
+}
+
+// HasTaskBundles verifies that the Tekton installation has the expected task bundles in its status
+func (a *TektonInstallationAssertion) HasTaskBundles(expected ...v1alpha1.TaskBundleStatus) *TektonInstallationAssertion {
+	err := a.loadTektonInstallationAssertion()
+	require.NoError(a.t, err)
+	assert.ElementsMatch(a.t, expected, a.tektonInstallation.Status.TaskBundles)
+	return a
+}
+
+// HasVersions verifies that the Tekton installation has the expected versions and target namespace in its status
+func (a *TektonInstallationAssertion) HasVersions(pipelinesVersion, operatorVersion, targetNamespace string) *TektonInstallationAssertion {
+	err := a.loadTektonInstallationAssertion()
+	require.NoError(a.t, err)
+	assert.Equal(a.t, pipelinesVersion, a.tektonInstallation.Status.PipelinesVersion)
+	assert.Equal(a.t, operatorVersion, a.tektonInstallation.Status.OperatorVersion)
+	assert.Equal(a.t, targetNamespace, a.tektonInstallation.Status.TargetNamespace)
+	return a
+}
+
+// HasInstallStartTime verifies that the Tekton installation has a start time in its status
+func (a *TektonInstallationAssertion) HasInstallStartTime() *TektonInstallationAssertion {
+	err := a.loadTektonInstallationAssertion()
+	require.NoError(a.t, err)
+	assert.NotNil(a.t, a.tektonInstallation.Status.InstallStartTime)
+	return a
+}
+
+// HasNoInstallStartTime verifies that the Tekton installation has no start time in its status
+func (a *TektonInstallationAssertion) HasNoInstallStartTime() *TektonInstallationAssertion {
+	err := a.loadTektonInstallationAssertion()
+	require.NoError(a.t, err)
+	assert.Nil(a.t, a.tektonInstallation.Status.InstallStartTime)
+	return a
+}
+
 func assertThatContainsOwnerReference(t *testing.T, cl client.Client, references []v1.OwnerReference, sub *opsv1alpha1.Subscription) {
 	err := cl.Get(context.TODO(), types.NamespacedName{Namespace: sub.GetNamespace(), Name: sub.GetName()}, sub)
 	require.NoError(t, err)
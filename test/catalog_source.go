@@ -0,0 +1,20 @@
+package test
+
+import (
+	olmv1alpha1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewCatalogSource returns a new CatalogSource with the given state of the connection to its registry (no connection state if empty)
+func NewCatalogSource(ns, name, state string) *olmv1alpha1.CatalogSource {
+	catalogSource := &olmv1alpha1.CatalogSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+		},
+	}
+	if state != "" {
+		catalogSource.Status.GRPCConnectionState = &olmv1alpha1.GRPCConnectionState{LastObservedState: state}
+	}
+	return catalogSource
+}
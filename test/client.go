@@ -91,6 +91,13 @@ func (c *FakeClient) Update(ctx context.Context, obj runtime.Object, opts ...cli
 	return c.Client.Update(ctx, obj, opts...)
 }
 
+func (c *FakeClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if c.MockPatch != nil {
+		return c.MockPatch(ctx, obj, patch, opts...)
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
 func (c *FakeClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
 	if c.MockDelete != nil {
 		return c.MockDelete(ctx, obj, opts...)
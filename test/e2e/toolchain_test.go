@@ -8,6 +8,7 @@ import (
 	toolchainv1alpha1 "github.com/codeready-toolchain/api/pkg/apis/toolchain/v1alpha1"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/apis"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/apis/toolchain/v1alpha1"
+	"github.com/codeready-toolchain/toolchain-operator/pkg/configuration"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/controller/cheinstallation"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/controller/tektoninstallation"
 	"github.com/codeready-toolchain/toolchain-operator/pkg/toolchain"
@@ -20,6 +21,8 @@ import (
 	framework "github.com/operator-framework/operator-sdk/pkg/test"
 	"github.com/operator-framework/operator-sdk/pkg/test/e2eutil"
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -35,13 +38,14 @@ func TestToolchain(t *testing.T) {
 	// ctx, await := InitOperator(t)
 	_, await := InitOperator(t)
 	// defer ctx.Cleanup()
-	cheInstallation := cheinstallation.NewInstallation()
+	config := configuration.NewConfig()
+	cheInstallation := cheinstallation.NewInstallation(config)
 	cheOperatorNS := cheInstallation.Spec.CheOperatorSpec.Namespace
-	cheOg := cheinstallation.NewOperatorGroup(cheOperatorNS)
-	cheSub := cheinstallation.NewSubscription(cheOperatorNS)
-	cheCluster := cheinstallation.NewCheCluster(cheOperatorNS)
+	cheOg := cheinstallation.NewOperatorGroup(cheInstallation)
+	cheSub := cheinstallation.NewSubscription(config, cheOperatorNS)
+	cheCluster := cheinstallation.NewCheCluster(cheInstallation)
 	tknInstallation := tektoninstallation.NewInstallation()
-	tektonSub := tektoninstallation.NewSubscription(tektoninstallation.SubscriptionNamespace)
+	tektonSub := tektoninstallation.NewSubscription(config, config.GetTektonSubscriptionNamespace())
 
 	f := framework.Global
 
@@ -57,26 +61,32 @@ func TestToolchain(t *testing.T) {
 		checkCheResources(t, f.Client.Client, cheOperatorNS, cheOg, cheSub, cheCluster)
 	})
 
-	// TODO enable this test. The issue is Namespace when deleted, stuck in Terminating Phase
-	// t.Run("should recreate Che operator ns operatorgroup subscription when ns deleted", func(t *testing.T) {
-	// 	// given
-	// 	ns := &v1.Namespace{}
-	// 	err := f.Client.Get(context.TODO(), types.NamespacedName{Name: cheOperatorNS}, ns)
-	// 	require.NoError(t, err)
+	t.Run("should recreate Che operator ns operatorgroup subscription when ns deleted", func(t *testing.T) {
+		// given
+		// the finalizers of the toolchain resources (eg: the CheCluster) are cleared when the namespace is stuck in Terminating,
+		// otherwise the namespace would never be deleted as the Che operator is deleted along with it
+		installation, err := await.GetCheInstallation(cheInstallation.Name)
+		require.NoError(t, err)
+		installation.Spec.ClearStuckFinalizers = true
+		err = f.Client.Update(context.TODO(), installation)
+		require.NoError(t, err)
+		ns := &v1.Namespace{}
+		err = f.Client.Get(context.TODO(), types.NamespacedName{Name: cheOperatorNS}, ns)
+		require.NoError(t, err)
 
-	// 	// when
-	// 	err = f.Client.Delete(context.TODO(), ns)
+		// when
+		err = f.Client.Delete(context.TODO(), ns)
 
-	// 	// then
-	// 	require.NoError(t, err, "failed to delete Che Operator Namespace")
+		// then
+		require.NoError(t, err, "failed to delete Che Operator Namespace")
 
-	// 	err = await.WaitForNamespace(cheOperatorNS, v1.NamespaceActive)
-	// 	require.NoError(t, err)
+		err = await.WaitForNamespaceRecreation(cheOperatorNS)
+		require.NoError(t, err)
 
-	// 	err = await.WaitForCheInstallConditions(cheInstallation.Name, UntilHasCheStatusCondition(cheinstallation.InstallationSucceeded()))
-	// 	require.NoError(t, err)
-	// 	checkCheResources(t, f.Client.Client, cheOperatorNS, cheOg, cheSub, cheCluster)
-	// })
+		err = await.WaitForCheInstallConditions(cheInstallation.Name, UntilHasCheStatusCondition(cheinstallation.InstallationSucceeded()))
+		require.NoError(t, err)
+		checkCheResources(t, f.Client.Client, cheOperatorNS, cheOg, cheSub, cheCluster)
+	})
 
 	t.Run("should recreate deleted operator group for che", func(t *testing.T) {
 		// given
@@ -141,7 +151,7 @@ func TestToolchain(t *testing.T) {
 
 	t.Run("should recreate deleted subscription for tekton", func(t *testing.T) {
 		// given
-		tektonSubscription, err := await.GetSubscription(tektoninstallation.SubscriptionNamespace, tektoninstallation.SubscriptionName)
+		tektonSubscription, err := await.GetSubscription(config.GetTektonSubscriptionNamespace(), tektoninstallation.SubscriptionName)
 		require.NoError(t, err)
 
 		// when
@@ -150,7 +160,7 @@ func TestToolchain(t *testing.T) {
 		// then
 		require.NoError(t, err, "failed to delete TektonInstallation")
 
-		err = await.WaitForSubscription(tektoninstallation.SubscriptionNamespace, tektoninstallation.SubscriptionName)
+		err = await.WaitForSubscription(config.GetTektonSubscriptionNamespace(), tektoninstallation.SubscriptionName)
 		require.NoError(t, err)
 
 		err = await.WaitForTektonInstallConditions(tknInstallation.Name, UntilHasTektonStatusCondition(tektoninstallation.InstallationSucceeded()))